@@ -3,10 +3,20 @@ package main
 import (
 	"carigo/internal/application/ports"
 	"carigo/internal/application/usecases"
+	"carigo/internal/domain"
+	"carigo/internal/infrastructure/bankstatement"
+	"carigo/internal/infrastructure/pdf"
+	"carigo/internal/infrastructure/persistence/memory"
 	"carigo/internal/infrastructure/persistence/sqlite"
+	"carigo/internal/infrastructure/scheduler"
+	"carigo/internal/infrastructure/tcmb"
 	"carigo/internal/interfaces/http/handlers"
+	"context"
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -21,32 +31,134 @@ func main() {
 		port = "8080"
 	}
 
-	baseRepo, custRepo, invRepo, payRepo, allocRepo, err := sqlite.NewRepositories(dbPath)
+	overdueCheckInterval := time.Hour
+	if v := os.Getenv("OVERDUE_CHECK_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			log.Fatalf("Invalid OVERDUE_CHECK_INTERVAL: %v", err)
+		}
+		overdueCheckInterval = d
+	}
+
+	// Invoices that break the credit policy are rejected unless
+	// CREDIT_CHECK_ACTION is FLAG. A positive CREDIT_MAX_DAYS_OVERDUE also
+	// checks for invoices overdue by more than that many days.
+	maxDaysOverdue := 0
+	if v := os.Getenv("CREDIT_MAX_DAYS_OVERDUE"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			log.Fatalf("Invalid CREDIT_MAX_DAYS_OVERDUE: %v", err)
+		}
+		maxDaysOverdue = n
+	}
+	creditPolicy, err := domain.NewCreditPolicy(domain.CreditAction(strings.ToUpper(os.Getenv("CREDIT_CHECK_ACTION"))), maxDaysOverdue)
+	if err != nil {
+		log.Fatalf("Invalid credit policy: %v", err)
+	}
+
+	// BANK_CSV_PROFILES names a JSON file of extra CSV layouts for bank
+	// statement imports, keyed by profile name.
+	var csvProfiles map[string]bankstatement.CSVProfile
+	if v := os.Getenv("BANK_CSV_PROFILES"); v != "" {
+		csvProfiles, err = bankstatement.LoadCSVProfiles(v)
+		if err != nil {
+			log.Fatalf("Invalid BANK_CSV_PROFILES: %v", err)
+		}
+	}
+
+	repos, err := sqlite.NewRepositories(dbPath)
 	if err != nil {
 		log.Fatalf("Failed to init DB: %v", err)
 	}
-	
+
 	realClock := ports.RealClock{}
+	planStore := memory.NewAllocationPlanStore()
+	rateImporter := tcmb.NewFileImporter()
+	statementParser := bankstatement.NewParser(csvProfiles)
+	// COMPANY_* name the issuer printed on PDF statements and invoices.
+	companyName := os.Getenv("COMPANY_NAME")
+	if companyName == "" {
+		companyName = "CariGo"
+	}
+	pdfRenderer := pdf.NewRenderer(pdf.Company{
+		Name:      companyName,
+		Address:   os.Getenv("COMPANY_ADDRESS"),
+		TaxOffice: os.Getenv("COMPANY_TAX_OFFICE"),
+		TaxID:     os.Getenv("COMPANY_TAX_ID"),
+		Email:     os.Getenv("COMPANY_EMAIL"),
+	}, realClock)
+
+	registerPaymentUC := usecases.NewRegisterPaymentUseCase(repos.Payments, repos.Invoices, repos.Allocations, repos.Customers, repos.ExchangeRates, repos.Base, realClock)
+	allocatePaymentUC := usecases.NewAllocatePaymentManuallyUseCase(repos.Payments, repos.Invoices, repos.Allocations, repos.ExchangeRates, repos.Base, realClock)
+	unapplyAllocationUC := usecases.NewUnapplyAllocationUseCase(repos.Allocations, repos.Payments, repos.CreditNotes, repos.Invoices, repos.Base, realClock)
+	reversePaymentUC := usecases.NewReversePaymentUseCase(repos.Payments, repos.Invoices, repos.Allocations, repos.Base, realClock)
+	proposeAllocationUC := usecases.NewProposeAllocationUseCase(repos.Invoices, repos.Customers, planStore, repos.ExchangeRates, realClock)
+	confirmAllocationUC := usecases.NewConfirmAllocationUseCase(repos.Payments, repos.Invoices, repos.Allocations, planStore, repos.Base, realClock)
+	voidInvoiceUC := usecases.NewVoidInvoiceUseCase(repos.Invoices, repos.Payments, repos.CreditNotes, repos.Allocations, repos.Base, realClock)
+	createInvoiceUC := usecases.NewCreateInvoiceUseCase(repos.Invoices, repos.Customers, repos.Payments, repos.CreditNotes, repos.Allocations, repos.ExchangeRates, repos.Base, creditPolicy, realClock)
+	createCreditNoteUC := usecases.NewCreateCreditNoteUseCase(repos.CreditNotes, repos.Invoices, repos.Allocations, repos.Customers, repos.ExchangeRates, repos.Base, realClock)
+	listInvoicesUC := usecases.NewListInvoicesUseCase(repos.Invoices, realClock)
+	listPaymentsUC := usecases.NewListPaymentsUseCase(repos.Payments)
+	createExchangeRateUC := usecases.NewCreateExchangeRateUseCase(repos.ExchangeRates, realClock)
+	listExchangeRatesUC := usecases.NewListExchangeRatesUseCase(repos.ExchangeRates)
+	importExchangeRatesUC := usecases.NewImportExchangeRatesUseCase(rateImporter, repos.ExchangeRates, repos.Base)
+	exchangeDifferencesUC := usecases.NewCalculateExchangeDifferencesUseCase(repos.Allocations, repos.Invoices, repos.Payments, repos.CreditNotes, repos.ExchangeRates, repos.Base, realClock)
+	dashboardStatsUC := usecases.NewGetDashboardStatsUseCase(repos.Payments, repos.Invoices, repos.Customers, repos.OverdueEvents, realClock)
+	detectOverdueUC := usecases.NewDetectOverdueInvoicesUseCase(repos.Invoices, repos.OverdueEvents, repos.Base, realClock)
+	agingReportUC := usecases.NewGetAgingReportUseCase(repos.Invoices, repos.Customers, realClock)
+	lateInterestUC := usecases.NewCalculateLateInterestUseCase(repos.Invoices, repos.Customers, repos.Allocations, repos.Payments, repos.CreditNotes, repos.Base, realClock)
+	importBankStatementUC := usecases.NewImportBankStatementUseCase(statementParser, repos.BankLines, repos.Base, realClock)
+	listBankLinesUC := usecases.NewListBankLinesUseCase(repos.BankLines)
+	ignoreBankLineUC := usecases.NewIgnoreBankLineUseCase(repos.BankLines, realClock)
+	matchBankLinesUC := usecases.NewMatchBankLinesUseCase(repos.BankLines, repos.Customers, repos.Invoices, repos.BankMatchRules, registerPaymentUC, repos.Base, realClock)
+	postBankLineUC := usecases.NewPostBankLineUseCase(repos.BankLines, repos.Customers, repos.BankMatchRules, registerPaymentUC, repos.Base, realClock)
+	bankReviewQueueUC := usecases.NewGetBankReviewQueueUseCase(repos.BankLines, repos.Customers, repos.Invoices, repos.BankMatchRules)
 
-	registerPaymentUC := usecases.NewRegisterPaymentUseCase(payRepo, invRepo, allocRepo, baseRepo, realClock)
-	createInvoiceUC := usecases.NewCreateInvoiceUseCase(invRepo, realClock)
-	listInvoicesUC := usecases.NewListInvoicesUseCase(invRepo)
-	listPaymentsUC := usecases.NewListPaymentsUseCase(payRepo)
-	dashboardStatsUC := usecases.NewGetDashboardStatsUseCase(payRepo, invRepo, custRepo)
-	
-	createCustomerUC := usecases.NewCreateCustomerUseCase(custRepo)
-	listCustomersUC := usecases.NewListCustomersUseCase(custRepo)
-	getCustomerStatementUC := usecases.NewGetCustomerStatementUseCase(custRepo, invRepo, payRepo)
-
-	paymentHandler := handlers.NewPaymentHandler(registerPaymentUC, listPaymentsUC, listCustomersUC)
-	invoiceHandler := handlers.NewInvoiceHandler(createInvoiceUC, listInvoicesUC, listCustomersUC)
+	createCustomerUC := usecases.NewCreateCustomerUseCase(repos.Customers)
+	listCustomersUC := usecases.NewListCustomersUseCase(repos.Customers, repos.Invoices, repos.Reconciliations, creditPolicy, realClock)
+	getCustomerStatementUC := usecases.NewGetCustomerStatementUseCase(repos.Customers, repos.Invoices, repos.Payments, repos.CreditNotes, repos.Allocations, repos.Statements, creditPolicy, realClock)
+	exportCustomerStatementUC := usecases.NewExportCustomerStatementUseCase(getCustomerStatementUC, pdfRenderer)
+	exportInvoiceUC := usecases.NewExportInvoiceUseCase(repos.Invoices, repos.Customers, pdfRenderer, realClock)
+	setLateInterestRateUC := usecases.NewSetLateInterestRateUseCase(repos.Customers)
+	setPaymentTermsUC := usecases.NewSetPaymentTermsUseCase(repos.Customers)
+	setCreditLimitUC := usecases.NewSetCreditLimitUseCase(repos.Customers)
+	createReconciliationUC := usecases.NewCreateReconciliationUseCase(repos.Reconciliations, repos.Customers, repos.Statements, realClock)
+	respondReconciliationUC := usecases.NewRespondReconciliationUseCase(repos.Reconciliations, repos.Customers, realClock)
+	listReconciliationsUC := usecases.NewListReconciliationsUseCase(repos.Reconciliations, repos.Customers)
+	reconciliationDocumentUC := usecases.NewGetReconciliationDocumentUseCase(repos.Reconciliations, repos.Customers)
+
+	paymentHandler := handlers.NewPaymentHandler(registerPaymentUC, allocatePaymentUC, reversePaymentUC, listPaymentsUC, listCustomersUC, listInvoicesUC)
+	allocationHandler := handlers.NewAllocationHandler(proposeAllocationUC, confirmAllocationUC, unapplyAllocationUC)
+	invoiceHandler := handlers.NewInvoiceHandler(createInvoiceUC, voidInvoiceUC, listInvoicesUC, listCustomersUC, exportInvoiceUC)
+	creditNoteHandler := handlers.NewCreditNoteHandler(createCreditNoteUC)
+	exchangeRateHandler := handlers.NewExchangeRateHandler(createExchangeRateUC, listExchangeRatesUC, importExchangeRatesUC)
+	exchangeDifferenceHandler := handlers.NewExchangeDifferenceHandler(exchangeDifferencesUC)
 	dashboardHandler := handlers.NewDashboardHandler(dashboardStatsUC)
-	customerHandler := handlers.NewCustomerHandler(createCustomerUC, listCustomersUC, getCustomerStatementUC)
+	agingHandler := handlers.NewAgingHandler(agingReportUC)
+	lateInterestHandler := handlers.NewLateInterestHandler(lateInterestUC)
+	bankLineHandler := handlers.NewBankLineHandler(importBankStatementUC, listBankLinesUC, ignoreBankLineUC, matchBankLinesUC, postBankLineUC, bankReviewQueueUC, listCustomersUC)
+	reconciliationHandler := handlers.NewReconciliationHandler(createReconciliationUC, respondReconciliationUC, listReconciliationsUC, reconciliationDocumentUC)
+	customerHandler := handlers.NewCustomerHandler(createCustomerUC, listCustomersUC, getCustomerStatementUC, exportCustomerStatementUC, setLateInterestRateUC, setPaymentTermsUC, setCreditLimitUC)
+
+	// A non-positive interval turns the overdue check off.
+	if overdueCheckInterval > 0 {
+		scheduler.Every(context.Background(), "overdue-detection", overdueCheckInterval, func(ctx context.Context) error {
+			res, err := detectOverdueUC.Execute(ctx)
+			if err != nil {
+				return err
+			}
+			if len(res.NewEvents) > 0 {
+				log.Printf("overdue-detection: %d instalments became overdue", len(res.NewEvents))
+			}
+			return nil
+		})
+	}
 
 	r := gin.Default()
 	r.SetTrustedProxies(nil)
 
 	r.Static("/assets", "./web/assets")
+	r.SetFuncMap(handlers.TemplateFuncs())
 	r.LoadHTMLGlob("web/templates/**/*")
 
 	r.GET("/health", func(c *gin.Context) {
@@ -54,15 +166,51 @@ func main() {
 	})
 	r.GET("/", dashboardHandler.ShowDashboard)
 	r.GET("/invoices", invoiceHandler.ShowInvoices)
+	r.GET("/invoices/:id/invoice.pdf", invoiceHandler.ShowInvoicePDF)
 	r.GET("/payments", paymentHandler.ShowPayments)
 	r.GET("/customers", customerHandler.ShowCustomers)
 	r.GET("/customers/:id", customerHandler.ShowCustomerStatement)
+	r.GET("/customers/:id/statement.pdf", customerHandler.ShowStatementPDF)
+	r.GET("/aging", agingHandler.ShowAging)
+	r.GET("/bank", bankLineHandler.ShowBankLines)
+	r.GET("/reconciliations", reconciliationHandler.ShowReconciliations)
+	r.GET("/reconciliations/:id", reconciliationHandler.ShowReconciliationDocument)
 
 	api := r.Group("/api/v1")
 	{
+		api.GET("/invoices", invoiceHandler.ListInvoices)
 		api.POST("/invoices", invoiceHandler.CreateInvoice)
+		api.POST("/invoices/:id/void", invoiceHandler.VoidInvoice)
+		api.POST("/credit-notes", creditNoteHandler.CreateCreditNote)
 		api.POST("/payments", paymentHandler.RegisterPayment)
+		api.POST("/payments/:id/allocations", paymentHandler.AllocatePayment)
+		api.POST("/payments/:id/reverse", paymentHandler.ReversePayment)
+		api.POST("/allocations/:id/unapply", allocationHandler.UnapplyAllocation)
+		api.POST("/allocation-plans", allocationHandler.ProposeAllocation)
+		api.POST("/allocation-plans/:id/confirm", allocationHandler.ConfirmAllocation)
 		api.POST("/customers", customerHandler.CreateCustomer)
+		api.GET("/customers/:id/statement", customerHandler.GetCustomerStatement)
+		api.PUT("/customers/:id/late-interest", customerHandler.SetLateInterestRate)
+		api.PUT("/customers/:id/payment-terms", customerHandler.SetPaymentTerms)
+		api.PUT("/customers/:id/credit-limit", customerHandler.SetCreditLimit)
+		api.POST("/customers/:id/reconciliations", reconciliationHandler.CreateReconciliation)
+		api.GET("/reconciliations", reconciliationHandler.ListReconciliations)
+		api.GET("/reconciliations/:id", reconciliationHandler.GetReconciliationDocument)
+		api.POST("/reconciliations/:id/response", reconciliationHandler.RespondReconciliation)
+		api.GET("/exchange-rates", exchangeRateHandler.ListExchangeRates)
+		api.POST("/exchange-rates", exchangeRateHandler.CreateExchangeRate)
+		api.POST("/exchange-rates/import", exchangeRateHandler.ImportExchangeRates)
+		api.GET("/reports/exchange-differences", exchangeDifferenceHandler.GetReport)
+		api.GET("/reports/aging", agingHandler.GetReport)
+		api.POST("/exchange-differences/invoices", exchangeDifferenceHandler.IssueInvoices)
+		api.GET("/reports/late-interest", lateInterestHandler.GetReport)
+		api.POST("/late-interest/invoices", lateInterestHandler.IssueInvoices)
+		api.POST("/bank-statements", bankLineHandler.ImportBankStatement)
+		api.GET("/bank-lines", bankLineHandler.ListBankLines)
+		api.GET("/bank-lines/review", bankLineHandler.GetReviewQueue)
+		api.POST("/bank-lines/match", bankLineHandler.MatchBankLines)
+		api.POST("/bank-lines/:id/post", bankLineHandler.PostBankLine)
+		api.POST("/bank-lines/:id/ignore", bankLineHandler.IgnoreBankLine)
 	}
 
 	log.Printf("Starting server on port %s", port)
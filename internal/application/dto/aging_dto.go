@@ -0,0 +1,33 @@
+package dto
+
+import "time"
+
+// AgingReportRequest picks the day to age against (default today) and the
+// bucket edges in days past due, e.g. "30,60,90".
+type AgingReportRequest struct {
+	AsOf    time.Time `form:"as_of" time_format:"2006-01-02"`
+	Buckets string    `form:"buckets"`
+}
+
+// AgingReport buckets open receivables by days past due. Every Amounts slice
+// is aligned with Buckets.
+type AgingReport struct {
+	AsOf      time.Time       `json:"as_of"`
+	Buckets   []string        `json:"buckets"`
+	Customers []CustomerAging `json:"customers"`
+	Totals    []CurrencyAging `json:"totals"`
+}
+
+type CustomerAging struct {
+	CustomerID   string   `json:"customer_id"`
+	CustomerName string   `json:"customer_name"`
+	Currency     string   `json:"currency"`
+	Amounts      []string `json:"amounts"`
+	Total        string   `json:"total"`
+}
+
+type CurrencyAging struct {
+	Currency string   `json:"currency"`
+	Amounts  []string `json:"amounts"`
+	Total    string   `json:"total"`
+}
@@ -0,0 +1,27 @@
+package dto
+
+import "time"
+
+type AllocationPlanDTO struct {
+	PlanID            string                 `json:"plan_id"`
+	CustomerID        string                 `json:"customer_id"`
+	Amount            int64                  `json:"amount"`
+	Currency          string                 `json:"currency"`
+	Date              time.Time              `json:"date"`
+	Strategy          string                 `json:"strategy"`
+	AllocatedAmount   int64                  `json:"allocated_amount"`
+	UnallocatedAmount int64                  `json:"unallocated_amount"`
+	Lines             []PlannedAllocationDTO `json:"lines"`
+}
+
+type PlannedAllocationDTO struct {
+	InvoiceID          string `json:"invoice_id"`
+	Amount             int64  `json:"amount"`
+	RemainingDebtAfter int64  `json:"remaining_debt_after"`
+	InvoiceAmount      int64  `json:"invoice_amount,omitempty"`
+	InvoiceCurrency    string `json:"invoice_currency,omitempty"`
+	ExchangeRate       string `json:"exchange_rate,omitempty"`
+	// Discount is the early-payment discount that would settle the rest of
+	// the invoice, in the invoice currency, e.g. "12.50".
+	Discount string `json:"discount,omitempty"`
+}
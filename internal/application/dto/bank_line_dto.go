@@ -0,0 +1,98 @@
+package dto
+
+import (
+	"io"
+	"time"
+)
+
+type ImportBankStatementRequest struct {
+	// Format is CAMT053, MT940 or CSV; it is detected when empty.
+	Format string `form:"format"`
+	// Profile names the column layout of a CSV export.
+	Profile string `form:"profile"`
+	// Account is the receiving account for statements that do not name it,
+	// such as most CSV exports.
+	Account string    `form:"account"`
+	File    io.Reader `form:"-"`
+}
+
+type ImportBankStatementResponse struct {
+	Format   string `json:"format"`
+	Imported int    `json:"imported"`
+	// Skipped counts the lines imported before, which are left as they are.
+	Skipped int           `json:"skipped"`
+	Lines   []BankLineDTO `json:"lines"`
+	// Matching is the matching run that followed the import.
+	Matching *MatchBankLinesResponse `json:"matching,omitempty"`
+}
+
+type BankLineDTO struct {
+	ID          string    `json:"id"`
+	Account     string    `json:"account"`
+	Reference   string    `json:"reference"`
+	BookingDate string    `json:"booking_date"`
+	ValueDate   string    `json:"value_date"`
+	Amount      string    `json:"amount"`
+	Currency    string    `json:"currency"`
+	PayerName   string    `json:"payer_name,omitempty"`
+	PayerIBAN   string    `json:"payer_iban,omitempty"`
+	PayerTaxID  string    `json:"payer_tax_id,omitempty"`
+	Description string    `json:"description"`
+	Format      string    `json:"format"`
+	State       string    `json:"state"`
+	CustomerID  string    `json:"customer_id,omitempty"`
+	PaymentID   string    `json:"payment_id,omitempty"`
+	ImportedAt  time.Time `json:"imported_at"`
+	// Candidate is the payment the line would be registered as; its
+	// customer is empty until the line is matched.
+	Candidate RegisterPaymentRequest `json:"candidate"`
+	// Candidates are the customers who may have made the transfer, best
+	// first. They are only filled in for the review queue.
+	Candidates []BankMatchCandidateDTO `json:"candidates,omitempty"`
+}
+
+type BankMatchCandidateDTO struct {
+	CustomerID   string   `json:"customer_id"`
+	CustomerName string   `json:"customer_name"`
+	Score        int      `json:"score"`
+	Reasons      []string `json:"reasons"`
+	Invoices     []string `json:"invoices,omitempty"`
+}
+
+// MatchBankLinesResponse counts what a matching run did with the open bank
+// lines: posted them as payments, suggested a customer for review or found
+// no customer. Errors lists the lines that could not be posted.
+type MatchBankLinesResponse struct {
+	Posted    int           `json:"posted"`
+	Suggested int           `json:"suggested"`
+	Unmatched int           `json:"unmatched"`
+	Lines     []BankLineDTO `json:"lines"`
+	Errors    []string      `json:"errors,omitempty"`
+}
+
+type PostBankLineRequest struct {
+	CustomerID string `json:"customer_id" binding:"required"`
+	// RememberIBAN learns that transfers from the payer's IBAN come from
+	// the customer.
+	RememberIBAN bool `json:"remember_iban"`
+}
+
+type PostBankLineResponse struct {
+	Line    BankLineDTO              `json:"line"`
+	Payment *RegisterPaymentResponse `json:"payment"`
+	// Rule is the rule learned from the line, if any.
+	Rule *BankMatchRuleDTO `json:"rule,omitempty"`
+}
+
+type BankMatchRuleDTO struct {
+	IBAN       string    `json:"iban"`
+	CustomerID string    `json:"customer_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// BankReviewQueue is the open bank lines with their candidates, and the
+// rules they were matched with.
+type BankReviewQueue struct {
+	Lines []BankLineDTO      `json:"lines"`
+	Rules []BankMatchRuleDTO `json:"rules"`
+}
@@ -0,0 +1,19 @@
+package dto
+
+import "time"
+
+type CreateCreditNoteRequest struct {
+	CustomerID string    `json:"customer_id" binding:"required"`
+	InvoiceID  string    `json:"invoice_id"`
+	Amount     int64     `json:"amount" binding:"required,gt=0"`
+	Currency   string    `json:"currency" binding:"required,len=3"`
+	Date       time.Time `json:"date"`
+	Reason     string    `json:"reason" binding:"required"`
+}
+
+type CreateCreditNoteResponse struct {
+	CreditNoteID      string                   `json:"credit_note_id"`
+	AllocatedAmount   int64                    `json:"allocated_amount"`
+	RemainingBalance  int64                    `json:"remaining_balance"`
+	AllocatedInvoices []AllocatedInvoiceParams `json:"allocated_invoices"`
+}
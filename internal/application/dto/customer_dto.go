@@ -3,9 +3,35 @@ package dto
 import "time"
 
 type CreateCustomerRequest struct {
-	Name  string `json:"name" binding:"required"`
-	Email string `json:"email" binding:"required,email"`
-	TaxID string `json:"tax_id" binding:"required"`
+	Name               string `json:"name" binding:"required"`
+	Email              string `json:"email" binding:"required,email"`
+	TaxID              string `json:"tax_id" binding:"required"`
+	AllocationStrategy string `json:"allocation_strategy"`
+	// LateInterestRate is a percentage such as "3.5" per LateInterestPeriod
+	// (MONTHLY or ANNUAL); empty charges no late interest.
+	LateInterestRate   string `json:"late_interest_rate"`
+	LateInterestPeriod string `json:"late_interest_period"`
+	// PaymentTerms are the default terms of the customer's invoices.
+	PaymentTerms *PaymentTermsRequest `json:"payment_terms"`
+	// CreditLimits caps the customer's open invoices, in minor units per
+	// currency code.
+	CreditLimits map[string]int64 `json:"credit_limits"`
+}
+
+// PaymentTermsRequest describes how an invoice falls due. Basis is NET
+// (NetDays after the invoice date), EOM (NetDays after the end of its month)
+// or NEXT_MONTH (on DayOfMonth of the next month). Instalments splits it into
+// monthly instalments; BusinessDays moves due dates off weekends and public
+// holidays. DiscountPercent off is granted for payment within DiscountDays,
+// e.g. "2" and 10 for "2/10 net 30".
+type PaymentTermsRequest struct {
+	Basis           string `json:"basis"`
+	NetDays         int    `json:"net_days"`
+	DayOfMonth      int    `json:"day_of_month"`
+	Instalments     int    `json:"instalments"`
+	BusinessDays    bool   `json:"business_days"`
+	DiscountPercent string `json:"discount_percent"`
+	DiscountDays    int    `json:"discount_days"`
 }
 
 type CreateCustomerResponse struct {
@@ -15,9 +41,36 @@ type CreateCustomerResponse struct {
 }
 
 type CustomerDTO struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	Email     string    `json:"email"`
-	TaxID     string    `json:"tax_id"`
-	CreatedAt time.Time `json:"created_at"`
+	ID                 string    `json:"id"`
+	Name               string    `json:"name"`
+	Email              string    `json:"email"`
+	TaxID              string    `json:"tax_id"`
+	AllocationStrategy string    `json:"allocation_strategy"`
+	LateInterestRate   string    `json:"late_interest_rate,omitempty"`
+	LateInterestPeriod string    `json:"late_interest_period,omitempty"`
+	PaymentTerms       string    `json:"payment_terms,omitempty"`
+	CreatedAt          time.Time `json:"created_at"`
+	// CreditLimits are decimal amounts per currency code.
+	CreditLimits map[string]string `json:"credit_limits,omitempty"`
+	// Risk is filled in where the customer's open invoices are looked at.
+	Risk *CreditRiskDTO `json:"risk,omitempty"`
+	// Reconciliation is the customer's latest balance confirmation, where
+	// customers are listed.
+	Reconciliation *ReconciliationDTO `json:"reconciliation,omitempty"`
+}
+
+// CreditRiskDTO is where a customer stands against their credit limits and
+// the allowed days overdue. Exposure is the open amount per currency.
+type CreditRiskDTO struct {
+	Risky           bool              `json:"risky"`
+	Exposure        map[string]string `json:"exposure"`
+	OverLimit       []string          `json:"over_limit"`
+	OverdueInvoices []string          `json:"overdue_invoices"`
+}
+
+// SetCreditLimitRequest changes the credit limit in one currency. Limit is in
+// minor units; zero removes the limit.
+type SetCreditLimitRequest struct {
+	Currency string `json:"currency" binding:"required,len=3"`
+	Limit    int64  `json:"limit" binding:"min=0"`
 }
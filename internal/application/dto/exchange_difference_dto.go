@@ -0,0 +1,60 @@
+package dto
+
+import "time"
+
+// ExchangeDifferenceRequest selects allocations by customer and by the day
+// they were settled. Empty fields do not filter.
+type ExchangeDifferenceRequest struct {
+	CustomerID string    `json:"customer_id" form:"customer_id"`
+	From       time.Time `json:"from" form:"from" time_format:"2006-01-02"`
+	To         time.Time `json:"to" form:"to" time_format:"2006-01-02"`
+	// IssueInvoices bills every customer's uninvoiced gains with a kur farkı invoice.
+	IssueInvoices bool `json:"issue_invoices"`
+	// DueDate of the issued invoices; defaults to the issue date.
+	DueDate time.Time `json:"due_date"`
+}
+
+type ExchangeDifferenceReport struct {
+	Items     []ExchangeDifferenceItem    `json:"items"`
+	Customers []CustomerExchangeSummary   `json:"customers"`
+	Invoices  []ExchangeDifferenceInvoice `json:"issued_invoices"`
+	// Unpriced lists allocations left out because a TRY rate was missing.
+	Unpriced []string `json:"unpriced_allocations"`
+}
+
+// ExchangeDifferenceItem is the realized kur farkı of one allocation. Values
+// and Difference are in TRY; a negative Difference is a loss.
+type ExchangeDifferenceItem struct {
+	AllocationID   string    `json:"allocation_id"`
+	CustomerID     string    `json:"customer_id"`
+	InvoiceID      string    `json:"invoice_id"`
+	PaymentID      string    `json:"payment_id,omitempty"`
+	CreditNoteID   string    `json:"credit_note_id,omitempty"`
+	InvoiceDate    time.Time `json:"invoice_date"`
+	SettledAt      time.Time `json:"settled_at"`
+	Amount         string    `json:"amount"`
+	Currency       string    `json:"currency"`
+	InvoiceRate    string    `json:"invoice_rate"`
+	SettlementRate string    `json:"settlement_rate"`
+	BookedValue    string    `json:"booked_value"`
+	SettledValue   string    `json:"settled_value"`
+	Difference     string    `json:"difference"`
+	// DifferenceInvoiceID is the kur farkı invoice that billed a gain.
+	DifferenceInvoiceID string `json:"difference_invoice_id,omitempty"`
+}
+
+// CustomerExchangeSummary totals a customer's differences in TRY.
+type CustomerExchangeSummary struct {
+	CustomerID string `json:"customer_id"`
+	Gain       string `json:"gain"`
+	Loss       string `json:"loss"`
+	Net        string `json:"net"`
+}
+
+type ExchangeDifferenceInvoice struct {
+	InvoiceID     string   `json:"invoice_id"`
+	CustomerID    string   `json:"customer_id"`
+	Amount        int64    `json:"amount"`
+	Currency      string   `json:"currency"`
+	AllocationIDs []string `json:"allocation_ids"`
+}
@@ -0,0 +1,29 @@
+package dto
+
+import "time"
+
+type CreateExchangeRateRequest struct {
+	From string `json:"from" binding:"required,len=3"`
+	To   string `json:"to" binding:"required,len=3"`
+	// Rate is a decimal string, e.g. "34.1234", to avoid float rounding.
+	Rate string    `json:"rate" binding:"required"`
+	Date time.Time `json:"date"`
+}
+
+type ImportExchangeRatesRequest struct {
+	// Path is a TCMB bulletin file on the server's local disk.
+	Path string `json:"path" binding:"required"`
+}
+
+type ImportExchangeRatesResponse struct {
+	Imported int               `json:"imported"`
+	Rates    []ExchangeRateDTO `json:"rates"`
+}
+
+type ExchangeRateDTO struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Rate   string `json:"rate"`
+	Date   string `json:"date"`
+	Source string `json:"source"`
+}
@@ -3,16 +3,82 @@ package dto
 import "time"
 
 type CreateInvoiceRequest struct {
-	CustomerID string   `json:"customer_id" binding:"required"`
-	Amount     int64    `json:"amount" binding:"required,gt=0"`
-	Currency   string   `json:"currency" binding:"required,len=3"`
-	DueDate    time.Time `json:"due_date" binding:"required"`
+	CustomerID string `json:"customer_id" binding:"required"`
+	// Amount is for single-amount invoices. Leave it out when Lines are given:
+	// the total is then derived from the lines.
+	Amount   int64  `json:"amount" binding:"required_without=Lines,omitempty,gt=0"`
+	Currency string `json:"currency" binding:"required,len=3"`
+	// DueDate is the due date of a single-payment invoice, or the first due
+	// date of an InstalmentCount split. A manual schedule replaces it. Left
+	// out, it is derived from the payment terms.
+	DueDate time.Time            `json:"due_date"`
+	Lines   []InvoiceLineRequest `json:"lines" binding:"omitempty,dive"`
+	// InstalmentCount splits the total evenly into monthly instalments.
+	InstalmentCount int `json:"instalment_count" binding:"omitempty,min=2,max=120"`
+	// Instalments is a schedule entered by hand; it must add up to the total.
+	Instalments []InstalmentRequest `json:"instalments" binding:"omitempty,dive"`
+	// PaymentTerms override the customer's default terms for this invoice.
+	// They also split it into instalments when no schedule is given.
+	PaymentTerms *PaymentTermsRequest `json:"payment_terms"`
+}
+
+// InstalmentRequest is one entry of a hand-entered schedule. Amount is in
+// minor units of the invoice currency.
+type InstalmentRequest struct {
+	DueDate time.Time `json:"due_date" binding:"required"`
+	Amount  int64     `json:"amount" binding:"gt=0"`
+}
+
+// InvoiceLineRequest is one item of a new invoice. Money fields are in minor
+// units of the invoice currency.
+type InvoiceLineRequest struct {
+	Description string `json:"description" binding:"required"`
+	// Quantity is a decimal with up to three places, e.g. "2.5".
+	Quantity  string `json:"quantity" binding:"required"`
+	UnitPrice int64  `json:"unit_price" binding:"gte=0"`
+	Discount  int64  `json:"discount" binding:"gte=0"`
+	// VATRate is the KDV rate in percent: 0, 1, 10 or 20.
+	VATRate int `json:"vat_rate"`
+	// Withholding is the KDV tevkifat ratio, e.g. "9/10". Empty means none.
+	Withholding string `json:"withholding"`
 }
 
 type CreateInvoiceResponse struct {
-	InvoiceID   string    `json:"invoice_id"`
-	TotalAmount int64     `json:"total_amount"`
-	Currency    string    `json:"currency"`
-	Status      string    `json:"status"`
-	DueDate     time.Time `json:"due_date"`
+	InvoiceID      string                `json:"invoice_id"`
+	TotalAmount    int64                 `json:"total_amount"`
+	PaidAmount     int64                 `json:"paid_amount"`
+	Subtotal       int64                 `json:"subtotal"`
+	VATAmount      int64                 `json:"vat_amount"`
+	Withholding    int64                 `json:"withholding_amount"`
+	Currency       string                `json:"currency"`
+	Status         string                `json:"status"`
+	DueDate        time.Time             `json:"due_date"`
+	PaymentTerms   string                `json:"payment_terms,omitempty"`
+	Instalments    []InstalmentParams    `json:"instalments,omitempty"`
+	AppliedCredits []AppliedCreditParams `json:"applied_credits"`
+	// CreditWarnings say why the invoice broke the credit policy, when the
+	// policy flags such invoices rather than rejecting them.
+	CreditWarnings []string `json:"credit_warnings,omitempty"`
+}
+
+type InstalmentParams struct {
+	Number  int       `json:"number"`
+	DueDate time.Time `json:"due_date"`
+	Amount  int64     `json:"amount"`
+}
+
+// AppliedCreditParams is on-account credit of a payment or credit note
+// consumed by a new invoice.
+type AppliedCreditParams struct {
+	AllocationID string `json:"allocation_id"`
+	PaymentID    string `json:"payment_id,omitempty"`
+	CreditNoteID string `json:"credit_note_id,omitempty"`
+	Amount       int64  `json:"amount"`
+}
+
+type VoidInvoiceResponse struct {
+	InvoiceID           string                     `json:"invoice_id"`
+	Status              string                     `json:"status"`
+	Reason              string                     `json:"reason"`
+	ReleasedAllocations []ReversedAllocationParams `json:"released_allocations"`
 }
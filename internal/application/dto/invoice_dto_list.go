@@ -1,12 +1,58 @@
 package dto
 
+// InvoiceFilter narrows an invoice listing. The zero value lists everything.
+type InvoiceFilter struct {
+	Overdue bool `form:"overdue"`
+}
+
 type InvoiceDTO struct {
-	ID          string  `json:"id"`
-	CustomerID  string  `json:"customer_id"`
-	TotalAmount float64 `json:"total_amount"`
-	PaidAmount  float64 `json:"paid_amount"`
-	Currency    string  `json:"currency"`
-	Status      string  `json:"status"`
-	IssueDate   string  `json:"issue_date"`
-	DueDate     string  `json:"due_date"`
+	ID          string `json:"id"`
+	CustomerID  string `json:"customer_id"`
+	Kind        string `json:"kind"`
+	TotalAmount string `json:"total_amount"`
+	PaidAmount  string `json:"paid_amount"`
+	Subtotal    string `json:"subtotal"`
+	VATAmount   string `json:"vat_amount"`
+	Withholding string `json:"withholding_amount"`
+	Currency    string `json:"currency"`
+	Status      string `json:"status"`
+	// EffectiveStatus is Status, or OVERDUE for an open invoice past due.
+	EffectiveStatus string           `json:"effective_status"`
+	DaysOverdue     int              `json:"days_overdue"`
+	IssueDate       string           `json:"issue_date"`
+	DueDate         string           `json:"due_date"`
+	VoidReason      string           `json:"void_reason,omitempty"`
+	Lines           []InvoiceLineDTO `json:"lines,omitempty"`
+	Instalments     []InstalmentDTO  `json:"instalments,omitempty"`
+}
+
+// InstalmentDTO is one instalment with the part of the invoice's payments
+// that covers it.
+type InstalmentDTO struct {
+	Number    int    `json:"number"`
+	DueDate   string `json:"due_date"`
+	Amount    string `json:"amount"`
+	Paid      string `json:"paid"`
+	Remaining string `json:"remaining"`
+	Overdue   bool   `json:"overdue"`
+}
+
+type InvoiceLineDTO struct {
+	Description string `json:"description"`
+	Quantity    string `json:"quantity"`
+	UnitPrice   string `json:"unit_price"`
+	Discount    string `json:"discount"`
+	NetAmount   string `json:"net_amount"`
+	VATRate     int    `json:"vat_rate"`
+	VATAmount   string `json:"vat_amount"`
+	Withholding string `json:"withholding,omitempty"`
+	WithheldVAT string `json:"withheld_vat"`
+}
+
+// InvoiceDocument is an invoice with what a printed copy shows besides it:
+// the customer it is addressed to and the amount still to be paid.
+type InvoiceDocument struct {
+	Invoice   InvoiceDTO  `json:"invoice"`
+	Customer  CustomerDTO `json:"customer"`
+	Remaining string      `json:"remaining"`
 }
@@ -0,0 +1,61 @@
+package dto
+
+import "time"
+
+// LateInterestRequest selects the invoices to charge late interest on. An
+// empty CustomerID means every customer with a rate; a zero AsOf means today.
+type LateInterestRequest struct {
+	CustomerID string    `json:"customer_id" form:"customer_id"`
+	AsOf       time.Time `json:"as_of" form:"as_of" time_format:"2006-01-02"`
+	// IssueInvoices bills the interest with a vade farkı invoice per customer and currency.
+	IssueInvoices bool `json:"issue_invoices"`
+	// DueDate of the issued invoices; defaults to the issue date.
+	DueDate time.Time `json:"due_date"`
+}
+
+type LateInterestReport struct {
+	AsOf     time.Time             `json:"as_of"`
+	Items    []LateInterestItem    `json:"items"`
+	Invoices []LateInterestInvoice `json:"issued_invoices"`
+}
+
+// LateInterestItem is the interest accrued on one invoice, in its currency.
+type LateInterestItem struct {
+	InvoiceID  string `json:"invoice_id"`
+	CustomerID string `json:"customer_id"`
+	Currency   string `json:"currency"`
+	// Rate is a percentage per Period, e.g. "3.5" and "MONTHLY".
+	Rate     string                `json:"rate"`
+	Period   string                `json:"period"`
+	Portions []LateInterestPortion `json:"portions"`
+	Interest string                `json:"interest"`
+}
+
+// LateInterestPortion is a part of an instalment that accrued interest for
+// Days, from Since to Until. Since is the due date, or the day interest was
+// last billed through. Settled is false while the part is still unpaid.
+type LateInterestPortion struct {
+	Instalment int       `json:"instalment"`
+	DueDate    time.Time `json:"due_date"`
+	Since      time.Time `json:"since"`
+	Until      time.Time `json:"until"`
+	Days       int       `json:"days"`
+	Principal  string    `json:"principal"`
+	Interest   string    `json:"interest"`
+	Settled    bool      `json:"settled"`
+}
+
+type LateInterestInvoice struct {
+	InvoiceID  string   `json:"invoice_id"`
+	CustomerID string   `json:"customer_id"`
+	Amount     string   `json:"amount"`
+	Currency   string   `json:"currency"`
+	InvoiceIDs []string `json:"source_invoice_ids"`
+}
+
+// SetLateInterestRateRequest changes a customer's late interest rate. An
+// empty Rate stops charging interest.
+type SetLateInterestRateRequest struct {
+	Rate   string `json:"rate"`
+	Period string `json:"period"`
+}
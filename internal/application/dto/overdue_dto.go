@@ -0,0 +1,22 @@
+package dto
+
+import "time"
+
+// OverdueDetectionResult is the outcome of one overdue check: how many
+// invoices are overdue and which instalments became overdue since the last.
+type OverdueDetectionResult struct {
+	CheckedAt       time.Time         `json:"checked_at"`
+	OverdueInvoices int               `json:"overdue_invoices"`
+	NewEvents       []OverdueEventDTO `json:"new_events"`
+}
+
+type OverdueEventDTO struct {
+	ID         string    `json:"id"`
+	InvoiceID  string    `json:"invoice_id"`
+	CustomerID string    `json:"customer_id"`
+	Instalment int       `json:"instalment"`
+	DueDate    string    `json:"due_date"`
+	Amount     string    `json:"amount"`
+	Currency   string    `json:"currency"`
+	DetectedAt time.Time `json:"detected_at"`
+}
@@ -3,21 +3,42 @@ package dto
 import "time"
 
 type RegisterPaymentRequest struct {
-	CustomerID string  `json:"customer_id" binding:"required"`
-	Amount     int64   `json:"amount" binding:"required,gt=0"`
-	Currency   string  `json:"currency" binding:"required,len=3"`
+	CustomerID string    `json:"customer_id" binding:"required"`
+	Amount     int64     `json:"amount" binding:"required,gt=0"`
+	Currency   string    `json:"currency" binding:"required,len=3"`
 	Date       time.Time `json:"date"`
-	Notes      string  `json:"notes"`
+	Notes      string    `json:"notes"`
+	// AllocationStrategy overrides the customer's default allocation order.
+	AllocationStrategy string `json:"allocation_strategy"`
 }
 
 type RegisterPaymentResponse struct {
-	PaymentID         string `json:"payment_id"`
-	AllocatedAmount   int64  `json:"allocated_amount"`
-	RemainingBalance  int64  `json:"remaining_balance"`
+	PaymentID         string                   `json:"payment_id"`
+	AllocatedAmount   int64                    `json:"allocated_amount"`
+	RemainingBalance  int64                    `json:"remaining_balance"`
 	AllocatedInvoices []AllocatedInvoiceParams `json:"allocated_invoices"`
 }
 
+// AllocatedInvoiceParams describes one booked allocation. Amount is in the
+// payment currency; the invoice-side fields are set only when the invoice is
+// billed in another currency.
 type AllocatedInvoiceParams struct {
-	InvoiceID string `json:"invoice_id"`
-	Amount    int64  `json:"amount"`
+	AllocationID    string `json:"allocation_id"`
+	InvoiceID       string `json:"invoice_id"`
+	Amount          int64  `json:"amount"`
+	InvoiceAmount   int64  `json:"invoice_amount,omitempty"`
+	InvoiceCurrency string `json:"invoice_currency,omitempty"`
+	ExchangeRate    string `json:"exchange_rate,omitempty"`
+	// Discount is the early-payment discount that settled the rest of the
+	// invoice, in the invoice currency, e.g. "12.50".
+	Discount string `json:"discount,omitempty"`
+}
+
+type ManualAllocationRequest struct {
+	Lines []ManualAllocationLine `json:"lines" binding:"required,min=1,dive"`
+}
+
+type ManualAllocationLine struct {
+	InvoiceID string `json:"invoice_id" binding:"required"`
+	Amount    int64  `json:"amount" binding:"required,gt=0"`
 }
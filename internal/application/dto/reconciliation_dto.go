@@ -0,0 +1,66 @@
+package dto
+
+import "time"
+
+// CreateReconciliationRequest asks for a balance confirmation as of the end
+// of AsOf; a zero AsOf means today.
+type CreateReconciliationRequest struct {
+	AsOf time.Time `json:"as_of"`
+}
+
+// RespondReconciliationRequest records the customer's answer. Status is
+// AGREED or DISPUTED; a dispute states the customer's balances in minor
+// units, positive when they owe us, and may explain them in Note.
+type RespondReconciliationRequest struct {
+	Status          string          `json:"status" binding:"required,oneof=AGREED DISPUTED"`
+	ClaimedBalances []ClaimedAmount `json:"claimed_balances" binding:"dive"`
+	Note            string          `json:"note"`
+}
+
+type ClaimedAmount struct {
+	Currency string `json:"currency" binding:"required,len=3"`
+	Amount   int64  `json:"amount"`
+}
+
+// ReconciliationDTO is a balance confirmation. Balances are signed decimal
+// amounts like the statement's, positive when the customer owes us.
+type ReconciliationDTO struct {
+	ID           string                        `json:"id"`
+	CustomerID   string                        `json:"customer_id"`
+	CustomerName string                        `json:"customer_name"`
+	AsOf         time.Time                     `json:"as_of"`
+	Status       string                        `json:"status"`
+	Balances     []CurrencyAmount              `json:"balances"`
+	Claimed      []CurrencyAmount              `json:"claimed,omitempty"`
+	Differences  []ReconciliationDifferenceDTO `json:"differences,omitempty"`
+	Note         string                        `json:"note,omitempty"`
+	CreatedAt    time.Time                     `json:"created_at"`
+	RespondedAt  *time.Time                    `json:"responded_at,omitempty"`
+}
+
+// ReconciliationDifferenceDTO is a currency to investigate. Difference is
+// ours less theirs.
+type ReconciliationDifferenceDTO struct {
+	Currency   string `json:"currency"`
+	Ours       string `json:"ours"`
+	Theirs     string `json:"theirs"`
+	Difference string `json:"difference"`
+}
+
+// ReconciliationDocument is the confirmation letter sent to the customer.
+type ReconciliationDocument struct {
+	Reconciliation ReconciliationDTO `json:"reconciliation"`
+	Customer       CustomerDTO       `json:"customer"`
+	// Balances state each balance as an amount and the side it is on for
+	// the customer: BORÇ when they owe us, ALACAK when we owe them.
+	Balances []LetterBalance `json:"balances"`
+	// Subject and Body are the letter as plain text, for email.
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+type LetterBalance struct {
+	Currency string `json:"currency"`
+	Amount   string `json:"amount"`
+	Side     string `json:"side"`
+}
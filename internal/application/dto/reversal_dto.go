@@ -0,0 +1,20 @@
+package dto
+
+type ReversalRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+type ReversalResponse struct {
+	PaymentID           string                     `json:"payment_id,omitempty"`
+	CreditNoteID        string                     `json:"credit_note_id,omitempty"`
+	PaymentReversed     bool                       `json:"payment_reversed"`
+	RemainingBalance    int64                      `json:"remaining_balance"`
+	ReversedAllocations []ReversedAllocationParams `json:"reversed_allocations"`
+}
+
+type ReversedAllocationParams struct {
+	AllocationID string `json:"allocation_id"`
+	ReversalID   string `json:"reversal_id"`
+	InvoiceID    string `json:"invoice_id"`
+	Amount       int64  `json:"amount"`
+}
@@ -2,20 +2,53 @@ package dto
 
 import "time"
 
+// StatementRequest limits a statement to the days From through To. Empty
+// fields do not limit.
+type StatementRequest struct {
+	From time.Time `json:"from" form:"from" time_format:"2006-01-02"`
+	To   time.Time `json:"to" form:"to" time_format:"2006-01-02"`
+}
+
 type StatementItem struct {
 	Date        time.Time `json:"date"`
 	Type        string    `json:"type"`
 	ReferenceID string    `json:"reference_id"`
 	Description string    `json:"description"`
-	Debt        float64   `json:"debt"`
-	Credit      float64   `json:"credit"`
-	Balance     float64   `json:"balance"`
+	Debt        string    `json:"debt"`
+	Credit      string    `json:"credit"`
+	Balance     string    `json:"balance"`
 	Currency    string    `json:"currency"`
+	// DueDate and Overdue describe invoice obligations; with an instalment
+	// plan each instalment is its own item.
+	DueDate *time.Time `json:"due_date,omitempty"`
+	Overdue bool       `json:"overdue,omitempty"`
+}
+
+// CurrencyStatement holds the movements of one currency with their own running
+// balance. Amounts in different currencies are never added together.
+// OpeningBalance (devir) is the balance brought forward from before the
+// statement period; the running balance starts from it. TotalDebt and
+// TotalCredit add up the period's transactions.
+type CurrencyStatement struct {
+	Currency       string          `json:"currency"`
+	OpeningBalance string          `json:"opening_balance"`
+	Transactions   []StatementItem `json:"transactions"`
+	TotalDebt      string          `json:"total_debt"`
+	TotalCredit    string          `json:"total_credit"`
+	FinalBalance   string          `json:"final_balance"`
 }
 
 type CustomerStatementDTO struct {
-	Customer     CustomerDTO     `json:"customer"`
-	Transactions []StatementItem `json:"transactions"`
-	FinalBalance float64         `json:"final_balance"`
-	Currency     string          `json:"currency"`
+	Customer CustomerDTO `json:"customer"`
+	// From and To are the statement period, if it is limited.
+	From       *time.Time          `json:"from,omitempty"`
+	To         *time.Time          `json:"to,omitempty"`
+	Currencies []CurrencyStatement `json:"currencies"`
+	// OnAccountCredits is money received but not yet allocated to any invoice.
+	OnAccountCredits []CurrencyAmount `json:"on_account_credits"`
+}
+
+type CurrencyAmount struct {
+	Currency string `json:"currency"`
+	Amount   string `json:"amount"`
 }
@@ -1,8 +1,11 @@
 package ports
 
 import (
+	"carigo/internal/application/dto"
 	"carigo/internal/domain"
 	"context"
+	"io"
+	"time"
 )
 
 // InvoiceRepository defines access to Invoice storage.
@@ -15,6 +18,25 @@ type InvoiceRepository interface {
 	FindByCustomer(ctx context.Context, customerID domain.CustomerID) ([]*domain.Invoice, error)
 	CountAllOpen(ctx context.Context) (int64, error)
 	SumTotalAmount(ctx context.Context) (int64, error)
+	// SumOpenByAge totals the open balance of invoices issued on or before
+	// asOf per customer, currency and aging bucket, counting days past due up
+	// to asOf. Balances are as they stood at asOf: payments allocated and
+	// invoices voided later do not count. Invoices with an instalment plan
+	// are aged per instalment.
+	SumOpenByAge(ctx context.Context, asOf time.Time, buckets domain.AgingBuckets) ([]AgedBalance, error)
+	// FindOverdue returns the invoices that, at asOf, were open with an
+	// unpaid instalment due before the day of asOf.
+	FindOverdue(ctx context.Context, asOf time.Time) ([]*domain.Invoice, error)
+	// CountOverdue counts the invoices FindOverdue would return.
+	CountOverdue(ctx context.Context, asOf time.Time) (int64, error)
+}
+
+// AgedBalance is the open balance of a customer in one currency and aging
+// bucket; Bucket indexes domain.AgingBuckets.Labels().
+type AgedBalance struct {
+	CustomerID domain.CustomerID
+	Bucket     int
+	Amount     domain.Money
 }
 
 // PaymentRepository defines access to Payment storage.
@@ -23,7 +45,26 @@ type PaymentRepository interface {
 	FindByID(ctx context.Context, id domain.PaymentID) (*domain.Payment, error)
 	FindAll(ctx context.Context) ([]*domain.Payment, error)
 	FindByCustomer(ctx context.Context, customerID domain.CustomerID) ([]*domain.Payment, error)
+	// FindUnallocatedByCustomer returns payments that still have AvailableAmount left, oldest first.
+	FindUnallocatedByCustomer(ctx context.Context, customerID domain.CustomerID) ([]*domain.Payment, error)
 	SumTotalCollected(ctx context.Context) (int64, error)
+	// SumAvailableByCustomer returns the on-account credit of every customer that has any, per currency.
+	SumAvailableByCustomer(ctx context.Context) ([]CustomerCredit, error)
+}
+
+// CustomerCredit is the unallocated payment balance of a customer in one currency.
+type CustomerCredit struct {
+	CustomerID domain.CustomerID
+	Amount     domain.Money
+}
+
+// CreditNoteRepository defines access to CreditNote storage.
+type CreditNoteRepository interface {
+	Save(ctx context.Context, note *domain.CreditNote) error
+	FindByID(ctx context.Context, id domain.CreditNoteID) (*domain.CreditNote, error)
+	FindByCustomer(ctx context.Context, customerID domain.CustomerID) ([]*domain.CreditNote, error)
+	// FindUnallocatedByCustomer returns credit notes that still have AvailableAmount left, oldest first.
+	FindUnallocatedByCustomer(ctx context.Context, customerID domain.CustomerID) ([]*domain.CreditNote, error)
 }
 
 // CustomerRepository defines access to Customer storage.
@@ -37,6 +78,116 @@ type CustomerRepository interface {
 // AllocationRepository defines access to Allocation storage.
 type AllocationRepository interface {
 	Save(ctx context.Context, allocation *domain.Allocation) error
+	FindByID(ctx context.Context, id domain.AllocationID) (*domain.Allocation, error)
+	// FindByPayment returns every allocation entry of a payment, including reversals, oldest first.
+	FindByPayment(ctx context.Context, paymentID domain.PaymentID) ([]*domain.Allocation, error)
+	// FindByCreditNote returns every allocation entry of a credit note, including reversals, oldest first.
+	FindByCreditNote(ctx context.Context, creditNoteID domain.CreditNoteID) ([]*domain.Allocation, error)
+	// FindByInvoice returns every allocation entry of an invoice, including reversals, oldest first.
+	FindByInvoice(ctx context.Context, invoiceID domain.InvoiceID) ([]*domain.Allocation, error)
+	// FindByExchangeDifferenceInvoice returns the allocations whose exchange gain the kur farkı invoice billed.
+	FindByExchangeDifferenceInvoice(ctx context.Context, invoiceID domain.InvoiceID) ([]*domain.Allocation, error)
+	// FindActiveForeign returns active allocations that settle invoices billed in
+	// a currency other than localCurrency, oldest first.
+	FindActiveForeign(ctx context.Context, localCurrency string) ([]*domain.Allocation, error)
+}
+
+// AllocationPlanStore keeps proposed allocation plans until they are confirmed.
+// Plans are not part of the ledger, so implementations need not be durable.
+type AllocationPlanStore interface {
+	Save(ctx context.Context, plan *domain.AllocationPlan) error
+	FindByID(ctx context.Context, id domain.AllocationPlanID) (*domain.AllocationPlan, error)
+	Delete(ctx context.Context, id domain.AllocationPlanID) error
+}
+
+// ExchangeRateProvider supplies rates for converting between currencies.
+type ExchangeRateProvider interface {
+	// Rate returns the most recent rate for the pair on or before the given day,
+	// stored in either direction, going back at most domain.RateMaxAgeDays
+	// days. It returns domain.ErrExchangeRateNotFound if there is none.
+	Rate(ctx context.Context, from, to string, on time.Time) (*domain.ExchangeRate, error)
+}
+
+// ExchangeRateRepository stores rates entered manually or imported from a feed.
+type ExchangeRateRepository interface {
+	ExchangeRateProvider
+	// Save stores the rate, replacing any rate for the same pair and day.
+	Save(ctx context.Context, rate *domain.ExchangeRate) error
+	// FindAll returns every stored rate, newest day first.
+	FindAll(ctx context.Context) ([]*domain.ExchangeRate, error)
+}
+
+// OverdueEventRepository stores the events recorded when invoices become overdue.
+type OverdueEventRepository interface {
+	Save(ctx context.Context, event *domain.OverdueEvent) error
+	FindByInvoice(ctx context.Context, invoiceID domain.InvoiceID) ([]*domain.OverdueEvent, error)
+	// FindRecent returns the latest events, newest first.
+	FindRecent(ctx context.Context, limit int) ([]*domain.OverdueEvent, error)
+}
+
+// ExchangeRateImporter reads a published rate file, such as the TCMB daily bulletin.
+type ExchangeRateImporter interface {
+	Import(ctx context.Context, path string) ([]*domain.ExchangeRate, error)
+}
+
+// BankLineRepository stages the incoming transfers imported from bank statements.
+type BankLineRepository interface {
+	Save(ctx context.Context, line *domain.BankLine) error
+	FindByID(ctx context.Context, id domain.BankLineID) (*domain.BankLine, error)
+	// FindByState returns the lines in state, newest booking first; an empty
+	// state returns every line.
+	FindByState(ctx context.Context, state domain.BankLineState) ([]*domain.BankLine, error)
+}
+
+// BankMatchRuleRepository stores the IBAN to customer rules learned from
+// clerks.
+type BankMatchRuleRepository interface {
+	// Save stores the rule, replacing any rule for the same IBAN.
+	Save(ctx context.Context, rule *domain.BankMatchRule) error
+	FindAll(ctx context.Context) ([]*domain.BankMatchRule, error)
+}
+
+// ReconciliationRepository stores balance confirmations. FindByStatus lists
+// the newest first; an empty status finds all of them.
+type ReconciliationRepository interface {
+	Save(ctx context.Context, r *domain.Reconciliation) error
+	FindByID(ctx context.Context, id domain.ReconciliationID) (*domain.Reconciliation, error)
+	FindByStatus(ctx context.Context, status domain.ReconciliationStatus) ([]*domain.Reconciliation, error)
+}
+
+// StatementRepository aggregates customer accounts in storage.
+type StatementRepository interface {
+	// BalancesBefore sums every movement of the customer's account dated
+	// before the given time into its balance per currency, by currency code.
+	BalancesBefore(ctx context.Context, customerID domain.CustomerID, before time.Time) ([]domain.Balance, error)
+}
+
+// BankStatementEntry is an incoming transfer as read from a bank statement.
+// Reference and ValueDate may be empty when the statement has none.
+type BankStatementEntry struct {
+	Account     string
+	Reference   string
+	BookingDate time.Time
+	ValueDate   time.Time
+	Amount      domain.Money
+	PayerName   string
+	PayerIBAN   string
+	PayerTaxID  string
+	Description string
+}
+
+// BankStatementParser reads the incoming transfers of an exported bank
+// statement. profile names the column layout of CSV exports; an empty format
+// is detected from the content.
+type BankStatementParser interface {
+	Parse(ctx context.Context, format domain.BankStatementFormat, profile string, r io.Reader) (domain.BankStatementFormat, []BankStatementEntry, error)
+}
+
+// DocumentRenderer lays out customer statements and invoices as printable
+// files, such as PDF.
+type DocumentRenderer interface {
+	RenderStatement(st *dto.CustomerStatementDTO) ([]byte, error)
+	RenderInvoice(doc *dto.InvoiceDocument) ([]byte, error)
 }
 
 // TransactionManager handles database transactions.
@@ -0,0 +1,99 @@
+package usecases
+
+import (
+	"carigo/internal/application/dto"
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"context"
+	"fmt"
+)
+
+// AllocatePaymentManuallyUseCase applies the unallocated part of an existing
+// payment to invoices chosen by the user, e.g. from a remittance advice.
+// Either every line is booked or none is.
+type AllocatePaymentManuallyUseCase struct {
+	paymentRepo    ports.PaymentRepository
+	invoiceRepo    ports.InvoiceRepository
+	allocationRepo ports.AllocationRepository
+	rates          ports.ExchangeRateProvider
+	txManager      ports.TransactionManager
+	clock          ports.Clock
+}
+
+func NewAllocatePaymentManuallyUseCase(
+	pr ports.PaymentRepository,
+	ir ports.InvoiceRepository,
+	ar ports.AllocationRepository,
+	rp ports.ExchangeRateProvider,
+	tm ports.TransactionManager,
+	clk ports.Clock,
+) *AllocatePaymentManuallyUseCase {
+	return &AllocatePaymentManuallyUseCase{
+		paymentRepo:    pr,
+		invoiceRepo:    ir,
+		allocationRepo: ar,
+		rates:          rp,
+		txManager:      tm,
+		clock:          clk,
+	}
+}
+
+func (uc *AllocatePaymentManuallyUseCase) Execute(ctx context.Context, paymentID string, req dto.ManualAllocationRequest) (*dto.RegisterPaymentResponse, error) {
+	var payment *domain.Payment
+	allocatedItems := []dto.AllocatedInvoiceParams{}
+	totalAllocated := int64(0)
+
+	err := uc.txManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		payment, err = uc.paymentRepo.FindByID(ctx, domain.PaymentID(paymentID))
+		if err != nil {
+			return err
+		}
+
+		invoices := make(map[domain.InvoiceID]*domain.Invoice)
+		for i, line := range req.Lines {
+			invID := domain.InvoiceID(line.InvoiceID)
+			inv, ok := invoices[invID]
+			if !ok {
+				inv, err = uc.invoiceRepo.FindByID(ctx, invID)
+				if err != nil {
+					return err
+				}
+				invoices[invID] = inv
+			}
+
+			amount, err := domain.NewMoney(line.Amount, payment.AvailableAmount.Currency())
+			if err != nil {
+				return fmt.Errorf("invalid amount: %w", err)
+			}
+
+			allocID := domain.AllocationID(fmt.Sprintf("AL-%s-%s-%d-%d", payment.ID, inv.ID, uc.clock.Now().UnixNano(), i))
+			allocation, err := allocateAcrossCurrencies(ctx, uc.rates, allocID, payment, inv, amount, payment.Date)
+			if err != nil {
+				return fmt.Errorf("invoice %s: %w", inv.ID, err)
+			}
+
+			if err := uc.invoiceRepo.Save(ctx, inv); err != nil {
+				return err
+			}
+			if err := uc.allocationRepo.Save(ctx, allocation); err != nil {
+				return err
+			}
+
+			allocatedItems = append(allocatedItems, mapAllocatedInvoice(allocation))
+			totalAllocated += allocation.Amount.Amount()
+		}
+
+		return uc.paymentRepo.Save(ctx, payment)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.RegisterPaymentResponse{
+		PaymentID:         string(payment.ID),
+		AllocatedAmount:   totalAllocated,
+		RemainingBalance:  payment.AvailableAmount.Amount(),
+		AllocatedInvoices: allocatedItems,
+	}, nil
+}
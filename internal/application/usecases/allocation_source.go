@@ -0,0 +1,53 @@
+package usecases
+
+import (
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"context"
+	"fmt"
+	"time"
+)
+
+// findAllocationSource loads the payment or credit note an allocation draws from.
+func findAllocationSource(
+	ctx context.Context,
+	allocation *domain.Allocation,
+	pr ports.PaymentRepository,
+	cnr ports.CreditNoteRepository,
+) (domain.AllocationSource, error) {
+	if allocation.CreditNoteID != "" {
+		return cnr.FindByID(ctx, allocation.CreditNoteID)
+	}
+	return pr.FindByID(ctx, allocation.PaymentID)
+}
+
+// saveAllocationSource persists a payment or credit note after its available
+// amount changed.
+func saveAllocationSource(
+	ctx context.Context,
+	source domain.AllocationSource,
+	pr ports.PaymentRepository,
+	cnr ports.CreditNoteRepository,
+) error {
+	switch s := source.(type) {
+	case *domain.Payment:
+		return pr.Save(ctx, s)
+	case *domain.CreditNote:
+		return cnr.Save(ctx, s)
+	default:
+		return fmt.Errorf("unsupported allocation source %T", source)
+	}
+}
+
+// allocationSourceDate returns the day the payment was received or the credit
+// note was issued.
+func allocationSourceDate(source domain.AllocationSource) (time.Time, error) {
+	switch s := source.(type) {
+	case *domain.Payment:
+		return s.Date, nil
+	case *domain.CreditNote:
+		return s.Date, nil
+	default:
+		return time.Time{}, fmt.Errorf("unsupported allocation source %T", source)
+	}
+}
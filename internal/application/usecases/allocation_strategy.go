@@ -0,0 +1,21 @@
+package usecases
+
+import (
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"context"
+)
+
+// resolveAllocationStrategy returns the per-request override when given,
+// otherwise the customer's default strategy.
+func resolveAllocationStrategy(ctx context.Context, custRepo ports.CustomerRepository, customerID domain.CustomerID, override string) (domain.AllocationStrategy, error) {
+	if override != "" {
+		return domain.NewAllocationStrategy(domain.AllocationStrategyName(override))
+	}
+
+	customer, err := custRepo.FindByID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewAllocationStrategy(customer.AllocationStrategy)
+}
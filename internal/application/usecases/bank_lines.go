@@ -0,0 +1,125 @@
+package usecases
+
+import (
+	"carigo/internal/application/dto"
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"context"
+	"strings"
+)
+
+// candidatePayment is the payment a bank line would be registered as: the
+// transferred amount, received on the value date.
+func candidatePayment(l *domain.BankLine) dto.RegisterPaymentRequest {
+	notes := "Banka " + l.Reference
+	if l.Description != "" {
+		notes += ": " + l.Description
+	}
+	return dto.RegisterPaymentRequest{
+		CustomerID: string(l.CustomerID),
+		Amount:     l.Amount.Amount(),
+		Currency:   l.Amount.Currency(),
+		Date:       l.ValueDate,
+		Notes:      strings.TrimSpace(notes),
+	}
+}
+
+func mapBankLine(l *domain.BankLine) dto.BankLineDTO {
+	return dto.BankLineDTO{
+		ID:          string(l.ID),
+		Account:     l.Account,
+		Reference:   l.Reference,
+		BookingDate: l.BookingDate.Format("2006-01-02"),
+		ValueDate:   l.ValueDate.Format("2006-01-02"),
+		Amount:      l.Amount.Decimal(),
+		Currency:    l.Amount.Currency(),
+		PayerName:   l.PayerName,
+		PayerIBAN:   l.PayerIBAN,
+		PayerTaxID:  l.PayerTaxID,
+		Description: l.Description,
+		Format:      string(l.Format),
+		State:       string(l.State),
+		CustomerID:  string(l.CustomerID),
+		PaymentID:   string(l.PaymentID),
+		ImportedAt:  l.ImportedAt,
+		Candidate:   candidatePayment(l),
+	}
+}
+
+// bankMatcher matches bank lines against the customers, their open invoices
+// and the learned rules as they were loaded.
+type bankMatcher struct {
+	invoiceRepo ports.InvoiceRepository
+	customers   []*domain.Customer
+	names       map[domain.CustomerID]string
+	open        map[domain.CustomerID][]*domain.Invoice
+	rules       []*domain.BankMatchRule
+}
+
+func loadBankMatcher(ctx context.Context, cr ports.CustomerRepository, ir ports.InvoiceRepository, rr ports.BankMatchRuleRepository) (*bankMatcher, error) {
+	customers, err := cr.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := rr.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &bankMatcher{
+		invoiceRepo: ir,
+		customers:   customers,
+		names:       map[domain.CustomerID]string{},
+		open:        map[domain.CustomerID][]*domain.Invoice{},
+		rules:       rules,
+	}
+	for _, c := range customers {
+		m.names[c.ID] = c.Name
+		if err := m.reload(ctx, c.ID); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// reload refreshes the open invoices of a customer, after a payment of
+// theirs was posted.
+func (m *bankMatcher) reload(ctx context.Context, customerID domain.CustomerID) error {
+	open, err := m.invoiceRepo.FindOpenByCustomer(ctx, customerID)
+	if err != nil {
+		return err
+	}
+	m.open[customerID] = open
+	return nil
+}
+
+func (m *bankMatcher) match(line *domain.BankLine) domain.BankMatch {
+	return domain.MatchBankLine(line, m.customers, m.open, m.rules)
+}
+
+func (m *bankMatcher) mapCandidates(match domain.BankMatch) []dto.BankMatchCandidateDTO {
+	dtos := make([]dto.BankMatchCandidateDTO, 0, len(match.Candidates))
+	for _, c := range match.Candidates {
+		item := dto.BankMatchCandidateDTO{
+			CustomerID:   string(c.CustomerID),
+			CustomerName: m.names[c.CustomerID],
+			Score:        c.Score,
+		}
+		for _, r := range c.Reasons {
+			item.Reasons = append(item.Reasons, string(r))
+		}
+		for _, id := range c.Invoices {
+			item.Invoices = append(item.Invoices, string(id))
+		}
+		dtos = append(dtos, item)
+	}
+	return dtos
+}
+
+func mapBankMatchRule(r *domain.BankMatchRule) dto.BankMatchRuleDTO {
+	return dto.BankMatchRuleDTO{
+		IBAN:       r.IBAN,
+		CustomerID: string(r.CustomerID),
+		CreatedAt:  r.CreatedAt,
+	}
+}
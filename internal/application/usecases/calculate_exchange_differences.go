@@ -0,0 +1,251 @@
+package usecases
+
+import (
+	"carigo/internal/application/dto"
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// CalculateExchangeDifferencesUseCase computes the realized kur farkı of every
+// active allocation against a foreign-currency invoice: the settled amount is
+// valued in TRY at the invoice-date rate and at the settlement-date rate. When
+// asked, it also bills each customer's uninvoiced gains with a kur farkı
+// invoice.
+type CalculateExchangeDifferencesUseCase struct {
+	allocationRepo ports.AllocationRepository
+	invoiceRepo    ports.InvoiceRepository
+	paymentRepo    ports.PaymentRepository
+	creditNoteRepo ports.CreditNoteRepository
+	rates          ports.ExchangeRateProvider
+	txManager      ports.TransactionManager
+	clock          ports.Clock
+}
+
+func NewCalculateExchangeDifferencesUseCase(
+	ar ports.AllocationRepository,
+	ir ports.InvoiceRepository,
+	pr ports.PaymentRepository,
+	cnr ports.CreditNoteRepository,
+	rp ports.ExchangeRateProvider,
+	tm ports.TransactionManager,
+	clk ports.Clock,
+) *CalculateExchangeDifferencesUseCase {
+	return &CalculateExchangeDifferencesUseCase{
+		allocationRepo: ar,
+		invoiceRepo:    ir,
+		paymentRepo:    pr,
+		creditNoteRepo: cnr,
+		rates:          rp,
+		txManager:      tm,
+		clock:          clk,
+	}
+}
+
+func (uc *CalculateExchangeDifferencesUseCase) Execute(ctx context.Context, req dto.ExchangeDifferenceRequest) (*dto.ExchangeDifferenceReport, error) {
+	var differences []*domain.ExchangeDifference
+	report := &dto.ExchangeDifferenceReport{
+		Items:     []dto.ExchangeDifferenceItem{},
+		Customers: []dto.CustomerExchangeSummary{},
+		Invoices:  []dto.ExchangeDifferenceInvoice{},
+		Unpriced:  []string{},
+	}
+
+	err := uc.txManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		differences, report.Unpriced, err = uc.collect(ctx, req)
+		if err != nil {
+			return err
+		}
+		if !req.IssueInvoices {
+			return nil
+		}
+		report.Invoices, err = uc.issueInvoices(ctx, differences, req.DueDate)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	type totals struct{ gain, loss domain.Money }
+	byCustomer := map[domain.CustomerID]*totals{}
+	for _, d := range differences {
+		report.Items = append(report.Items, mapExchangeDifference(d))
+
+		t, ok := byCustomer[d.CustomerID]
+		if !ok {
+			zero, _ := domain.NewMoney(0, domain.LocalCurrency)
+			t = &totals{gain: zero, loss: zero}
+			byCustomer[d.CustomerID] = t
+		}
+		var err error
+		if d.IsGain() {
+			t.gain, err = t.gain.Add(d.Difference().Abs())
+		} else {
+			t.loss, err = t.loss.Add(d.Difference().Abs())
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	for customerID, t := range byCustomer {
+		net, _ := domain.NewBalance(domain.LocalCurrency)
+		net, _ = net.Debit(t.gain)
+		net, _ = net.Credit(t.loss)
+		report.Customers = append(report.Customers, dto.CustomerExchangeSummary{
+			CustomerID: string(customerID),
+			Gain:       t.gain.Decimal(),
+			Loss:       t.loss.Decimal(),
+			Net:        net.Decimal(),
+		})
+	}
+	sort.Slice(report.Customers, func(i, j int) bool {
+		return report.Customers[i].CustomerID < report.Customers[j].CustomerID
+	})
+
+	return report, nil
+}
+
+// collect values every matching allocation at both rates. Allocations whose
+// invoice-date or settlement-date rate is unknown are returned as unpriced.
+func (uc *CalculateExchangeDifferencesUseCase) collect(ctx context.Context, req dto.ExchangeDifferenceRequest) ([]*domain.ExchangeDifference, []string, error) {
+	allocations, err := uc.allocationRepo.FindActiveForeign(ctx, domain.LocalCurrency)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var differences []*domain.ExchangeDifference
+	unpriced := []string{}
+	for _, allocation := range allocations {
+		invoice, err := uc.invoiceRepo.FindByID(ctx, allocation.InvoiceID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if req.CustomerID != "" && invoice.CustomerID != domain.CustomerID(req.CustomerID) {
+			continue
+		}
+
+		source, err := findAllocationSource(ctx, allocation, uc.paymentRepo, uc.creditNoteRepo)
+		if err != nil {
+			return nil, nil, err
+		}
+		settledAt, err := allocationSourceDate(source)
+		if err != nil {
+			return nil, nil, err
+		}
+		day := domain.RateDay(settledAt)
+		if !req.From.IsZero() && day.Before(domain.RateDay(req.From)) {
+			continue
+		}
+		if !req.To.IsZero() && day.After(domain.RateDay(req.To)) {
+			continue
+		}
+
+		currency := allocation.InvoiceAmount.Currency()
+		invoiceRate, err := uc.rates.Rate(ctx, currency, domain.LocalCurrency, invoice.IssueDate)
+		if errors.Is(err, domain.ErrExchangeRateNotFound) {
+			unpriced = append(unpriced, string(allocation.ID))
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// A TRY payment converted at booking time was collected at that rate.
+		settlementRate := allocation.ExchangeRate
+		if settlementRate == nil || !settlementRate.Converts(currency, domain.LocalCurrency) {
+			settlementRate, err = uc.rates.Rate(ctx, currency, domain.LocalCurrency, settledAt)
+			if errors.Is(err, domain.ErrExchangeRateNotFound) {
+				unpriced = append(unpriced, string(allocation.ID))
+				continue
+			}
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		difference, err := domain.NewExchangeDifference(allocation, invoice, settledAt, invoiceRate, settlementRate)
+		if err != nil {
+			return nil, nil, err
+		}
+		differences = append(differences, difference)
+	}
+
+	sort.SliceStable(differences, func(i, j int) bool {
+		return differences[i].SettledAt.Before(differences[j].SettledAt)
+	})
+	return differences, unpriced, nil
+}
+
+// issueInvoices bills each customer's uninvoiced gains with one kur farkı
+// invoice. Losses are left for the customer to invoice.
+func (uc *CalculateExchangeDifferencesUseCase) issueInvoices(ctx context.Context, differences []*domain.ExchangeDifference, dueDate time.Time) ([]dto.ExchangeDifferenceInvoice, error) {
+	var customers []domain.CustomerID
+	gains := map[domain.CustomerID][]*domain.ExchangeDifference{}
+	for _, d := range differences {
+		if !d.IsGain() || d.IsInvoiced() {
+			continue
+		}
+		if _, ok := gains[d.CustomerID]; !ok {
+			customers = append(customers, d.CustomerID)
+		}
+		gains[d.CustomerID] = append(gains[d.CustomerID], d)
+	}
+
+	now := uc.clock.Now()
+	if dueDate.IsZero() {
+		dueDate = now
+	}
+
+	issued := []dto.ExchangeDifferenceInvoice{}
+	for i, customerID := range customers {
+		id := domain.InvoiceID(fmt.Sprintf("KF-%d-%d", now.UnixNano(), i+1))
+		inv, err := domain.NewExchangeDifferenceInvoice(id, customerID, gains[customerID], now, dueDate)
+		if err != nil {
+			return nil, err
+		}
+		if err := uc.invoiceRepo.Save(ctx, inv); err != nil {
+			return nil, err
+		}
+
+		item := dto.ExchangeDifferenceInvoice{
+			InvoiceID:  string(inv.ID),
+			CustomerID: string(customerID),
+			Amount:     inv.TotalAmount.Amount(),
+			Currency:   inv.TotalAmount.Currency(),
+		}
+		for _, d := range gains[customerID] {
+			if err := uc.allocationRepo.Save(ctx, d.Allocation); err != nil {
+				return nil, err
+			}
+			item.AllocationIDs = append(item.AllocationIDs, string(d.Allocation.ID))
+		}
+		issued = append(issued, item)
+	}
+	return issued, nil
+}
+
+func mapExchangeDifference(d *domain.ExchangeDifference) dto.ExchangeDifferenceItem {
+	a := d.Allocation
+	return dto.ExchangeDifferenceItem{
+		AllocationID:        string(a.ID),
+		CustomerID:          string(d.CustomerID),
+		InvoiceID:           string(a.InvoiceID),
+		PaymentID:           string(a.PaymentID),
+		CreditNoteID:        string(a.CreditNoteID),
+		InvoiceDate:         d.InvoiceDate,
+		SettledAt:           d.SettledAt,
+		Amount:              a.InvoiceAmount.Decimal(),
+		Currency:            a.InvoiceAmount.Currency(),
+		InvoiceRate:         d.InvoiceRate.String(),
+		SettlementRate:      d.SettlementRate.String(),
+		BookedValue:         d.BookedValue.Decimal(),
+		SettledValue:        d.SettledValue.Decimal(),
+		Difference:          d.Difference().Decimal(),
+		DifferenceInvoiceID: string(a.ExchangeDifferenceInvoiceID),
+	}
+}
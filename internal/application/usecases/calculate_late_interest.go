@@ -0,0 +1,248 @@
+package usecases
+
+import (
+	"carigo/internal/application/dto"
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// CalculateLateInterestUseCase computes the gecikme faizi on invoices of
+// customers with a late interest rate. Each part of an invoice accrues
+// interest for the days it was overdue, as recorded by the allocations that
+// settled it. When asked, it also bills the interest with vade farkı invoices.
+type CalculateLateInterestUseCase struct {
+	invoiceRepo    ports.InvoiceRepository
+	customerRepo   ports.CustomerRepository
+	allocationRepo ports.AllocationRepository
+	paymentRepo    ports.PaymentRepository
+	creditNoteRepo ports.CreditNoteRepository
+	txManager      ports.TransactionManager
+	clock          ports.Clock
+}
+
+func NewCalculateLateInterestUseCase(
+	ir ports.InvoiceRepository,
+	cr ports.CustomerRepository,
+	ar ports.AllocationRepository,
+	pr ports.PaymentRepository,
+	cnr ports.CreditNoteRepository,
+	tm ports.TransactionManager,
+	clk ports.Clock,
+) *CalculateLateInterestUseCase {
+	return &CalculateLateInterestUseCase{
+		invoiceRepo:    ir,
+		customerRepo:   cr,
+		allocationRepo: ar,
+		paymentRepo:    pr,
+		creditNoteRepo: cnr,
+		txManager:      tm,
+		clock:          clk,
+	}
+}
+
+func (uc *CalculateLateInterestUseCase) Execute(ctx context.Context, req dto.LateInterestRequest) (*dto.LateInterestReport, error) {
+	asOf := req.AsOf
+	if asOf.IsZero() {
+		asOf = uc.clock.Now()
+	}
+
+	var interests []*domain.LateInterest
+	report := &dto.LateInterestReport{
+		AsOf:     domain.RateDay(asOf),
+		Items:    []dto.LateInterestItem{},
+		Invoices: []dto.LateInterestInvoice{},
+	}
+
+	err := uc.txManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		interests, err = uc.collect(ctx, req.CustomerID, asOf)
+		if err != nil {
+			return err
+		}
+		if !req.IssueInvoices {
+			return nil
+		}
+		report.Invoices, err = uc.issueInvoices(ctx, interests, req.DueDate)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, li := range interests {
+		report.Items = append(report.Items, mapLateInterest(li))
+	}
+	return report, nil
+}
+
+// collect calculates the interest on every invoice of the selected customers
+// that has any. Late interest invoices do not accrue interest themselves.
+func (uc *CalculateLateInterestUseCase) collect(ctx context.Context, customerID string, asOf time.Time) ([]*domain.LateInterest, error) {
+	var customers []*domain.Customer
+	if customerID != "" {
+		customer, err := uc.customerRepo.FindByID(ctx, domain.CustomerID(customerID))
+		if err != nil {
+			return nil, err
+		}
+		customers = []*domain.Customer{customer}
+	} else {
+		var err error
+		if customers, err = uc.customerRepo.FindAll(ctx); err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(customers, func(i, j int) bool { return customers[i].ID < customers[j].ID })
+
+	var interests []*domain.LateInterest
+	for _, customer := range customers {
+		if customer.LateInterest.IsZero() {
+			continue
+		}
+		invoices, err := uc.invoiceRepo.FindByCustomer(ctx, customer.ID)
+		if err != nil {
+			return nil, err
+		}
+		sort.SliceStable(invoices, func(i, j int) bool {
+			return invoices[i].IssueDate.Before(invoices[j].IssueDate)
+		})
+
+		for _, inv := range invoices {
+			if inv.Status == domain.InvoiceStatusVoid || inv.Kind == domain.InvoiceKindLateInterest {
+				continue
+			}
+			if domain.RateDay(inv.IssueDate).After(domain.RateDay(asOf)) {
+				continue
+			}
+			settlements, err := uc.settlements(ctx, inv, asOf)
+			if err != nil {
+				return nil, err
+			}
+			li, err := domain.NewLateInterest(inv, settlements, customer.LateInterest, asOf)
+			if err != nil {
+				return nil, err
+			}
+			if !li.Total.IsZero() {
+				interests = append(interests, li)
+			}
+		}
+	}
+	return interests, nil
+}
+
+// settlements dates every allocation that settled part of the invoice as of
+// asOf by the day its payment was received or its credit note issued.
+// Allocations made after asOf, or reversed by then, are left out, so a past
+// asOf always gives the answer it gave on that day.
+func (uc *CalculateLateInterestUseCase) settlements(ctx context.Context, inv *domain.Invoice, asOf time.Time) ([]domain.InvoiceSettlement, error) {
+	allocations, err := uc.allocationRepo.FindByInvoice(ctx, inv.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	day := domain.RateDay(asOf)
+	reversedOn := map[domain.AllocationID]time.Time{}
+	for _, a := range allocations {
+		if a.Type == domain.AllocationTypeReversal {
+			reversedOn[a.ReversalOf] = domain.RateDay(a.CreatedAt)
+		}
+	}
+
+	var settlements []domain.InvoiceSettlement
+	for _, a := range allocations {
+		if a.Type == domain.AllocationTypeReversal || domain.RateDay(a.CreatedAt).After(day) {
+			continue
+		}
+		if on, ok := reversedOn[a.ID]; ok && !on.After(day) {
+			continue
+		}
+		source, err := findAllocationSource(ctx, a, uc.paymentRepo, uc.creditNoteRepo)
+		if err != nil {
+			return nil, err
+		}
+		date, err := allocationSourceDate(source)
+		if err != nil {
+			return nil, err
+		}
+		settlements = append(settlements, domain.InvoiceSettlement{Date: date, Amount: a.InvoiceAmount})
+	}
+	return settlements, nil
+}
+
+// issueInvoices bills each customer's interest with one vade farkı invoice
+// per currency.
+func (uc *CalculateLateInterestUseCase) issueInvoices(ctx context.Context, interests []*domain.LateInterest, dueDate time.Time) ([]dto.LateInterestInvoice, error) {
+	type key struct {
+		customerID domain.CustomerID
+		currency   string
+	}
+	var keys []key
+	groups := map[key][]*domain.LateInterest{}
+	for _, li := range interests {
+		k := key{li.Invoice.CustomerID, li.Total.Currency()}
+		if _, ok := groups[k]; !ok {
+			keys = append(keys, k)
+		}
+		groups[k] = append(groups[k], li)
+	}
+
+	now := uc.clock.Now()
+	if dueDate.IsZero() {
+		dueDate = now
+	}
+
+	issued := []dto.LateInterestInvoice{}
+	for i, k := range keys {
+		id := domain.InvoiceID(fmt.Sprintf("VF-%d-%d", now.UnixNano(), i+1))
+		inv, err := domain.NewLateInterestInvoice(id, k.customerID, groups[k], now, dueDate)
+		if err != nil {
+			return nil, err
+		}
+		if err := uc.invoiceRepo.Save(ctx, inv); err != nil {
+			return nil, err
+		}
+
+		item := dto.LateInterestInvoice{
+			InvoiceID:  string(inv.ID),
+			CustomerID: string(k.customerID),
+			Amount:     inv.TotalAmount.Decimal(),
+			Currency:   inv.TotalAmount.Currency(),
+		}
+		for _, li := range groups[k] {
+			if err := uc.invoiceRepo.Save(ctx, li.Invoice); err != nil {
+				return nil, err
+			}
+			item.InvoiceIDs = append(item.InvoiceIDs, string(li.Invoice.ID))
+		}
+		issued = append(issued, item)
+	}
+	return issued, nil
+}
+
+func mapLateInterest(li *domain.LateInterest) dto.LateInterestItem {
+	item := dto.LateInterestItem{
+		InvoiceID:  string(li.Invoice.ID),
+		CustomerID: string(li.Invoice.CustomerID),
+		Currency:   li.Total.Currency(),
+		Rate:       domain.FormatRate(li.Rate.Percent),
+		Period:     string(li.Rate.Period),
+		Portions:   make([]dto.LateInterestPortion, len(li.Portions)),
+		Interest:   li.Total.Decimal(),
+	}
+	for i, p := range li.Portions {
+		item.Portions[i] = dto.LateInterestPortion{
+			Instalment: p.Instalment,
+			DueDate:    p.DueDate,
+			Since:      p.Since,
+			Until:      p.Until,
+			Days:       p.Days,
+			Principal:  p.Principal.Decimal(),
+			Interest:   p.Interest.Decimal(),
+			Settled:    p.Settled,
+		}
+	}
+	return item
+}
@@ -0,0 +1,150 @@
+package usecases
+
+import (
+	"carigo/internal/application/dto"
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"context"
+	"fmt"
+)
+
+// ConfirmAllocationUseCase books a previously proposed allocation plan.
+// The plan is refused if the customer's open invoices changed in the meantime.
+type ConfirmAllocationUseCase struct {
+	paymentRepo    ports.PaymentRepository
+	invoiceRepo    ports.InvoiceRepository
+	allocationRepo ports.AllocationRepository
+	planStore      ports.AllocationPlanStore
+	txManager      ports.TransactionManager
+	clock          ports.Clock
+}
+
+func NewConfirmAllocationUseCase(
+	pr ports.PaymentRepository,
+	ir ports.InvoiceRepository,
+	ar ports.AllocationRepository,
+	ps ports.AllocationPlanStore,
+	tm ports.TransactionManager,
+	clk ports.Clock,
+) *ConfirmAllocationUseCase {
+	return &ConfirmAllocationUseCase{
+		paymentRepo:    pr,
+		invoiceRepo:    ir,
+		allocationRepo: ar,
+		planStore:      ps,
+		txManager:      tm,
+		clock:          clk,
+	}
+}
+
+func (uc *ConfirmAllocationUseCase) Execute(ctx context.Context, planID string) (*dto.RegisterPaymentResponse, error) {
+	plan, err := uc.planStore.FindByID(ctx, domain.AllocationPlanID(planID))
+	if err != nil {
+		return nil, err
+	}
+
+	// Like RegisterPayment, the ID comes from the clock: plans of the same
+	// payment date would otherwise share it.
+	paymentID := domain.PaymentID(fmt.Sprintf("PAY-%d", uc.clock.Now().UnixNano()))
+	payment := domain.NewPayment(paymentID, plan.CustomerID, plan.Amount, plan.Date)
+
+	var allocatedItems []dto.AllocatedInvoiceParams
+
+	err = uc.txManager.Do(ctx, func(ctx context.Context) error {
+		invoices, err := uc.invoiceRepo.FindOpenByCustomer(ctx, plan.CustomerID)
+		if err != nil {
+			return err
+		}
+		if plan.IsStale(invoices) {
+			return domain.ErrAllocationPlanStale
+		}
+
+		allocatedItems, err = bookAllocationPlan(ctx, plan, payment, string(payment.ID), invoices, uc.invoiceRepo, uc.allocationRepo)
+		if err != nil {
+			return err
+		}
+		return uc.paymentRepo.Save(ctx, payment)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.planStore.Delete(ctx, plan.ID); err != nil {
+		return nil, err
+	}
+
+	return &dto.RegisterPaymentResponse{
+		PaymentID:         string(payment.ID),
+		AllocatedAmount:   plan.AllocatedAmount().Amount(),
+		RemainingBalance:  payment.AvailableAmount.Amount(),
+		AllocatedInvoices: allocatedItems,
+	}, nil
+}
+
+// bookAllocationPlan turns each plan line into an Allocation drawn from source
+// and persists the affected invoices and allocations. A line's early-payment
+// discount is booked as a separate discount allocation of the payment. The
+// caller saves the source. It must run inside a transaction.
+func bookAllocationPlan(
+	ctx context.Context,
+	plan *domain.AllocationPlan,
+	source domain.AllocationSource,
+	sourceID string,
+	invoices []*domain.Invoice,
+	ir ports.InvoiceRepository,
+	ar ports.AllocationRepository,
+) ([]dto.AllocatedInvoiceParams, error) {
+	byID := make(map[domain.InvoiceID]*domain.Invoice, len(invoices))
+	for _, inv := range invoices {
+		byID[inv.ID] = inv
+	}
+
+	allocatedItems := []dto.AllocatedInvoiceParams{}
+	for _, line := range plan.Lines {
+		inv, ok := byID[line.InvoiceID]
+		if !ok {
+			return nil, domain.ErrAllocationPlanStale
+		}
+
+		allocID := domain.AllocationID(fmt.Sprintf("AL-%s-%s", sourceID, inv.ID))
+		var allocation *domain.Allocation
+		var err error
+		if line.Rate != nil {
+			allocation, err = domain.NewAllocationAtRate(allocID, source, inv, line.Amount, line.Rate)
+		} else {
+			allocation, err = domain.NewAllocation(allocID, source, inv, line.Amount)
+		}
+		if err != nil {
+			return nil, err
+		}
+		item := mapAllocatedInvoice(allocation)
+
+		var discount *domain.Allocation
+		if !line.Discount.IsZero() {
+			payment, ok := source.(*domain.Payment)
+			if !ok {
+				return nil, fmt.Errorf("early-payment discount on %T", source)
+			}
+			discount, err = domain.NewDiscountAllocation(allocID+"-ISK", payment, inv, line.Discount)
+			if err != nil {
+				return nil, err
+			}
+			item.Discount = discount.InvoiceAmount.Decimal()
+		}
+
+		if err := ir.Save(ctx, inv); err != nil {
+			return nil, err
+		}
+		if err := ar.Save(ctx, allocation); err != nil {
+			return nil, err
+		}
+		if discount != nil {
+			if err := ar.Save(ctx, discount); err != nil {
+				return nil, err
+			}
+		}
+
+		allocatedItems = append(allocatedItems, item)
+	}
+	return allocatedItems, nil
+}
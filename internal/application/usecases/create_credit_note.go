@@ -0,0 +1,151 @@
+package usecases
+
+import (
+	"carigo/internal/application/dto"
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"context"
+	"fmt"
+)
+
+// CreateCreditNoteUseCase issues a credit note and allocates it like a payment.
+// When the note refers to an invoice, that invoice is settled first; the rest
+// follows the customer's allocation strategy and any remainder stays on account.
+type CreateCreditNoteUseCase struct {
+	creditNoteRepo ports.CreditNoteRepository
+	invoiceRepo    ports.InvoiceRepository
+	allocationRepo ports.AllocationRepository
+	customerRepo   ports.CustomerRepository
+	rates          ports.ExchangeRateProvider
+	txManager      ports.TransactionManager
+	clock          ports.Clock
+}
+
+func NewCreateCreditNoteUseCase(
+	cnr ports.CreditNoteRepository,
+	ir ports.InvoiceRepository,
+	ar ports.AllocationRepository,
+	cr ports.CustomerRepository,
+	rp ports.ExchangeRateProvider,
+	tm ports.TransactionManager,
+	clk ports.Clock,
+) *CreateCreditNoteUseCase {
+	return &CreateCreditNoteUseCase{
+		creditNoteRepo: cnr,
+		invoiceRepo:    ir,
+		allocationRepo: ar,
+		customerRepo:   cr,
+		rates:          rp,
+		txManager:      tm,
+		clock:          clk,
+	}
+}
+
+func (uc *CreateCreditNoteUseCase) Execute(ctx context.Context, req dto.CreateCreditNoteRequest) (*dto.CreateCreditNoteResponse, error) {
+	amount, err := domain.NewMoney(req.Amount, req.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("invalid money: %w", err)
+	}
+
+	date := req.Date
+	if date.IsZero() {
+		date = uc.clock.Now()
+	}
+
+	customerID := domain.CustomerID(req.CustomerID)
+	strategy, err := resolveAllocationStrategy(ctx, uc.customerRepo, customerID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	noteID := domain.CreditNoteID(fmt.Sprintf("CN-%d", uc.clock.Now().UnixNano()))
+	note, err := domain.NewCreditNote(noteID, customerID, domain.InvoiceID(req.InvoiceID), amount, date, req.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	allocatedItems := []dto.AllocatedInvoiceParams{}
+
+	err = uc.txManager.Do(ctx, func(ctx context.Context) error {
+		if note.InvoiceID != "" {
+			item, err := uc.applyToLinkedInvoice(ctx, note)
+			if err != nil {
+				return err
+			}
+			if item != nil {
+				allocatedItems = append(allocatedItems, *item)
+			}
+		}
+
+		if !note.AvailableAmount.IsZero() {
+			invoices, err := uc.invoiceRepo.FindOpenByCustomer(ctx, customerID)
+			if err != nil {
+				return err
+			}
+			rates, err := ratesForInvoices(ctx, uc.rates, amount.Currency(), invoices, date)
+			if err != nil {
+				return err
+			}
+			plan, err := domain.NewAllocationPlan(domain.AllocationPlanID(noteID), customerID, note.AvailableAmount, date, invoices, strategy, rates)
+			if err != nil {
+				return err
+			}
+			items, err := bookAllocationPlan(ctx, plan, note, string(note.ID), invoices, uc.invoiceRepo, uc.allocationRepo)
+			if err != nil {
+				return err
+			}
+			allocatedItems = append(allocatedItems, items...)
+		}
+
+		return uc.creditNoteRepo.Save(ctx, note)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	allocated, _ := note.Amount.Subtract(note.AvailableAmount)
+	return &dto.CreateCreditNoteResponse{
+		CreditNoteID:      string(note.ID),
+		AllocatedAmount:   allocated.Amount(),
+		RemainingBalance:  note.AvailableAmount.Amount(),
+		AllocatedInvoices: allocatedItems,
+	}, nil
+}
+
+// applyToLinkedInvoice settles as much as possible of the invoice the note was
+// issued for. A settled or voided invoice is left alone.
+func (uc *CreateCreditNoteUseCase) applyToLinkedInvoice(ctx context.Context, note *domain.CreditNote) (*dto.AllocatedInvoiceParams, error) {
+	inv, err := uc.invoiceRepo.FindByID(ctx, note.InvoiceID)
+	if err != nil {
+		return nil, err
+	}
+	if inv.CustomerID != note.CustomerID {
+		return nil, domain.ErrCustomerMismatch
+	}
+	if inv.Status == domain.InvoiceStatusPaid || inv.Status == domain.InvoiceStatusVoid {
+		return nil, nil
+	}
+
+	amount := note.AvailableAmount
+	remainingDebt := inv.RemainingAmount()
+	if remainingDebt.Currency() == amount.Currency() {
+		if isCreditLarger, _ := amount.GreaterThan(remainingDebt); isCreditLarger {
+			amount = remainingDebt
+		}
+	}
+
+	allocID := domain.AllocationID(fmt.Sprintf("AL-%s-%s", note.ID, inv.ID))
+	allocation, err := allocateAcrossCurrencies(ctx, uc.rates, allocID, note, inv, amount, note.Date)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.invoiceRepo.Save(ctx, inv); err != nil {
+		return nil, err
+	}
+	if err := uc.allocationRepo.Save(ctx, allocation); err != nil {
+		return nil, err
+	}
+
+	item := mapAllocatedInvoice(allocation)
+	return &item, nil
+}
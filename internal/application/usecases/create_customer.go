@@ -6,6 +6,7 @@ import (
 	"carigo/internal/domain"
 	"context"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -19,11 +20,35 @@ func NewCreateCustomerUseCase(repo ports.CustomerRepository) *CreateCustomerUseC
 
 func (uc *CreateCustomerUseCase) Execute(ctx context.Context, req dto.CreateCustomerRequest) (*dto.CreateCustomerResponse, error) {
 	id := domain.CustomerID(fmt.Sprintf("CUST-%d", time.Now().UnixNano()))
-	
+
 	customer, err := domain.NewCustomer(id, req.Name, req.Email, req.TaxID)
 	if err != nil {
 		return nil, err
 	}
+	if err := customer.SetAllocationStrategy(domain.AllocationStrategyName(req.AllocationStrategy)); err != nil {
+		return nil, err
+	}
+	rate, err := domain.ParseLateInterestRate(req.LateInterestRate, req.LateInterestPeriod)
+	if err != nil {
+		return nil, err
+	}
+	customer.SetLateInterestRate(rate)
+	if req.PaymentTerms != nil {
+		terms, err := parsePaymentTerms(*req.PaymentTerms)
+		if err != nil {
+			return nil, err
+		}
+		customer.SetPaymentTerms(terms)
+	}
+	for currency, amount := range req.CreditLimits {
+		limit, err := domain.NewMoney(amount, currency)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", domain.ErrInvalidCreditLimit, err)
+		}
+		if err := customer.SetCreditLimit(limit); err != nil {
+			return nil, err
+		}
+	}
 
 	if err := uc.repo.Save(ctx, customer); err != nil {
 		return nil, err
@@ -35,3 +60,16 @@ func (uc *CreateCustomerUseCase) Execute(ctx context.Context, req dto.CreateCust
 		Email: customer.Email,
 	}, nil
 }
+
+func parsePaymentTerms(req dto.PaymentTermsRequest) (domain.PaymentTerms, error) {
+	discount, err := domain.ParseDiscountPercent(req.DiscountPercent)
+	if err != nil {
+		return domain.PaymentTerms{}, err
+	}
+	basis := domain.TermsBasis(strings.ToUpper(strings.TrimSpace(req.Basis)))
+	days := req.NetDays
+	if basis == domain.TermsBasisDayOfNextMonth {
+		days = req.DayOfMonth
+	}
+	return domain.NewPaymentTerms(basis, days, req.Instalments, req.BusinessDays, discount, req.DiscountDays)
+}
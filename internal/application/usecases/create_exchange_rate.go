@@ -0,0 +1,56 @@
+package usecases
+
+import (
+	"carigo/internal/application/dto"
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"context"
+	"strings"
+)
+
+// CreateExchangeRateUseCase records a rate entered by hand. A rate for the same
+// pair and day is replaced.
+type CreateExchangeRateUseCase struct {
+	rateRepo ports.ExchangeRateRepository
+	clock    ports.Clock
+}
+
+func NewCreateExchangeRateUseCase(rr ports.ExchangeRateRepository, clk ports.Clock) *CreateExchangeRateUseCase {
+	return &CreateExchangeRateUseCase{
+		rateRepo: rr,
+		clock:    clk,
+	}
+}
+
+func (uc *CreateExchangeRateUseCase) Execute(ctx context.Context, req dto.CreateExchangeRateRequest) (*dto.ExchangeRateDTO, error) {
+	value, err := domain.ParseRate(req.Rate)
+	if err != nil {
+		return nil, err
+	}
+
+	date := req.Date
+	if date.IsZero() {
+		date = uc.clock.Now()
+	}
+
+	rate, err := domain.NewExchangeRate(strings.ToUpper(req.From), strings.ToUpper(req.To), value, date, domain.ExchangeRateSourceManual)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.rateRepo.Save(ctx, rate); err != nil {
+		return nil, err
+	}
+
+	res := mapExchangeRate(rate)
+	return &res, nil
+}
+
+func mapExchangeRate(r *domain.ExchangeRate) dto.ExchangeRateDTO {
+	return dto.ExchangeRateDTO{
+		From:   r.From,
+		To:     r.To,
+		Rate:   r.String(),
+		Date:   r.Date.Format("2006-01-02"),
+		Source: r.Source,
+	}
+}
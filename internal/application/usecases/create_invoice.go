@@ -5,43 +5,313 @@ import (
 	"carigo/internal/application/ports"
 	"carigo/internal/domain"
 	"context"
+	"errors"
 	"fmt"
 )
 
 type CreateInvoiceUseCase struct {
-	invoiceRepo ports.InvoiceRepository
-	clock       ports.Clock
+	invoiceRepo    ports.InvoiceRepository
+	customerRepo   ports.CustomerRepository
+	paymentRepo    ports.PaymentRepository
+	creditNoteRepo ports.CreditNoteRepository
+	allocationRepo ports.AllocationRepository
+	rates          ports.ExchangeRateProvider
+	txManager      ports.TransactionManager
+	creditPolicy   domain.CreditPolicy
+	clock          ports.Clock
 }
 
-func NewCreateInvoiceUseCase(ir ports.InvoiceRepository, clk ports.Clock) *CreateInvoiceUseCase {
+func NewCreateInvoiceUseCase(
+	ir ports.InvoiceRepository,
+	cr ports.CustomerRepository,
+	pr ports.PaymentRepository,
+	cnr ports.CreditNoteRepository,
+	ar ports.AllocationRepository,
+	rp ports.ExchangeRateProvider,
+	tm ports.TransactionManager,
+	policy domain.CreditPolicy,
+	clk ports.Clock,
+) *CreateInvoiceUseCase {
 	return &CreateInvoiceUseCase{
-		invoiceRepo: ir,
-		clock:       clk,
+		invoiceRepo:    ir,
+		customerRepo:   cr,
+		paymentRepo:    pr,
+		creditNoteRepo: cnr,
+		allocationRepo: ar,
+		rates:          rp,
+		txManager:      tm,
+		creditPolicy:   policy,
+		clock:          clk,
 	}
 }
 
 func (uc *CreateInvoiceUseCase) Execute(ctx context.Context, req dto.CreateInvoiceRequest) (*dto.CreateInvoiceResponse, error) {
-	total, err := domain.NewMoney(req.Amount, req.Currency)
+	issueDate := uc.clock.Now()
+	id := domain.InvoiceID(fmt.Sprintf("INV-%d", issueDate.UnixNano()))
+
+	customer, err := uc.customerRepo.FindByID(ctx, domain.CustomerID(req.CustomerID))
 	if err != nil {
-		return nil, fmt.Errorf("invalid amount: %w", err)
+		return nil, err
+	}
+	terms := customer.PaymentTerms
+	if req.PaymentTerms != nil {
+		if terms, err = parsePaymentTerms(*req.PaymentTerms); err != nil {
+			return nil, err
+		}
+	}
+	dueDate := req.DueDate
+	if dueDate.IsZero() && len(req.Instalments) == 0 {
+		if terms.IsZero() {
+			return nil, domain.ErrDueDateRequired
+		}
+		dueDate = terms.DueDate(issueDate)
 	}
 
-	id := domain.InvoiceID(fmt.Sprintf("INV-%d", uc.clock.Now().UnixNano()))
+	var inv *domain.Invoice
+	if len(req.Lines) > 0 {
+		if req.Amount != 0 {
+			return nil, fmt.Errorf("%w: amount is derived from the lines", domain.ErrInvalidAmount)
+		}
+		lines, err := buildInvoiceLines(req.Lines, req.Currency)
+		if err != nil {
+			return nil, err
+		}
+		inv, err = domain.NewInvoiceWithLines(id, domain.CustomerID(req.CustomerID), lines, issueDate, dueDate)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		total, err := domain.NewMoney(req.Amount, req.Currency)
+		if err != nil {
+			return nil, fmt.Errorf("invalid amount: %w", err)
+		}
+		inv, err = domain.NewInvoice(id, domain.CustomerID(req.CustomerID), total, issueDate, dueDate)
+		if err != nil {
+			return nil, err
+		}
+	}
+	inv.Terms = terms
 
-	inv, err := domain.NewInvoice(id, domain.CustomerID(req.CustomerID), total, uc.clock.Now(), req.DueDate)
-	if err != nil {
+	if err := applyInstalments(inv, req); err != nil {
 		return nil, err
 	}
 
-	if err := uc.invoiceRepo.Save(ctx, inv); err != nil {
+	appliedCredits := []dto.AppliedCreditParams{}
+	var creditWarnings []string
+
+	err = uc.txManager.Do(ctx, func(ctx context.Context) error {
+		if err := uc.invoiceRepo.Save(ctx, inv); err != nil {
+			return err
+		}
+
+		applied, err := uc.applyOnAccountCredit(ctx, inv)
+		if err != nil {
+			return err
+		}
+		appliedCredits = applied
+
+		if len(applied) > 0 {
+			if err := uc.invoiceRepo.Save(ctx, inv); err != nil {
+				return err
+			}
+		}
+
+		creditWarnings, err = uc.checkCredit(ctx, customer, inv)
+		return err
+	})
+	if err != nil {
 		return nil, err
 	}
 
+	totals := inv.Totals()
+	var instalments []dto.InstalmentParams
+	for n, inst := range inv.Instalments {
+		instalments = append(instalments, dto.InstalmentParams{
+			Number:  n + 1,
+			DueDate: inst.DueDate,
+			Amount:  inst.Amount.Amount(),
+		})
+	}
 	return &dto.CreateInvoiceResponse{
-		InvoiceID:   string(inv.ID),
-		TotalAmount: inv.TotalAmount.Amount(),
-		Currency:    inv.TotalAmount.Currency(),
-		Status:      string(inv.Status),
-		DueDate:     inv.DueDate,
+		InvoiceID:      string(inv.ID),
+		TotalAmount:    inv.TotalAmount.Amount(),
+		PaidAmount:     inv.PaidAmount.Amount(),
+		Subtotal:       totals.Subtotal.Amount(),
+		VATAmount:      totals.VAT.Amount(),
+		Withholding:    totals.Withholding.Amount(),
+		Currency:       inv.TotalAmount.Currency(),
+		Status:         string(inv.Status),
+		DueDate:        inv.DueDate,
+		PaymentTerms:   termsString(inv.Terms),
+		Instalments:    instalments,
+		AppliedCredits: appliedCredits,
+		CreditWarnings: creditWarnings,
 	}, nil
 }
+
+// checkCredit holds the new invoice, already saved with on-account credit
+// applied, against the credit policy. A rejecting policy fails the invoice;
+// a flagging one lets it through with the reasons as warnings.
+func (uc *CreateInvoiceUseCase) checkCredit(ctx context.Context, customer *domain.Customer, inv *domain.Invoice) ([]string, error) {
+	risk, err := assessCreditRisk(ctx, uc.invoiceRepo, customer, uc.creditPolicy, uc.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	breaches := risk.Breaches(inv.TotalAmount.Currency())
+	if len(breaches) == 0 {
+		return nil, nil
+	}
+	if uc.creditPolicy.Action != domain.CreditActionFlag {
+		return nil, errors.Join(breaches...)
+	}
+	warnings := make([]string, len(breaches))
+	for i, b := range breaches {
+		warnings[i] = b.Error()
+	}
+	return warnings, nil
+}
+
+func buildInvoiceLines(reqs []dto.InvoiceLineRequest, currency string) ([]domain.InvoiceLine, error) {
+	lines := make([]domain.InvoiceLine, 0, len(reqs))
+	for _, r := range reqs {
+		quantity, err := domain.ParseQuantity(r.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		unitPrice, err := domain.NewMoney(r.UnitPrice, currency)
+		if err != nil {
+			return nil, err
+		}
+		discount, err := domain.NewMoney(r.Discount, currency)
+		if err != nil {
+			return nil, err
+		}
+		withholding, err := domain.ParseWithholdingRatio(r.Withholding)
+		if err != nil {
+			return nil, err
+		}
+		line, err := domain.NewInvoiceLine(r.Description, quantity, unitPrice, discount, domain.VATRate(r.VATRate), withholding)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
+
+// applyInstalments gives the invoice the schedule asked for: an even split
+// starting at the due date, or the instalments as entered. Without either,
+// the invoice is split as its payment terms say, if they say so.
+func applyInstalments(inv *domain.Invoice, req dto.CreateInvoiceRequest) error {
+	if req.InstalmentCount > 0 && len(req.Instalments) > 0 {
+		return fmt.Errorf("%w: give either instalment_count or instalments", domain.ErrInvalidInstalments)
+	}
+
+	var (
+		schedule []domain.Instalment
+		err      error
+	)
+	switch {
+	case req.InstalmentCount > 0:
+		schedule, err = domain.NewEvenInstalments(inv.TotalAmount, req.InstalmentCount, inv.DueDate)
+	case len(req.Instalments) > 0:
+		for _, r := range req.Instalments {
+			amount, err := domain.NewMoney(r.Amount, req.Currency)
+			if err != nil {
+				return err
+			}
+			schedule = append(schedule, domain.Instalment{DueDate: r.DueDate, Amount: amount})
+		}
+	case inv.Terms.Instalments > 0 && req.DueDate.IsZero():
+		schedule, err = inv.Terms.Schedule(inv.TotalAmount, inv.IssueDate)
+	case inv.Terms.Instalments > 0:
+		schedule, err = domain.NewEvenInstalments(inv.TotalAmount, inv.Terms.Instalments, req.DueDate)
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return inv.SetInstalments(schedule)
+}
+
+// applyOnAccountCredit settles the new invoice from the customer's unallocated
+// payments, oldest payment first, and then from open credit notes. Credit in
+// another currency is converted at the rate of the invoice date, if one is known.
+func (uc *CreateInvoiceUseCase) applyOnAccountCredit(ctx context.Context, inv *domain.Invoice) ([]dto.AppliedCreditParams, error) {
+	payments, err := uc.paymentRepo.FindUnallocatedByCustomer(ctx, inv.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+	notes, err := uc.creditNoteRepo.FindUnallocatedByCustomer(ctx, inv.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := []dto.AppliedCreditParams{}
+	apply := func(source domain.AllocationSource, sourceID string, available domain.Money) (*domain.Allocation, error) {
+		remainingDebt := inv.RemainingAmount()
+		if remainingDebt.IsZero() {
+			return nil, nil
+		}
+
+		amount := available
+		if available.Currency() == remainingDebt.Currency() {
+			if isCreditLarger, _ := amount.GreaterThan(remainingDebt); isCreditLarger {
+				amount = remainingDebt
+			}
+		}
+
+		allocID := domain.AllocationID(fmt.Sprintf("AL-%s-%s", sourceID, inv.ID))
+		allocation, err := allocateAcrossCurrencies(ctx, uc.rates, allocID, source, inv, amount, inv.IssueDate)
+		if errors.Is(err, domain.ErrExchangeRateNotFound) || errors.Is(err, domain.ErrNegativeAmount) {
+			// No rate, or the credit is too small to settle anything once converted.
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := saveAllocationSource(ctx, source, uc.paymentRepo, uc.creditNoteRepo); err != nil {
+			return nil, err
+		}
+		if err := uc.allocationRepo.Save(ctx, allocation); err != nil {
+			return nil, err
+		}
+		return allocation, nil
+	}
+
+	for _, payment := range payments {
+		allocation, err := apply(payment, string(payment.ID), payment.AvailableAmount)
+		if err != nil {
+			return nil, err
+		}
+		if allocation != nil {
+			applied = append(applied, dto.AppliedCreditParams{
+				AllocationID: string(allocation.ID),
+				PaymentID:    string(payment.ID),
+				Amount:       allocation.Amount.Amount(),
+			})
+		}
+	}
+	for _, note := range notes {
+		allocation, err := apply(note, string(note.ID), note.AvailableAmount)
+		if err != nil {
+			return nil, err
+		}
+		if allocation != nil {
+			applied = append(applied, dto.AppliedCreditParams{
+				AllocationID: string(allocation.ID),
+				CreditNoteID: string(note.ID),
+				Amount:       allocation.Amount.Amount(),
+			})
+		}
+	}
+	return applied, nil
+}
+
+func termsString(terms domain.PaymentTerms) string {
+	if terms.IsZero() {
+		return ""
+	}
+	return terms.String()
+}
@@ -0,0 +1,52 @@
+package usecases
+
+import (
+	"carigo/internal/application/dto"
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"context"
+	"fmt"
+)
+
+// CreateReconciliationUseCase snapshots a customer's statement balances as of
+// a day and asks the customer to confirm them.
+type CreateReconciliationUseCase struct {
+	repo     ports.ReconciliationRepository
+	custRepo ports.CustomerRepository
+	stmtRepo ports.StatementRepository
+	clock    ports.Clock
+}
+
+func NewCreateReconciliationUseCase(r ports.ReconciliationRepository, c ports.CustomerRepository, s ports.StatementRepository, clk ports.Clock) *CreateReconciliationUseCase {
+	return &CreateReconciliationUseCase{repo: r, custRepo: c, stmtRepo: s, clock: clk}
+}
+
+func (uc *CreateReconciliationUseCase) Execute(ctx context.Context, customerID string, req dto.CreateReconciliationRequest) (*dto.ReconciliationDTO, error) {
+	customer, err := uc.custRepo.FindByID(ctx, domain.CustomerID(customerID))
+	if err != nil {
+		return nil, err
+	}
+
+	now := uc.clock.Now()
+	asOf := req.AsOf
+	if asOf.IsZero() {
+		asOf = now
+	}
+	// The balances at the end of the day of asOf.
+	balances, err := uc.stmtRepo.BalancesBefore(ctx, customer.ID, domain.RateDay(asOf).AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	id := domain.ReconciliationID(fmt.Sprintf("REC-%d", now.UnixNano()))
+	r, err := domain.NewReconciliation(id, customer.ID, asOf, balances, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.repo.Save(ctx, r); err != nil {
+		return nil, err
+	}
+
+	res := mapReconciliation(r, customer.Name)
+	return &res, nil
+}
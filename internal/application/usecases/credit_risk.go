@@ -0,0 +1,34 @@
+package usecases
+
+import (
+	"carigo/internal/application/dto"
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"context"
+	"time"
+)
+
+// assessCreditRisk looks at the customer's open invoices under policy.
+func assessCreditRisk(ctx context.Context, invoices ports.InvoiceRepository, customer *domain.Customer, policy domain.CreditPolicy, now time.Time) (domain.CreditRisk, error) {
+	open, err := invoices.FindOpenByCustomer(ctx, customer.ID)
+	if err != nil {
+		return domain.CreditRisk{}, err
+	}
+	return domain.NewCreditRisk(customer, open, policy, now)
+}
+
+func mapCreditRisk(r domain.CreditRisk) *dto.CreditRiskDTO {
+	res := &dto.CreditRiskDTO{
+		Risky:           r.IsRisky(),
+		Exposure:        make(map[string]string, len(r.Exposure)),
+		OverLimit:       append([]string{}, r.OverLimit...),
+		OverdueInvoices: make([]string, len(r.Overdue)),
+	}
+	for currency, exposure := range r.Exposure {
+		res.Exposure[currency] = exposure.Decimal()
+	}
+	for i, id := range r.Overdue {
+		res.OverdueInvoices[i] = string(id)
+	}
+	return res
+}
@@ -0,0 +1,79 @@
+package usecases
+
+import (
+	"carigo/internal/application/dto"
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"context"
+)
+
+// DetectOverdueInvoicesUseCase records an OverdueEvent for every instalment
+// that became overdue since the last run. Running it again is harmless:
+// instalments that already have an event are skipped.
+type DetectOverdueInvoicesUseCase struct {
+	invoiceRepo ports.InvoiceRepository
+	eventRepo   ports.OverdueEventRepository
+	txManager   ports.TransactionManager
+	clock       ports.Clock
+}
+
+func NewDetectOverdueInvoicesUseCase(ir ports.InvoiceRepository, er ports.OverdueEventRepository, tm ports.TransactionManager, clk ports.Clock) *DetectOverdueInvoicesUseCase {
+	return &DetectOverdueInvoicesUseCase{
+		invoiceRepo: ir,
+		eventRepo:   er,
+		txManager:   tm,
+		clock:       clk,
+	}
+}
+
+func (uc *DetectOverdueInvoicesUseCase) Execute(ctx context.Context) (*dto.OverdueDetectionResult, error) {
+	now := uc.clock.Now()
+	result := &dto.OverdueDetectionResult{
+		CheckedAt: now,
+		NewEvents: []dto.OverdueEventDTO{},
+	}
+
+	err := uc.txManager.Do(ctx, func(ctx context.Context) error {
+		invoices, err := uc.invoiceRepo.FindOverdue(ctx, now)
+		if err != nil {
+			return err
+		}
+		result.OverdueInvoices = len(invoices)
+
+		for _, inv := range invoices {
+			existing, err := uc.eventRepo.FindByInvoice(ctx, inv.ID)
+			if err != nil {
+				return err
+			}
+			recorded := make(map[int]bool, len(existing))
+			for _, e := range existing {
+				recorded[e.Instalment] = true
+			}
+
+			for _, event := range inv.NewOverdueEvents(now, recorded) {
+				if err := uc.eventRepo.Save(ctx, event); err != nil {
+					return err
+				}
+				result.NewEvents = append(result.NewEvents, mapOverdueEvent(event))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func mapOverdueEvent(e *domain.OverdueEvent) dto.OverdueEventDTO {
+	return dto.OverdueEventDTO{
+		ID:         string(e.ID),
+		InvoiceID:  string(e.InvoiceID),
+		CustomerID: string(e.CustomerID),
+		Instalment: e.Instalment,
+		DueDate:    e.DueDate.Format("2006-01-02"),
+		Amount:     e.Amount.Decimal(),
+		Currency:   e.Amount.Currency(),
+		DetectedAt: e.DetectedAt,
+	}
+}
@@ -0,0 +1,70 @@
+package usecases
+
+import (
+	"carigo/internal/application/dto"
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"context"
+	"errors"
+	"time"
+)
+
+// ratesForInvoices looks up a rate from currency into every other currency the
+// invoices are billed in. Pairs without a known rate are left out, so those
+// invoices are simply not allocated.
+func ratesForInvoices(ctx context.Context, provider ports.ExchangeRateProvider, currency string, invoices []*domain.Invoice, on time.Time) ([]*domain.ExchangeRate, error) {
+	seen := map[string]bool{currency: true}
+	var rates []*domain.ExchangeRate
+	for _, inv := range invoices {
+		target := inv.TotalAmount.Currency()
+		if seen[target] {
+			continue
+		}
+		seen[target] = true
+
+		rate, err := provider.Rate(ctx, currency, target, on)
+		if errors.Is(err, domain.ErrExchangeRateNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		rates = append(rates, rate)
+	}
+	return rates, nil
+}
+
+// allocateAcrossCurrencies books amount from source against inv, converting at
+// the rate in effect on the given day when the currencies differ.
+func allocateAcrossCurrencies(
+	ctx context.Context,
+	provider ports.ExchangeRateProvider,
+	id domain.AllocationID,
+	source domain.AllocationSource,
+	inv *domain.Invoice,
+	amount domain.Money,
+	on time.Time,
+) (*domain.Allocation, error) {
+	if amount.Currency() == inv.TotalAmount.Currency() {
+		return domain.NewAllocation(id, source, inv, amount)
+	}
+	rate, err := provider.Rate(ctx, amount.Currency(), inv.TotalAmount.Currency(), on)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewAllocationAtRate(id, source, inv, amount, rate)
+}
+
+func mapAllocatedInvoice(a *domain.Allocation) dto.AllocatedInvoiceParams {
+	item := dto.AllocatedInvoiceParams{
+		AllocationID: string(a.ID),
+		InvoiceID:    string(a.InvoiceID),
+		Amount:       a.Amount.Amount(),
+	}
+	if a.ExchangeRate != nil {
+		item.InvoiceAmount = a.InvoiceAmount.Amount()
+		item.InvoiceCurrency = a.InvoiceAmount.Currency()
+		item.ExchangeRate = a.ExchangeRate.String()
+	}
+	return item
+}
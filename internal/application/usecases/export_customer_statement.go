@@ -0,0 +1,26 @@
+package usecases
+
+import (
+	"carigo/internal/application/dto"
+	"carigo/internal/application/ports"
+	"context"
+)
+
+// ExportCustomerStatementUseCase renders a customer's statement as a
+// printable document.
+type ExportCustomerStatementUseCase struct {
+	statement *GetCustomerStatementUseCase
+	renderer  ports.DocumentRenderer
+}
+
+func NewExportCustomerStatementUseCase(statement *GetCustomerStatementUseCase, renderer ports.DocumentRenderer) *ExportCustomerStatementUseCase {
+	return &ExportCustomerStatementUseCase{statement: statement, renderer: renderer}
+}
+
+func (uc *ExportCustomerStatementUseCase) Execute(ctx context.Context, customerID string, req dto.StatementRequest) ([]byte, error) {
+	st, err := uc.statement.Execute(ctx, customerID, req)
+	if err != nil {
+		return nil, err
+	}
+	return uc.renderer.RenderStatement(st)
+}
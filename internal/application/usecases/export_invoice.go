@@ -0,0 +1,38 @@
+package usecases
+
+import (
+	"carigo/internal/application/dto"
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"context"
+)
+
+// ExportInvoiceUseCase renders an invoice, addressed to its customer, as a
+// printable document.
+type ExportInvoiceUseCase struct {
+	invRepo  ports.InvoiceRepository
+	custRepo ports.CustomerRepository
+	renderer ports.DocumentRenderer
+	clock    ports.Clock
+}
+
+func NewExportInvoiceUseCase(i ports.InvoiceRepository, c ports.CustomerRepository, renderer ports.DocumentRenderer, clk ports.Clock) *ExportInvoiceUseCase {
+	return &ExportInvoiceUseCase{invRepo: i, custRepo: c, renderer: renderer, clock: clk}
+}
+
+func (uc *ExportInvoiceUseCase) Execute(ctx context.Context, invoiceID string) ([]byte, error) {
+	inv, err := uc.invRepo.FindByID(ctx, domain.InvoiceID(invoiceID))
+	if err != nil {
+		return nil, err
+	}
+	customer, err := uc.custRepo.FindByID(ctx, inv.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.renderer.RenderInvoice(&dto.InvoiceDocument{
+		Invoice:   mapInvoice(inv, uc.clock.Now()),
+		Customer:  mapCustomer(customer),
+		Remaining: inv.RemainingAmount().Decimal(),
+	})
+}
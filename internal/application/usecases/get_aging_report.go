@@ -0,0 +1,135 @@
+package usecases
+
+import (
+	"carigo/internal/application/dto"
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"context"
+	"sort"
+)
+
+// GetAgingReportUseCase buckets open receivables by days past due, per
+// customer and currency, with totals per currency.
+type GetAgingReportUseCase struct {
+	invoiceRepo  ports.InvoiceRepository
+	customerRepo ports.CustomerRepository
+	clock        ports.Clock
+}
+
+func NewGetAgingReportUseCase(ir ports.InvoiceRepository, cr ports.CustomerRepository, clk ports.Clock) *GetAgingReportUseCase {
+	return &GetAgingReportUseCase{
+		invoiceRepo:  ir,
+		customerRepo: cr,
+		clock:        clk,
+	}
+}
+
+func (uc *GetAgingReportUseCase) Execute(ctx context.Context, req dto.AgingReportRequest) (*dto.AgingReport, error) {
+	buckets, err := domain.ParseAgingBuckets(req.Buckets)
+	if err != nil {
+		return nil, err
+	}
+	asOf := req.AsOf
+	if asOf.IsZero() {
+		asOf = uc.clock.Now()
+	} else {
+		// A bare day includes everything issued on it.
+		asOf = domain.RateDay(asOf).AddDate(0, 0, 1).Add(-1)
+	}
+
+	balances, err := uc.invoiceRepo.SumOpenByAge(ctx, asOf, buckets)
+	if err != nil {
+		return nil, err
+	}
+	customers, err := uc.customerRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[domain.CustomerID]string, len(customers))
+	for _, c := range customers {
+		names[c.ID] = c.Name
+	}
+
+	type key struct {
+		customerID domain.CustomerID
+		currency   string
+	}
+	var keys []key
+	rows := map[key][]domain.Money{}
+	totals := map[string][]domain.Money{}
+	add := func(row []domain.Money, bucket int, amount domain.Money) ([]domain.Money, error) {
+		if row == nil {
+			row = make([]domain.Money, buckets.Len())
+			for i := range row {
+				row[i], _ = domain.NewMoney(0, amount.Currency())
+			}
+		}
+		var err error
+		row[bucket], err = row[bucket].Add(amount)
+		return row, err
+	}
+	for _, b := range balances {
+		k := key{b.CustomerID, b.Amount.Currency()}
+		if _, ok := rows[k]; !ok {
+			keys = append(keys, k)
+		}
+		if rows[k], err = add(rows[k], b.Bucket, b.Amount); err != nil {
+			return nil, err
+		}
+		if totals[k.currency], err = add(totals[k.currency], b.Bucket, b.Amount); err != nil {
+			return nil, err
+		}
+	}
+
+	report := &dto.AgingReport{
+		AsOf:      domain.RateDay(asOf),
+		Buckets:   buckets.Labels(),
+		Customers: make([]dto.CustomerAging, 0, len(keys)),
+		Totals:    make([]dto.CurrencyAging, 0, len(totals)),
+	}
+	for _, k := range keys {
+		amounts, total, err := agingAmounts(rows[k])
+		if err != nil {
+			return nil, err
+		}
+		report.Customers = append(report.Customers, dto.CustomerAging{
+			CustomerID:   string(k.customerID),
+			CustomerName: names[k.customerID],
+			Currency:     k.currency,
+			Amounts:      amounts,
+			Total:        total,
+		})
+	}
+	for currency, row := range totals {
+		amounts, total, err := agingAmounts(row)
+		if err != nil {
+			return nil, err
+		}
+		report.Totals = append(report.Totals, dto.CurrencyAging{
+			Currency: currency,
+			Amounts:  amounts,
+			Total:    total,
+		})
+	}
+	sort.Slice(report.Totals, func(i, j int) bool {
+		return report.Totals[i].Currency < report.Totals[j].Currency
+	})
+	return report, nil
+}
+
+// agingAmounts renders a row of bucket amounts and their sum as decimals.
+func agingAmounts(row []domain.Money) ([]string, string, error) {
+	amounts := make([]string, len(row))
+	sum := row[0]
+	for i, m := range row {
+		amounts[i] = m.Decimal()
+		if i == 0 {
+			continue
+		}
+		var err error
+		if sum, err = sum.Add(m); err != nil {
+			return nil, "", err
+		}
+	}
+	return amounts, sum.Decimal(), nil
+}
@@ -0,0 +1,53 @@
+package usecases
+
+import (
+	"carigo/internal/application/dto"
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"context"
+)
+
+// GetBankReviewQueueUseCase lists the bank lines waiting for a clerk, MATCHED
+// ones first, each with the customers who may have made the transfer.
+type GetBankReviewQueueUseCase struct {
+	lineRepo     ports.BankLineRepository
+	customerRepo ports.CustomerRepository
+	invoiceRepo  ports.InvoiceRepository
+	ruleRepo     ports.BankMatchRuleRepository
+}
+
+func NewGetBankReviewQueueUseCase(lr ports.BankLineRepository, cr ports.CustomerRepository, ir ports.InvoiceRepository, rr ports.BankMatchRuleRepository) *GetBankReviewQueueUseCase {
+	return &GetBankReviewQueueUseCase{
+		lineRepo:     lr,
+		customerRepo: cr,
+		invoiceRepo:  ir,
+		ruleRepo:     rr,
+	}
+}
+
+func (uc *GetBankReviewQueueUseCase) Execute(ctx context.Context) (*dto.BankReviewQueue, error) {
+	matcher, err := loadBankMatcher(ctx, uc.customerRepo, uc.invoiceRepo, uc.ruleRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	queue := &dto.BankReviewQueue{
+		Lines: []dto.BankLineDTO{},
+		Rules: make([]dto.BankMatchRuleDTO, 0, len(matcher.rules)),
+	}
+	for _, state := range []domain.BankLineState{domain.BankLineStateMatched, domain.BankLineStateNew} {
+		lines, err := uc.lineRepo.FindByState(ctx, state)
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range lines {
+			item := mapBankLine(line)
+			item.Candidates = matcher.mapCandidates(matcher.match(line))
+			queue.Lines = append(queue.Lines, item)
+		}
+	}
+	for _, r := range matcher.rules {
+		queue.Rules = append(queue.Rules, mapBankMatchRule(r))
+	}
+	return queue, nil
+}
@@ -5,26 +5,46 @@ import (
 	"carigo/internal/application/ports"
 	"carigo/internal/domain"
 	"context"
+	"fmt"
+	"slices"
 	"sort"
+	"time"
 )
 
 type GetCustomerStatementUseCase struct {
-	custRepo ports.CustomerRepository
-	invRepo  ports.InvoiceRepository
-	payRepo  ports.PaymentRepository
+	custRepo  ports.CustomerRepository
+	invRepo   ports.InvoiceRepository
+	payRepo   ports.PaymentRepository
+	cnRepo    ports.CreditNoteRepository
+	allocRepo ports.AllocationRepository
+	stmtRepo  ports.StatementRepository
+	policy    domain.CreditPolicy
+	clock     ports.Clock
 }
 
-func NewGetCustomerStatementUseCase(c ports.CustomerRepository, i ports.InvoiceRepository, p ports.PaymentRepository) *GetCustomerStatementUseCase {
+func NewGetCustomerStatementUseCase(c ports.CustomerRepository, i ports.InvoiceRepository, p ports.PaymentRepository, cn ports.CreditNoteRepository, a ports.AllocationRepository, st ports.StatementRepository, policy domain.CreditPolicy, clk ports.Clock) *GetCustomerStatementUseCase {
 	return &GetCustomerStatementUseCase{
-		custRepo: c,
-		invRepo:  i,
-		payRepo:  p,
+		custRepo:  c,
+		invRepo:   i,
+		payRepo:   p,
+		cnRepo:    cn,
+		allocRepo: a,
+		stmtRepo:  st,
+		policy:    policy,
+		clock:     clk,
 	}
 }
 
-func (uc *GetCustomerStatementUseCase) Execute(ctx context.Context, customerID string) (*dto.CustomerStatementDTO, error) {
+// Execute lists the movements of the customer's account from req.From
+// through req.To. Everything before From is carried into each currency's
+// opening balance (devir), which the running balance starts from. Money on
+// account and the credit risk are always as of now.
+func (uc *GetCustomerStatementUseCase) Execute(ctx context.Context, customerID string, req dto.StatementRequest) (*dto.CustomerStatementDTO, error) {
 	cid := domain.CustomerID(customerID)
-	
+	if !req.From.IsZero() && !req.To.IsZero() && req.To.Before(req.From) {
+		return nil, domain.ErrInvalidStatementPeriod
+	}
+
 	customer, err := uc.custRepo.FindByID(ctx, cid)
 	if err != nil {
 		return nil, err
@@ -34,57 +54,294 @@ func (uc *GetCustomerStatementUseCase) Execute(ctx context.Context, customerID s
 		return nil, err
 	}
 
-	payments, err := uc.payRepo.FindByCustomer(ctx, cid)
+	now := uc.clock.Now()
+	entries, credits, err := uc.movements(ctx, cid, invoices, now)
 	if err != nil {
 		return nil, err
 	}
 
-	var transactions []dto.StatementItem
+	var opening []domain.Balance
+	if !req.From.IsZero() {
+		from := domain.RateDay(req.From)
+		if opening, err = uc.stmtRepo.BalancesBefore(ctx, cid, from); err != nil {
+			return nil, err
+		}
+		entries = slices.DeleteFunc(entries, func(e statementEntry) bool {
+			return e.date.Before(from)
+		})
+	}
+	if !req.To.IsZero() {
+		end := domain.RateDay(req.To).AddDate(0, 0, 1)
+		entries = slices.DeleteFunc(entries, func(e statementEntry) bool {
+			return !e.date.Before(end)
+		})
+	}
 
-	for _, inv := range invoices {
-		transactions = append(transactions, dto.StatementItem{
-			Date:        inv.IssueDate,
-			Type:        "FATURA",
-			ReferenceID: string(inv.ID),
-			Description: "Satış Faturası",
-			Debt:        float64(inv.TotalAmount.Amount()) / 100.0,
-			Credit:      0,
-			Currency:    inv.TotalAmount.Currency(),
+	currencies, err := buildCurrencyStatements(opening, entries)
+	if err != nil {
+		return nil, err
+	}
+
+	onAccount := make([]dto.CurrencyAmount, 0, len(credits))
+	for currency, amount := range credits {
+		credit, err := domain.NewMoney(amount, currency)
+		if err != nil {
+			return nil, err
+		}
+		onAccount = append(onAccount, dto.CurrencyAmount{
+			Currency: currency,
+			Amount:   credit.Decimal(),
 		})
 	}
+	sort.Slice(onAccount, func(i, j int) bool {
+		return onAccount[i].Currency < onAccount[j].Currency
+	})
+
+	risk, err := domain.NewCreditRisk(customer, invoices, uc.policy, now)
+	if err != nil {
+		return nil, err
+	}
+	customerDTO := mapCustomer(customer)
+	customerDTO.Risk = mapCreditRisk(risk)
+
+	res := &dto.CustomerStatementDTO{
+		Customer:         customerDTO,
+		Currencies:       currencies,
+		OnAccountCredits: onAccount,
+	}
+	if !req.From.IsZero() {
+		from := domain.RateDay(req.From)
+		res.From = &from
+	}
+	if !req.To.IsZero() {
+		to := domain.RateDay(req.To)
+		res.To = &to
+	}
+	return res, nil
+}
+
+// movements lists every movement of the customer's account, with the
+// overdue status of invoices as of now, and the money they have on account
+// per currency.
+func (uc *GetCustomerStatementUseCase) movements(ctx context.Context, cid domain.CustomerID, invoices []*domain.Invoice, now time.Time) ([]statementEntry, map[string]int64, error) {
+	payments, err := uc.payRepo.FindByCustomer(ctx, cid)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	creditNotes, err := uc.cnRepo.FindByCustomer(ctx, cid)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var entries []statementEntry
+	credits := make(map[string]int64)
+
+	for _, inv := range invoices {
+		entries = append(entries, invoiceEntries(inv, now)...)
+		if inv.Status == domain.InvoiceStatusVoid && inv.VoidedAt != nil {
+			entries = append(entries, creditEntry(*inv.VoidedAt, "FATURA İPTALİ", string(inv.ID), "İptal: "+inv.VoidReason, inv.TotalAmount))
+		}
+	}
 
 	for _, pay := range payments {
-		transactions = append(transactions, dto.StatementItem{
-			Date:        pay.Date,
-			Type:        "TAHSİLAT",
-			ReferenceID: string(pay.ID),
-			Description: "Ödeme Alındı",
-			Debt:        0,
-			Credit:      float64(pay.Amount.Amount()) / 100.0,
-			Currency:    pay.Amount.Currency(),
-		})
+		entries = append(entries, creditEntry(pay.Date, "TAHSİLAT", string(pay.ID), "Ödeme Alındı", pay.Amount))
+		if pay.IsReversed() {
+			entries = append(entries, debitEntry(*pay.ReversedAt, "TAHSİLAT İPTALİ", string(pay.ID), "İptal: "+pay.ReversalReason, pay.Amount))
+		}
+		if !pay.AvailableAmount.IsZero() {
+			credits[pay.AvailableAmount.Currency()] += pay.AvailableAmount.Amount()
+		}
+
+		allocations, err := uc.allocRepo.FindByPayment(ctx, pay.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		entries = append(entries, allocationEntries(allocations, pay.Date)...)
 	}
 
-	sort.Slice(transactions, func(i, j int) bool {
-		return transactions[i].Date.Before(transactions[j].Date)
-	})
+	for _, note := range creditNotes {
+		description := note.Reason
+		if note.InvoiceID != "" {
+			description += " (" + string(note.InvoiceID) + ")"
+		}
+		entries = append(entries, creditEntry(note.Date, "İADE/ALACAK DEKONTU", string(note.ID), description, note.Amount))
+		allocations, err := uc.allocRepo.FindByCreditNote(ctx, note.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		entries = append(entries, allocationEntries(allocations, note.Date)...)
+		if !note.AvailableAmount.IsZero() {
+			credits[note.AvailableAmount.Currency()] += note.AvailableAmount.Amount()
+		}
+	}
+	return entries, credits, nil
+}
+
+// allocationEntries books what the allocations of a payment or credit note
+// move between the customer's currencies. An early-payment discount is a
+// credit on date, the source's date. A cross-currency settlement takes its
+// amount out of the source currency and settles the invoice in its own, on
+// the day it was made: a debit in the one and a credit in the other. The
+// reversal of either books the opposite.
+func allocationEntries(allocations []*domain.Allocation, date time.Time) []statementEntry {
+	var entries []statementEntry
+	for _, a := range allocations {
+		switch {
+		case a.IsDiscount():
+			entries = append(entries, creditEntry(date, "İSKONTO", string(a.ID), "Erken Ödeme İskontosu ("+string(a.InvoiceID)+")", a.InvoiceAmount))
+			for _, r := range allocations {
+				if r.ReversalOf == a.ID {
+					entries = append(entries, debitEntry(r.CreatedAt, "İSKONTO İPTALİ", string(a.ID), "İptal: "+r.Reason, a.InvoiceAmount))
+				}
+			}
+		case a.ExchangeRate == nil:
+		case a.Type == domain.AllocationTypeApplication:
+			description := fmt.Sprintf("Kur Mahsubu (%s, kur %s)", a.InvoiceID, a.ExchangeRate)
+			entries = append(entries,
+				debitEntry(a.CreatedAt, "KUR MAHSUBU", string(a.ID), description, a.Amount),
+				creditEntry(a.CreatedAt, "KUR MAHSUBU", string(a.ID), description, a.InvoiceAmount))
+		case a.Type == domain.AllocationTypeReversal:
+			description := "İptal: " + a.Reason
+			entries = append(entries,
+				creditEntry(a.CreatedAt, "KUR MAHSUBU İPTALİ", string(a.ReversalOf), description, a.Amount),
+				debitEntry(a.CreatedAt, "KUR MAHSUBU İPTALİ", string(a.ReversalOf), description, a.InvoiceAmount))
+		}
+	}
+	return entries
+}
+
+// statementEntry is a single account movement. Exactly one of debt and credit
+// is non-zero; both are in the entry's currency.
+type statementEntry struct {
+	date        time.Time
+	kind        string
+	referenceID string
+	description string
+	debt        domain.Money
+	credit      domain.Money
+	// dueDate is set for invoice obligations only.
+	dueDate *time.Time
+	overdue bool
+}
+
+func debitEntry(date time.Time, kind, ref, description string, amount domain.Money) statementEntry {
+	zero, _ := domain.NewMoney(0, amount.Currency())
+	return statementEntry{date: date, kind: kind, referenceID: ref, description: description, debt: amount, credit: zero}
+}
+
+// invoiceEntries books an invoice as one obligation per instalment, each with
+// its own due date and overdue status as of asOf.
+func invoiceEntries(inv *domain.Invoice, asOf time.Time) []statementEntry {
+	statuses := inv.InstalmentStatuses()
+	entries := make([]statementEntry, 0, len(statuses))
+	for _, st := range statuses {
+		description := invoiceDescription(inv)
+		if inv.HasInstalments() {
+			description += fmt.Sprintf(" - Taksit %d/%d", st.Number, len(statuses))
+		}
+		e := debitEntry(inv.IssueDate, "FATURA", string(inv.ID), description, st.Amount)
+		dueDate := st.DueDate
+		e.dueDate = &dueDate
+		e.overdue = inv.Status != domain.InvoiceStatusVoid && st.IsOverdue(asOf)
+		entries = append(entries, e)
+	}
+	return entries
+}
 
-	balance := 0.0
-	for i := range transactions {
-		balance += transactions[i].Debt
-		balance -= transactions[i].Credit
-		transactions[i].Balance = balance
-	}
-
-	return &dto.CustomerStatementDTO{
-		Customer: dto.CustomerDTO{
-			ID:    string(customer.ID),
-			Name:  customer.Name,
-			Email: customer.Email,
-			TaxID: customer.TaxID,
-		},
-		Transactions: transactions,
-		FinalBalance: balance,
-		Currency:     "TRY",
-	}, nil
+func creditEntry(date time.Time, kind, ref, description string, amount domain.Money) statementEntry {
+	zero, _ := domain.NewMoney(0, amount.Currency())
+	return statementEntry{date: date, kind: kind, referenceID: ref, description: description, debt: zero, credit: amount}
+}
+
+// buildCurrencyStatements groups entries by currency and runs a separate
+// balance for each, in date order, starting from the currency's opening
+// balance. A currency with an opening balance but no entries still gets its
+// section. Sections are ordered by currency code.
+func buildCurrencyStatements(opening []domain.Balance, entries []statementEntry) ([]dto.CurrencyStatement, error) {
+	byCurrency := make(map[string][]statementEntry)
+	openingBalances := make(map[string]domain.Balance)
+	for _, b := range opening {
+		openingBalances[b.Currency()] = b
+		byCurrency[b.Currency()] = nil
+	}
+	for _, e := range entries {
+		byCurrency[e.debt.Currency()] = append(byCurrency[e.debt.Currency()], e)
+	}
+
+	codes := make([]string, 0, len(byCurrency))
+	for code := range byCurrency {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	sections := make([]dto.CurrencyStatement, 0, len(codes))
+	var err error
+	for _, code := range codes {
+		group := byCurrency[code]
+		sort.SliceStable(group, func(i, j int) bool {
+			return group[i].date.Before(group[j].date)
+		})
+
+		balance, ok := openingBalances[code]
+		if !ok {
+			if balance, err = domain.NewBalance(code); err != nil {
+				return nil, err
+			}
+		}
+		openingBalance := balance
+		totalDebt, err := domain.NewMoney(0, code)
+		if err != nil {
+			return nil, err
+		}
+		totalCredit := totalDebt
+
+		items := make([]dto.StatementItem, 0, len(group))
+		for _, e := range group {
+			if balance, err = balance.Debit(e.debt); err != nil {
+				return nil, err
+			}
+			if balance, err = balance.Credit(e.credit); err != nil {
+				return nil, err
+			}
+			if totalDebt, err = totalDebt.Add(e.debt); err != nil {
+				return nil, err
+			}
+			if totalCredit, err = totalCredit.Add(e.credit); err != nil {
+				return nil, err
+			}
+			items = append(items, dto.StatementItem{
+				Date:        e.date,
+				Type:        e.kind,
+				ReferenceID: e.referenceID,
+				Description: e.description,
+				Debt:        e.debt.Decimal(),
+				Credit:      e.credit.Decimal(),
+				Balance:     balance.Decimal(),
+				Currency:    code,
+				DueDate:     e.dueDate,
+				Overdue:     e.overdue,
+			})
+		}
+
+		sections = append(sections, dto.CurrencyStatement{
+			Currency:       code,
+			OpeningBalance: openingBalance.Decimal(),
+			Transactions:   items,
+			TotalDebt:      totalDebt.Decimal(),
+			TotalCredit:    totalCredit.Decimal(),
+			FinalBalance:   balance.Decimal(),
+		})
+	}
+	return sections, nil
+}
+
+func invoiceDescription(inv *domain.Invoice) string {
+	switch inv.Kind {
+	case domain.InvoiceKindExchangeDifference:
+		return "Kur Farkı Faturası"
+	case domain.InvoiceKindLateInterest:
+		return "Vade Farkı Faturası"
+	}
+	return "Satış Faturası"
 }
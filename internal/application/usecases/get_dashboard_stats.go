@@ -2,25 +2,40 @@ package usecases
 
 import (
 	"carigo/internal/application/ports"
+	"carigo/internal/domain"
 	"context"
+	"sort"
+	"time"
 )
 
 type DashboardStats struct {
 	TotalCollected int64
 	OpenInvoices   int64
-	TotalRevenue   int64 
-	TotalCustomers int64 
-	PendingBalance int64 
+	TotalRevenue   int64
+	TotalCustomers int64
+	PendingBalance int64
+	// CustomerCredits lists unallocated payment balances per customer and currency.
+	CustomerCredits []ports.CustomerCredit
+	OverdueInvoices int64
+	// OverdueAmounts is the past-due part of open invoices, per currency.
+	OverdueAmounts []domain.Money
+	// RecentOverdue lists the latest "became overdue" events.
+	RecentOverdue []*domain.OverdueEvent
 }
 
+// recentOverdueLimit caps RecentOverdue on the dashboard.
+const recentOverdueLimit = 10
+
 type GetDashboardStatsUseCase struct {
-	payRepo  ports.PaymentRepository
-	invRepo  ports.InvoiceRepository
-	custRepo ports.CustomerRepository
+	payRepo   ports.PaymentRepository
+	invRepo   ports.InvoiceRepository
+	custRepo  ports.CustomerRepository
+	eventRepo ports.OverdueEventRepository
+	clock     ports.Clock
 }
 
-func NewGetDashboardStatsUseCase(pr ports.PaymentRepository, ir ports.InvoiceRepository, cr ports.CustomerRepository) *GetDashboardStatsUseCase {
-	return &GetDashboardStatsUseCase{payRepo: pr, invRepo: ir, custRepo: cr}
+func NewGetDashboardStatsUseCase(pr ports.PaymentRepository, ir ports.InvoiceRepository, cr ports.CustomerRepository, er ports.OverdueEventRepository, clk ports.Clock) *GetDashboardStatsUseCase {
+	return &GetDashboardStatsUseCase{payRepo: pr, invRepo: ir, custRepo: cr, eventRepo: er, clock: clk}
 }
 
 func (uc *GetDashboardStatsUseCase) Execute(ctx context.Context) (*DashboardStats, error) {
@@ -43,16 +58,74 @@ func (uc *GetDashboardStatsUseCase) Execute(ctx context.Context) (*DashboardStat
 		return nil, err
 	}
 
+	customerCredits, err := uc.payRepo.SumAvailableByCustomer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := uc.clock.Now()
+	overdueInvoices, err := uc.invRepo.CountOverdue(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	overdueAmounts, err := uc.sumOverdue(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	recentOverdue, err := uc.eventRepo.FindRecent(ctx, recentOverdueLimit)
+	if err != nil {
+		return nil, err
+	}
+
 	pendingBalance := totalRevenue - totalCollected
 	if pendingBalance < 0 {
-		pendingBalance = 0 
+		pendingBalance = 0
 	}
 
 	return &DashboardStats{
-		TotalCollected: totalCollected,
-		OpenInvoices:   openInvoices,
-		TotalRevenue:   totalRevenue,
-		TotalCustomers: totalCustomers,
-		PendingBalance: pendingBalance,
+		TotalCollected:  totalCollected,
+		OpenInvoices:    openInvoices,
+		TotalRevenue:    totalRevenue,
+		TotalCustomers:  totalCustomers,
+		PendingBalance:  pendingBalance,
+		CustomerCredits: customerCredits,
+		OverdueInvoices: overdueInvoices,
+		OverdueAmounts:  overdueAmounts,
+		RecentOverdue:   recentOverdue,
 	}, nil
 }
+
+// sumOverdue adds up every aging bucket except "current", per currency.
+func (uc *GetDashboardStatsUseCase) sumOverdue(ctx context.Context, now time.Time) ([]domain.Money, error) {
+	balances, err := uc.invRepo.SumOpenByAge(ctx, now, domain.DefaultAgingBuckets)
+	if err != nil {
+		return nil, err
+	}
+
+	var currencies []string
+	sums := map[string]domain.Money{}
+	for _, b := range balances {
+		if b.Bucket == 0 {
+			continue
+		}
+		currency := b.Amount.Currency()
+		sum, ok := sums[currency]
+		if !ok {
+			currencies = append(currencies, currency)
+			sums[currency] = b.Amount
+			continue
+		}
+		if sums[currency], err = sum.Add(b.Amount); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Strings(currencies)
+	amounts := make([]domain.Money, 0, len(currencies))
+	for _, c := range currencies {
+		amounts = append(amounts, sums[c])
+	}
+	return amounts, nil
+}
@@ -0,0 +1,43 @@
+package usecases
+
+import (
+	"carigo/internal/application/dto"
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"context"
+)
+
+// GetReconciliationDocumentUseCase builds the confirmation letter of a
+// reconciliation, to be printed or emailed to the customer.
+type GetReconciliationDocumentUseCase struct {
+	repo     ports.ReconciliationRepository
+	custRepo ports.CustomerRepository
+}
+
+func NewGetReconciliationDocumentUseCase(r ports.ReconciliationRepository, c ports.CustomerRepository) *GetReconciliationDocumentUseCase {
+	return &GetReconciliationDocumentUseCase{repo: r, custRepo: c}
+}
+
+func (uc *GetReconciliationDocumentUseCase) Execute(ctx context.Context, id string) (*dto.ReconciliationDocument, error) {
+	r, err := uc.repo.FindByID(ctx, domain.ReconciliationID(id))
+	if err != nil {
+		return nil, err
+	}
+	customer, err := uc.custRepo.FindByID(ctx, r.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+
+	subject, body := reconciliationLetter(r, customer)
+	balances := make([]dto.LetterBalance, len(r.Balances))
+	for i, b := range r.Balances {
+		balances[i] = dto.LetterBalance{Currency: b.Currency(), Amount: b.Abs().Decimal(), Side: balanceSide(b)}
+	}
+	return &dto.ReconciliationDocument{
+		Reconciliation: mapReconciliation(r, customer.Name),
+		Customer:       mapCustomer(customer),
+		Balances:       balances,
+		Subject:        subject,
+		Body:           body,
+	}, nil
+}
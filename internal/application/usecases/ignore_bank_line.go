@@ -0,0 +1,35 @@
+package usecases
+
+import (
+	"carigo/internal/application/dto"
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"context"
+)
+
+// IgnoreBankLineUseCase keeps an open bank line from becoming a payment, for
+// transfers such as those between the company's own accounts.
+type IgnoreBankLineUseCase struct {
+	repo  ports.BankLineRepository
+	clock ports.Clock
+}
+
+func NewIgnoreBankLineUseCase(r ports.BankLineRepository, clk ports.Clock) *IgnoreBankLineUseCase {
+	return &IgnoreBankLineUseCase{repo: r, clock: clk}
+}
+
+func (uc *IgnoreBankLineUseCase) Execute(ctx context.Context, id string) (*dto.BankLineDTO, error) {
+	line, err := uc.repo.FindByID(ctx, domain.BankLineID(id))
+	if err != nil {
+		return nil, err
+	}
+	if err := line.Ignore(uc.clock.Now()); err != nil {
+		return nil, err
+	}
+	if err := uc.repo.Save(ctx, line); err != nil {
+		return nil, err
+	}
+
+	res := mapBankLine(line)
+	return &res, nil
+}
@@ -0,0 +1,97 @@
+package usecases
+
+import (
+	"carigo/internal/application/dto"
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ImportBankStatementUseCase stages the incoming transfers of an uploaded
+// bank statement as NEW bank lines. Lines are keyed by the receiving account
+// and the bank's reference, so uploading a statement again, or one that
+// overlaps it, only adds the lines not seen before and leaves the others,
+// and whatever was done with them, alone.
+type ImportBankStatementUseCase struct {
+	parser    ports.BankStatementParser
+	lineRepo  ports.BankLineRepository
+	txManager ports.TransactionManager
+	clock     ports.Clock
+}
+
+func NewImportBankStatementUseCase(p ports.BankStatementParser, lr ports.BankLineRepository, tm ports.TransactionManager, clk ports.Clock) *ImportBankStatementUseCase {
+	return &ImportBankStatementUseCase{
+		parser:    p,
+		lineRepo:  lr,
+		txManager: tm,
+		clock:     clk,
+	}
+}
+
+func (uc *ImportBankStatementUseCase) Execute(ctx context.Context, req dto.ImportBankStatementRequest) (*dto.ImportBankStatementResponse, error) {
+	format, entries, err := uc.parser.Parse(ctx, domain.BankStatementFormat(strings.ToUpper(req.Format)), req.Profile, req.File)
+	if err != nil {
+		return nil, err
+	}
+
+	now := uc.clock.Now()
+	lines := make([]*domain.BankLine, 0, len(entries))
+	alike := map[string]int{}
+	firstLine := map[domain.BankLineID]int{}
+	for i, e := range entries {
+		account := e.Account
+		if account == "" {
+			account = req.Account
+		}
+		if account == "" {
+			return nil, fmt.Errorf("%w: line %d: the receiving account is not in the statement", domain.ErrInvalidBankStatement, i+1)
+		}
+		// Transfers the bank gives no reference for are told apart by their
+		// position among the alike ones of this statement.
+		key := strings.Join([]string{account, domain.RateDay(e.BookingDate).Format("2006-01-02"), e.Amount.Decimal(), e.Amount.Currency(),
+			e.PayerIBAN, strings.Join(strings.Fields(e.PayerName), " "), strings.Join(strings.Fields(e.Description), " ")}, "|")
+		alike[key]++
+		payer := domain.BankPayer{Name: e.PayerName, IBAN: e.PayerIBAN, TaxID: e.PayerTaxID}
+		line, err := domain.NewBankLine(account, e.Reference, e.BookingDate, e.ValueDate, e.Amount, payer, e.Description, alike[key], format, now)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		// A statement never lists a transfer twice, so lines of one upload
+		// that come out the same are an error rather than lines seen before.
+		if first, ok := firstLine[line.ID]; ok {
+			return nil, fmt.Errorf("%w: line %d has the same reference %s as line %d", domain.ErrInvalidBankStatement, i+1, line.Reference, first)
+		}
+		firstLine[line.ID] = i + 1
+		lines = append(lines, line)
+	}
+
+	res := &dto.ImportBankStatementResponse{
+		Format: string(format),
+		Lines:  make([]dto.BankLineDTO, 0, len(lines)),
+	}
+	err = uc.txManager.Do(ctx, func(ctx context.Context) error {
+		for _, line := range lines {
+			_, err := uc.lineRepo.FindByID(ctx, line.ID)
+			if err == nil {
+				res.Skipped++
+				continue
+			}
+			if !errors.Is(err, domain.ErrBankLineNotFound) {
+				return err
+			}
+			if err := uc.lineRepo.Save(ctx, line); err != nil {
+				return err
+			}
+			res.Imported++
+			res.Lines = append(res.Lines, mapBankLine(line))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,51 @@
+package usecases
+
+import (
+	"carigo/internal/application/dto"
+	"carigo/internal/application/ports"
+	"context"
+)
+
+// ImportExchangeRatesUseCase stores every rate of a published bulletin. The
+// import is all or nothing, and re-importing the same day overwrites it.
+type ImportExchangeRatesUseCase struct {
+	importer  ports.ExchangeRateImporter
+	rateRepo  ports.ExchangeRateRepository
+	txManager ports.TransactionManager
+}
+
+func NewImportExchangeRatesUseCase(im ports.ExchangeRateImporter, rr ports.ExchangeRateRepository, tm ports.TransactionManager) *ImportExchangeRatesUseCase {
+	return &ImportExchangeRatesUseCase{
+		importer:  im,
+		rateRepo:  rr,
+		txManager: tm,
+	}
+}
+
+func (uc *ImportExchangeRatesUseCase) Execute(ctx context.Context, req dto.ImportExchangeRatesRequest) (*dto.ImportExchangeRatesResponse, error) {
+	rates, err := uc.importer.Import(ctx, req.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	err = uc.txManager.Do(ctx, func(ctx context.Context) error {
+		for _, r := range rates {
+			if err := uc.rateRepo.Save(ctx, r); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.ImportExchangeRatesResponse{
+		Imported: len(rates),
+		Rates:    make([]dto.ExchangeRateDTO, 0, len(rates)),
+	}
+	for _, r := range rates {
+		res.Rates = append(res.Rates, mapExchangeRate(r))
+	}
+	return res, nil
+}
@@ -0,0 +1,31 @@
+package usecases
+
+import (
+	"carigo/internal/application/dto"
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"context"
+	"strings"
+)
+
+type ListBankLinesUseCase struct {
+	repo ports.BankLineRepository
+}
+
+func NewListBankLinesUseCase(r ports.BankLineRepository) *ListBankLinesUseCase {
+	return &ListBankLinesUseCase{repo: r}
+}
+
+// Execute lists the bank lines in state, or all of them if state is empty.
+func (uc *ListBankLinesUseCase) Execute(ctx context.Context, state string) ([]dto.BankLineDTO, error) {
+	lines, err := uc.repo.FindByState(ctx, domain.BankLineState(strings.ToUpper(state)))
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]dto.BankLineDTO, len(lines))
+	for i, l := range lines {
+		dtos[i] = mapBankLine(l)
+	}
+	return dtos, nil
+}
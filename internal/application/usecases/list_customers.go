@@ -3,15 +3,20 @@ package usecases
 import (
 	"carigo/internal/application/dto"
 	"carigo/internal/application/ports"
+	"carigo/internal/domain"
 	"context"
 )
 
 type ListCustomersUseCase struct {
-	repo ports.CustomerRepository
+	repo               ports.CustomerRepository
+	invoiceRepo        ports.InvoiceRepository
+	reconciliationRepo ports.ReconciliationRepository
+	policy             domain.CreditPolicy
+	clock              ports.Clock
 }
 
-func NewListCustomersUseCase(repo ports.CustomerRepository) *ListCustomersUseCase {
-	return &ListCustomersUseCase{repo: repo}
+func NewListCustomersUseCase(repo ports.CustomerRepository, ir ports.InvoiceRepository, rr ports.ReconciliationRepository, policy domain.CreditPolicy, clk ports.Clock) *ListCustomersUseCase {
+	return &ListCustomersUseCase{repo: repo, invoiceRepo: ir, reconciliationRepo: rr, policy: policy, clock: clk}
 }
 
 func (uc *ListCustomersUseCase) Execute(ctx context.Context) ([]dto.CustomerDTO, error) {
@@ -20,15 +25,57 @@ func (uc *ListCustomersUseCase) Execute(ctx context.Context) ([]dto.CustomerDTO,
 		return nil, err
 	}
 
+	// Reconciliations come newest first, so the first one seen per customer
+	// is their latest.
+	reconciliations, err := uc.reconciliationRepo.FindByStatus(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	latest := make(map[domain.CustomerID]*domain.Reconciliation)
+	for _, r := range reconciliations {
+		if _, ok := latest[r.CustomerID]; !ok {
+			latest[r.CustomerID] = r
+		}
+	}
+
+	now := uc.clock.Now()
 	dtos := make([]dto.CustomerDTO, len(customers))
 	for i, c := range customers {
-		dtos[i] = dto.CustomerDTO{
-			ID:        string(c.ID),
-			Name:      c.Name,
-			Email:     c.Email,
-			TaxID:     c.TaxID,
-			CreatedAt: c.CreatedAt,
+		risk, err := assessCreditRisk(ctx, uc.invoiceRepo, c, uc.policy, now)
+		if err != nil {
+			return nil, err
+		}
+		dtos[i] = mapCustomer(c)
+		dtos[i].Risk = mapCreditRisk(risk)
+		if r, ok := latest[c.ID]; ok {
+			rec := mapReconciliation(r, c.Name)
+			dtos[i].Reconciliation = &rec
 		}
 	}
 	return dtos, nil
 }
+
+func mapCustomer(c *domain.Customer) dto.CustomerDTO {
+	res := dto.CustomerDTO{
+		ID:                 string(c.ID),
+		Name:               c.Name,
+		Email:              c.Email,
+		TaxID:              c.TaxID,
+		AllocationStrategy: string(c.AllocationStrategy),
+		CreatedAt:          c.CreatedAt,
+	}
+	if !c.LateInterest.IsZero() {
+		res.LateInterestRate = domain.FormatRate(c.LateInterest.Percent)
+		res.LateInterestPeriod = string(c.LateInterest.Period)
+	}
+	if !c.PaymentTerms.IsZero() {
+		res.PaymentTerms = c.PaymentTerms.String()
+	}
+	if len(c.CreditLimits) > 0 {
+		res.CreditLimits = make(map[string]string, len(c.CreditLimits))
+		for currency, limit := range c.CreditLimits {
+			res.CreditLimits[currency] = limit.Decimal()
+		}
+	}
+	return res
+}
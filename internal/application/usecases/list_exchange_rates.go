@@ -0,0 +1,28 @@
+package usecases
+
+import (
+	"carigo/internal/application/dto"
+	"carigo/internal/application/ports"
+	"context"
+)
+
+type ListExchangeRatesUseCase struct {
+	rateRepo ports.ExchangeRateRepository
+}
+
+func NewListExchangeRatesUseCase(rr ports.ExchangeRateRepository) *ListExchangeRatesUseCase {
+	return &ListExchangeRatesUseCase{rateRepo: rr}
+}
+
+func (uc *ListExchangeRatesUseCase) Execute(ctx context.Context) ([]dto.ExchangeRateDTO, error) {
+	rates, err := uc.rateRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]dto.ExchangeRateDTO, 0, len(rates))
+	for _, r := range rates {
+		dtos = append(dtos, mapExchangeRate(r))
+	}
+	return dtos, nil
+}
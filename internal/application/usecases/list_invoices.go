@@ -9,35 +9,86 @@ import (
 )
 
 type ListInvoicesUseCase struct {
-	repo ports.InvoiceRepository
+	repo  ports.InvoiceRepository
+	clock ports.Clock
 }
 
-func NewListInvoicesUseCase(r ports.InvoiceRepository) *ListInvoicesUseCase {
-	return &ListInvoicesUseCase{repo: r}
+func NewListInvoicesUseCase(r ports.InvoiceRepository, clk ports.Clock) *ListInvoicesUseCase {
+	return &ListInvoicesUseCase{repo: r, clock: clk}
 }
 
-func (uc *ListInvoicesUseCase) Execute(ctx context.Context) ([]dto.InvoiceDTO, error) {
-	invoices, err := uc.repo.FindAll(ctx)
+func (uc *ListInvoicesUseCase) Execute(ctx context.Context, filter dto.InvoiceFilter) ([]dto.InvoiceDTO, error) {
+	now := uc.clock.Now()
+	var invoices []*domain.Invoice
+	var err error
+	if filter.Overdue {
+		invoices, err = uc.repo.FindOverdue(ctx, now)
+	} else {
+		invoices, err = uc.repo.FindAll(ctx)
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	dtos := make([]dto.InvoiceDTO, len(invoices))
 	for i, inv := range invoices {
-		dtos[i] = dto.InvoiceDTO{
-			ID:          string(inv.ID),
-			CustomerID:  string(inv.CustomerID),
-			TotalAmount: float64(inv.TotalAmount.Amount()) / 100.0,
-			PaidAmount:  float64(inv.PaidAmount.Amount()) / 100.0,
-			Currency:    inv.TotalAmount.Currency(),
-			Status:      string(inv.Status),
-			IssueDate:   inv.IssueDate.Format("2006-01-02"),
-			DueDate:     inv.DueDate.Format("2006-01-02"),
-		}
-		
-		if inv.Status == domain.InvoiceStatusOpen && inv.DueDate.Before(time.Now()) {
-			
-		}
+		dtos[i] = mapInvoice(inv, now)
 	}
 	return dtos, nil
 }
+
+// mapInvoice describes the invoice with its overdue status as of asOf.
+func mapInvoice(inv *domain.Invoice, asOf time.Time) dto.InvoiceDTO {
+	totals := inv.Totals()
+	d := dto.InvoiceDTO{
+		ID:              string(inv.ID),
+		CustomerID:      string(inv.CustomerID),
+		Kind:            string(inv.Kind),
+		TotalAmount:     inv.TotalAmount.Decimal(),
+		PaidAmount:      inv.PaidAmount.Decimal(),
+		Subtotal:        totals.Subtotal.Decimal(),
+		VATAmount:       totals.VAT.Decimal(),
+		Withholding:     totals.Withholding.Decimal(),
+		Currency:        inv.TotalAmount.Currency(),
+		Status:          string(inv.Status),
+		EffectiveStatus: string(inv.EffectiveStatus(asOf)),
+		DaysOverdue:     inv.DaysOverdue(asOf),
+		IssueDate:       inv.IssueDate.Format("2006-01-02"),
+		DueDate:         inv.DueDate.Format("2006-01-02"),
+		VoidReason:      inv.VoidReason,
+	}
+	for _, l := range inv.Lines {
+		d.Lines = append(d.Lines, mapInvoiceLine(l))
+	}
+	if inv.HasInstalments() {
+		for _, st := range inv.InstalmentStatuses() {
+			d.Instalments = append(d.Instalments, mapInstalment(inv, st, asOf))
+		}
+	}
+	return d
+}
+
+func mapInvoiceLine(l domain.InvoiceLine) dto.InvoiceLineDTO {
+	return dto.InvoiceLineDTO{
+		Description: l.Description,
+		Quantity:    domain.FormatQuantity(l.Quantity),
+		UnitPrice:   l.UnitPrice.Decimal(),
+		Discount:    l.Discount.Decimal(),
+		NetAmount:   l.NetAmount.Decimal(),
+		VATRate:     int(l.VATRate),
+		VATAmount:   l.VATAmount.Decimal(),
+		Withholding: l.Withholding.String(),
+		WithheldVAT: l.WithheldVAT.Decimal(),
+	}
+}
+
+func mapInstalment(inv *domain.Invoice, st domain.InstalmentStatus, asOf time.Time) dto.InstalmentDTO {
+	return dto.InstalmentDTO{
+		Number:    st.Number,
+		DueDate:   st.DueDate.Format("2006-01-02"),
+		Amount:    st.Amount.Decimal(),
+		Paid:      st.Paid.Decimal(),
+		Remaining: st.Remaining.Decimal(),
+		Overdue:   inv.Status != domain.InvoiceStatusVoid && st.IsOverdue(asOf),
+	}
+}
@@ -25,10 +25,11 @@ func (uc *ListPaymentsUseCase) Execute(ctx context.Context) ([]dto.PaymentDTO, e
 		dtos[i] = dto.PaymentDTO{
 			ID:              string(p.ID),
 			CustomerID:      string(p.CustomerID),
-			Amount:          float64(p.Amount.Amount()) / 100.0,
-			AvailableAmount: float64(p.AvailableAmount.Amount()) / 100.0,
+			Amount:          p.Amount.Decimal(),
+			AvailableAmount: p.AvailableAmount.Decimal(),
 			Currency:        p.Amount.Currency(),
 			Date:            p.Date.Format("2006-01-02"),
+			Reversed:        p.IsReversed(),
 		}
 	}
 	return dtos, nil
@@ -0,0 +1,42 @@
+package usecases
+
+import (
+	"carigo/internal/application/dto"
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"context"
+	"strings"
+)
+
+type ListReconciliationsUseCase struct {
+	repo     ports.ReconciliationRepository
+	custRepo ports.CustomerRepository
+}
+
+func NewListReconciliationsUseCase(r ports.ReconciliationRepository, c ports.CustomerRepository) *ListReconciliationsUseCase {
+	return &ListReconciliationsUseCase{repo: r, custRepo: c}
+}
+
+// Execute lists the reconciliations in status, newest first, or all of them
+// if status is empty. The DISPUTED ones carry the differences to
+// investigate.
+func (uc *ListReconciliationsUseCase) Execute(ctx context.Context, status string) ([]dto.ReconciliationDTO, error) {
+	reconciliations, err := uc.repo.FindByStatus(ctx, domain.ReconciliationStatus(strings.ToUpper(status)))
+	if err != nil {
+		return nil, err
+	}
+	customers, err := uc.custRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[domain.CustomerID]string, len(customers))
+	for _, c := range customers {
+		names[c.ID] = c.Name
+	}
+
+	dtos := make([]dto.ReconciliationDTO, len(reconciliations))
+	for i, r := range reconciliations {
+		dtos[i] = mapReconciliation(r, names[r.CustomerID])
+	}
+	return dtos, nil
+}
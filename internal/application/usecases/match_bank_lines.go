@@ -0,0 +1,124 @@
+package usecases
+
+import (
+	"carigo/internal/application/dto"
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"context"
+	"fmt"
+	"time"
+)
+
+// MatchBankLinesUseCase runs the open bank lines through the matching rules.
+// A line whose best customer is certain enough is posted as that customer's
+// payment through RegisterPaymentUseCase, which allocates it as any other
+// payment. A line with a likely customer is MATCHED for a clerk to confirm,
+// and the rest stay NEW in the review queue.
+type MatchBankLinesUseCase struct {
+	lineRepo        ports.BankLineRepository
+	customerRepo    ports.CustomerRepository
+	invoiceRepo     ports.InvoiceRepository
+	ruleRepo        ports.BankMatchRuleRepository
+	registerPayment *RegisterPaymentUseCase
+	txManager       ports.TransactionManager
+	clock           ports.Clock
+}
+
+func NewMatchBankLinesUseCase(
+	lr ports.BankLineRepository,
+	cr ports.CustomerRepository,
+	ir ports.InvoiceRepository,
+	rr ports.BankMatchRuleRepository,
+	rp *RegisterPaymentUseCase,
+	tm ports.TransactionManager,
+	clk ports.Clock,
+) *MatchBankLinesUseCase {
+	return &MatchBankLinesUseCase{
+		lineRepo:        lr,
+		customerRepo:    cr,
+		invoiceRepo:     ir,
+		ruleRepo:        rr,
+		registerPayment: rp,
+		txManager:       tm,
+		clock:           clk,
+	}
+}
+
+func (uc *MatchBankLinesUseCase) Execute(ctx context.Context) (*dto.MatchBankLinesResponse, error) {
+	var lines []*domain.BankLine
+	for _, state := range []domain.BankLineState{domain.BankLineStateNew, domain.BankLineStateMatched} {
+		found, err := uc.lineRepo.FindByState(ctx, state)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, found...)
+	}
+
+	matcher, err := loadBankMatcher(ctx, uc.customerRepo, uc.invoiceRepo, uc.ruleRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.MatchBankLinesResponse{Lines: []dto.BankLineDTO{}}
+	for _, line := range lines {
+		match := matcher.match(line)
+
+		if best, ok := match.Best(domain.AutoPostScore); ok {
+			if err := uc.post(ctx, line, best.CustomerID); err != nil {
+				res.Errors = append(res.Errors, fmt.Sprintf("%s: %v", line.ID, err))
+				continue
+			}
+			if err := matcher.reload(ctx, best.CustomerID); err != nil {
+				return nil, err
+			}
+			res.Posted++
+			res.Lines = append(res.Lines, mapBankLine(line))
+			continue
+		}
+
+		best, ok := match.Best(domain.SuggestScore)
+		if !ok {
+			res.Unmatched++
+			continue
+		}
+		res.Suggested++
+		if line.CustomerID == best.CustomerID {
+			continue
+		}
+		if err := line.Match(best.CustomerID, uc.clock.Now()); err != nil {
+			return nil, err
+		}
+		if err := uc.lineRepo.Save(ctx, line); err != nil {
+			return nil, err
+		}
+		res.Lines = append(res.Lines, mapBankLine(line))
+	}
+	return res, nil
+}
+
+// post registers the line as a payment of customerID, all or nothing.
+func (uc *MatchBankLinesUseCase) post(ctx context.Context, line *domain.BankLine, customerID domain.CustomerID) error {
+	return uc.txManager.Do(ctx, func(ctx context.Context) error {
+		_, err := postBankLine(ctx, uc.lineRepo, uc.registerPayment, line, customerID, uc.clock.Now())
+		return err
+	})
+}
+
+// postBankLine matches the line to customerID and registers it as their
+// payment. It must run in a transaction.
+func postBankLine(ctx context.Context, lineRepo ports.BankLineRepository, registerPayment *RegisterPaymentUseCase, line *domain.BankLine, customerID domain.CustomerID, now time.Time) (*dto.RegisterPaymentResponse, error) {
+	if err := line.Match(customerID, now); err != nil {
+		return nil, err
+	}
+	payment, err := registerPayment.Execute(ctx, candidatePayment(line))
+	if err != nil {
+		return nil, err
+	}
+	if err := line.Post(domain.PaymentID(payment.PaymentID), now); err != nil {
+		return nil, err
+	}
+	if err := lineRepo.Save(ctx, line); err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
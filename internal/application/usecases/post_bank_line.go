@@ -0,0 +1,79 @@
+package usecases
+
+import (
+	"carigo/internal/application/dto"
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"context"
+)
+
+// PostBankLineUseCase registers an open bank line as a payment of the
+// customer a clerk picked for it. When asked to, it remembers the payer's
+// IBAN for that customer, so the next transfer from it is posted without
+// review.
+type PostBankLineUseCase struct {
+	lineRepo        ports.BankLineRepository
+	customerRepo    ports.CustomerRepository
+	ruleRepo        ports.BankMatchRuleRepository
+	registerPayment *RegisterPaymentUseCase
+	txManager       ports.TransactionManager
+	clock           ports.Clock
+}
+
+func NewPostBankLineUseCase(
+	lr ports.BankLineRepository,
+	cr ports.CustomerRepository,
+	rr ports.BankMatchRuleRepository,
+	rp *RegisterPaymentUseCase,
+	tm ports.TransactionManager,
+	clk ports.Clock,
+) *PostBankLineUseCase {
+	return &PostBankLineUseCase{
+		lineRepo:        lr,
+		customerRepo:    cr,
+		ruleRepo:        rr,
+		registerPayment: rp,
+		txManager:       tm,
+		clock:           clk,
+	}
+}
+
+func (uc *PostBankLineUseCase) Execute(ctx context.Context, id string, req dto.PostBankLineRequest) (*dto.PostBankLineResponse, error) {
+	customer, err := uc.customerRepo.FindByID(ctx, domain.CustomerID(req.CustomerID))
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.PostBankLineResponse{}
+	err = uc.txManager.Do(ctx, func(ctx context.Context) error {
+		line, err := uc.lineRepo.FindByID(ctx, domain.BankLineID(id))
+		if err != nil {
+			return err
+		}
+		now := uc.clock.Now()
+
+		res.Payment, err = postBankLine(ctx, uc.lineRepo, uc.registerPayment, line, customer.ID, now)
+		if err != nil {
+			return err
+		}
+		res.Line = mapBankLine(line)
+
+		if !req.RememberIBAN || line.PayerIBAN == "" {
+			return nil
+		}
+		rule, err := domain.NewBankMatchRule(line.PayerIBAN, customer.ID, now)
+		if err != nil {
+			return err
+		}
+		if err := uc.ruleRepo.Save(ctx, rule); err != nil {
+			return err
+		}
+		learned := mapBankMatchRule(rule)
+		res.Rule = &learned
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,100 @@
+package usecases
+
+import (
+	"carigo/internal/application/dto"
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"context"
+	"fmt"
+)
+
+// ProposeAllocationUseCase builds an allocation plan for an incoming payment
+// without touching the ledger. The plan is kept until it is confirmed.
+type ProposeAllocationUseCase struct {
+	invoiceRepo  ports.InvoiceRepository
+	customerRepo ports.CustomerRepository
+	planStore    ports.AllocationPlanStore
+	rates        ports.ExchangeRateProvider
+	clock        ports.Clock
+}
+
+func NewProposeAllocationUseCase(ir ports.InvoiceRepository, cr ports.CustomerRepository, ps ports.AllocationPlanStore, rp ports.ExchangeRateProvider, clk ports.Clock) *ProposeAllocationUseCase {
+	return &ProposeAllocationUseCase{
+		invoiceRepo:  ir,
+		customerRepo: cr,
+		planStore:    ps,
+		rates:        rp,
+		clock:        clk,
+	}
+}
+
+func (uc *ProposeAllocationUseCase) Execute(ctx context.Context, req dto.RegisterPaymentRequest) (*dto.AllocationPlanDTO, error) {
+	amount, err := domain.NewMoney(req.Amount, req.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("invalid money: %w", err)
+	}
+
+	date := req.Date
+	if date.IsZero() {
+		date = uc.clock.Now()
+	}
+
+	customerID := domain.CustomerID(req.CustomerID)
+	strategy, err := resolveAllocationStrategy(ctx, uc.customerRepo, customerID, req.AllocationStrategy)
+	if err != nil {
+		return nil, err
+	}
+
+	invoices, err := uc.invoiceRepo.FindOpenByCustomer(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	rates, err := ratesForInvoices(ctx, uc.rates, amount.Currency(), invoices, date)
+	if err != nil {
+		return nil, err
+	}
+
+	planID := domain.AllocationPlanID(fmt.Sprintf("PLAN-%d", uc.clock.Now().UnixNano()))
+	plan, err := domain.NewPaymentAllocationPlan(planID, customerID, amount, date, invoices, strategy, rates)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.planStore.Save(ctx, plan); err != nil {
+		return nil, err
+	}
+
+	return mapAllocationPlan(plan), nil
+}
+
+func mapAllocationPlan(plan *domain.AllocationPlan) *dto.AllocationPlanDTO {
+	lines := make([]dto.PlannedAllocationDTO, len(plan.Lines))
+	for i, l := range plan.Lines {
+		lines[i] = dto.PlannedAllocationDTO{
+			InvoiceID:          string(l.InvoiceID),
+			Amount:             l.Amount.Amount(),
+			RemainingDebtAfter: l.RemainingAfter.Amount(),
+		}
+		if !l.Discount.IsZero() {
+			lines[i].Discount = l.Discount.Decimal()
+		}
+		if l.Rate != nil {
+			lines[i].InvoiceAmount = l.InvoiceAmount.Amount()
+			lines[i].InvoiceCurrency = l.InvoiceAmount.Currency()
+			lines[i].ExchangeRate = l.Rate.String()
+		}
+	}
+
+	return &dto.AllocationPlanDTO{
+		PlanID:            string(plan.ID),
+		CustomerID:        string(plan.CustomerID),
+		Amount:            plan.Amount.Amount(),
+		Currency:          plan.Amount.Currency(),
+		Date:              plan.Date,
+		Strategy:          string(plan.Strategy),
+		AllocatedAmount:   plan.AllocatedAmount().Amount(),
+		UnallocatedAmount: plan.Unallocated.Amount(),
+		Lines:             lines,
+	}
+}
@@ -0,0 +1,74 @@
+package usecases
+
+import (
+	"carigo/internal/application/dto"
+	"carigo/internal/domain"
+	"fmt"
+	"strings"
+)
+
+func mapReconciliation(r *domain.Reconciliation, customerName string) dto.ReconciliationDTO {
+	res := dto.ReconciliationDTO{
+		ID:           string(r.ID),
+		CustomerID:   string(r.CustomerID),
+		CustomerName: customerName,
+		AsOf:         r.AsOf,
+		Status:       string(r.Status),
+		Balances:     currencyAmounts(r.Balances),
+		Note:         r.Note,
+		CreatedAt:    r.CreatedAt,
+		RespondedAt:  r.RespondedAt,
+	}
+	if len(r.Claimed) > 0 {
+		res.Claimed = currencyAmounts(r.Claimed)
+	}
+	for _, d := range r.Differences() {
+		res.Differences = append(res.Differences, dto.ReconciliationDifferenceDTO{
+			Currency:   d.Difference.Currency(),
+			Ours:       d.Ours.Decimal(),
+			Theirs:     d.Theirs.Decimal(),
+			Difference: d.Difference.Decimal(),
+		})
+	}
+	return res
+}
+
+func currencyAmounts(balances []domain.Balance) []dto.CurrencyAmount {
+	amounts := make([]dto.CurrencyAmount, len(balances))
+	for i, b := range balances {
+		amounts[i] = dto.CurrencyAmount{Currency: b.Currency(), Amount: b.Decimal()}
+	}
+	return amounts
+}
+
+// reconciliationLetter is the confirmation request as plain text, stating
+// each balance with the side it is on.
+func reconciliationLetter(r *domain.Reconciliation, customer *domain.Customer) (subject, body string) {
+	day := r.AsOf.Format("02.01.2006")
+	subject = fmt.Sprintf("Cari Hesap Mutabakatı - %s", day)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Sayın %s,\n\n", customer.Name)
+	fmt.Fprintf(&b, "Kayıtlarımıza göre %s tarihi itibarıyla cari hesabınızın bakiyesi aşağıdaki gibidir:\n\n", day)
+	if len(r.Balances) == 0 {
+		b.WriteString("  Bakiye yoktur.\n")
+	}
+	for _, balance := range r.Balances {
+		fmt.Fprintf(&b, "  %s %s\n", balance.Abs().Format(domain.LocaleTR), balanceSide(balance))
+	}
+	b.WriteString("\nBakiyemizle mutabık olup olmadığınızı bildirmenizi rica ederiz. ")
+	b.WriteString("Mutabık değilseniz kayıtlarınızdaki bakiyeyi ve hesap ekstrenizi iletiniz.\n\n")
+	fmt.Fprintf(&b, "Mutabakat No: %s\nVergi/TC No: %s\n", r.ID, customer.TaxID)
+	return subject, b.String()
+}
+
+// balanceSide names the side of a balance from the customer's point of view.
+func balanceSide(b domain.Balance) string {
+	switch {
+	case b.IsDebit():
+		return "BORÇ"
+	case b.Amount() < 0:
+		return "ALACAK"
+	}
+	return ""
+}
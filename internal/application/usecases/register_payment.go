@@ -12,6 +12,8 @@ type RegisterPaymentUseCase struct {
 	paymentRepo    ports.PaymentRepository
 	invoiceRepo    ports.InvoiceRepository
 	allocationRepo ports.AllocationRepository
+	customerRepo   ports.CustomerRepository
+	rates          ports.ExchangeRateProvider
 	txManager      ports.TransactionManager
 	clock          ports.Clock
 }
@@ -20,6 +22,8 @@ func NewRegisterPaymentUseCase(
 	pr ports.PaymentRepository,
 	ir ports.InvoiceRepository,
 	ar ports.AllocationRepository,
+	cr ports.CustomerRepository,
+	rp ports.ExchangeRateProvider,
 	tm ports.TransactionManager,
 	clk ports.Clock,
 ) *RegisterPaymentUseCase {
@@ -27,6 +31,8 @@ func NewRegisterPaymentUseCase(
 		paymentRepo:    pr,
 		invoiceRepo:    ir,
 		allocationRepo: ar,
+		customerRepo:   cr,
+		rates:          rp,
 		txManager:      tm,
 		clock:          clk,
 	}
@@ -43,71 +49,40 @@ func (uc *RegisterPaymentUseCase) Execute(ctx context.Context, req dto.RegisterP
 		date = uc.clock.Now()
 	}
 
-	paymentID := domain.PaymentID(fmt.Sprintf("PAY-%d", date.UnixNano()))
+	strategy, err := resolveAllocationStrategy(ctx, uc.customerRepo, domain.CustomerID(req.CustomerID), req.AllocationStrategy)
+	if err != nil {
+		return nil, err
+	}
+
+	// The ID comes from the clock rather than the payment date, which many
+	// payments share, e.g. when a bank statement is posted.
+	paymentID := domain.PaymentID(fmt.Sprintf("PAY-%d", uc.clock.Now().UnixNano()))
 	payment := domain.NewPayment(paymentID, domain.CustomerID(req.CustomerID), amount, date)
-	
-	allocatedItems := []dto.AllocatedInvoiceParams{}
-	totalAllocated := int64(0)
+
+	var allocatedItems []dto.AllocatedInvoiceParams
+	var plan *domain.AllocationPlan
 
 	err = uc.txManager.Do(ctx, func(ctx context.Context) error {
-		if err := uc.paymentRepo.Save(ctx, payment); err != nil {
-			return err
-		}
 		invoices, err := uc.invoiceRepo.FindOpenByCustomer(ctx, domain.CustomerID(req.CustomerID))
 		if err != nil {
 			return err
 		}
 
-		for _, inv := range invoices {
-			if payment.AvailableAmount.IsZero() {
-				break
-			}
-			remainingDebt := inv.RemainingAmount()
-			
-			available := payment.AvailableAmount
-
-			var allocationAmount domain.Money
-			
-			if remainingDebt.Currency() != available.Currency() {
-				continue
-			}
-
-			isDebtLarger, _ := remainingDebt.GreaterThan(available)
-			if isDebtLarger {
-				allocationAmount = available
-			} else {
-				allocationAmount = remainingDebt
-			}
-
-			if allocationAmount.IsZero() {
-				continue
-			}
-
-			allocID := domain.AllocationID(fmt.Sprintf("AL-%s-%s", payment.ID, inv.ID))
-			allocation, err := domain.NewAllocation(allocID, payment, inv, allocationAmount)
-			if err != nil {
-				return err
-			}
-
-			if err := uc.invoiceRepo.Save(ctx, inv); err != nil {
-				return err
-			}
-			if err := uc.paymentRepo.Save(ctx, payment); err != nil {
-				return err
-			}
-
-			if err := uc.allocationRepo.Save(ctx, allocation); err != nil {
-				return err
-			}
+		rates, err := ratesForInvoices(ctx, uc.rates, amount.Currency(), invoices, date)
+		if err != nil {
+			return err
+		}
 
-			allocatedItems = append(allocatedItems, dto.AllocatedInvoiceParams{
-				InvoiceID: string(inv.ID),
-				Amount:    allocationAmount.Amount(),
-			})
-			totalAllocated += allocationAmount.Amount()
+		plan, err = domain.NewPaymentAllocationPlan(domain.AllocationPlanID(paymentID), payment.CustomerID, amount, date, invoices, strategy, rates)
+		if err != nil {
+			return err
 		}
 
-		return nil
+		allocatedItems, err = bookAllocationPlan(ctx, plan, payment, string(payment.ID), invoices, uc.invoiceRepo, uc.allocationRepo)
+		if err != nil {
+			return err
+		}
+		return uc.paymentRepo.Save(ctx, payment)
 	})
 
 	if err != nil {
@@ -116,7 +91,7 @@ func (uc *RegisterPaymentUseCase) Execute(ctx context.Context, req dto.RegisterP
 
 	return &dto.RegisterPaymentResponse{
 		PaymentID:         string(payment.ID),
-		AllocatedAmount:   totalAllocated,
+		AllocatedAmount:   plan.AllocatedAmount().Amount(),
 		RemainingBalance:  payment.AvailableAmount.Amount(),
 		AllocatedInvoices: allocatedItems,
 	}, nil
@@ -0,0 +1,59 @@
+package usecases
+
+import (
+	"carigo/internal/application/dto"
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"context"
+	"strings"
+)
+
+// RespondReconciliationUseCase records whether the customer confirmed a
+// reconciliation or disputed it with their own balances.
+type RespondReconciliationUseCase struct {
+	repo     ports.ReconciliationRepository
+	custRepo ports.CustomerRepository
+	clock    ports.Clock
+}
+
+func NewRespondReconciliationUseCase(r ports.ReconciliationRepository, c ports.CustomerRepository, clk ports.Clock) *RespondReconciliationUseCase {
+	return &RespondReconciliationUseCase{repo: r, custRepo: c, clock: clk}
+}
+
+func (uc *RespondReconciliationUseCase) Execute(ctx context.Context, id string, req dto.RespondReconciliationRequest) (*dto.ReconciliationDTO, error) {
+	r, err := uc.repo.FindByID(ctx, domain.ReconciliationID(id))
+	if err != nil {
+		return nil, err
+	}
+
+	now := uc.clock.Now()
+	switch domain.ReconciliationStatus(req.Status) {
+	case domain.ReconciliationStatusAgreed:
+		err = r.Agree(now)
+	case domain.ReconciliationStatusDisputed:
+		claimed := make([]domain.Balance, 0, len(req.ClaimedBalances))
+		for _, c := range req.ClaimedBalances {
+			b, err := domain.NewBalanceOf(c.Amount, strings.ToUpper(c.Currency))
+			if err != nil {
+				return nil, err
+			}
+			claimed = append(claimed, b)
+		}
+		err = r.Dispute(claimed, strings.TrimSpace(req.Note), now)
+	default:
+		err = domain.ErrInvalidReconciliation
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.repo.Save(ctx, r); err != nil {
+		return nil, err
+	}
+
+	customer, err := uc.custRepo.FindByID(ctx, r.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+	res := mapReconciliation(r, customer.Name)
+	return &res, nil
+}
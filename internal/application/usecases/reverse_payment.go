@@ -0,0 +1,87 @@
+package usecases
+
+import (
+	"carigo/internal/application/dto"
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"context"
+)
+
+// ReversePaymentUseCase cancels a payment booked by mistake, e.g. against the
+// wrong customer. Every active allocation is reversed first, then the payment
+// itself is marked as reversed. Nothing is deleted.
+type ReversePaymentUseCase struct {
+	paymentRepo    ports.PaymentRepository
+	invoiceRepo    ports.InvoiceRepository
+	allocationRepo ports.AllocationRepository
+	txManager      ports.TransactionManager
+	clock          ports.Clock
+}
+
+func NewReversePaymentUseCase(
+	pr ports.PaymentRepository,
+	ir ports.InvoiceRepository,
+	ar ports.AllocationRepository,
+	tm ports.TransactionManager,
+	clk ports.Clock,
+) *ReversePaymentUseCase {
+	return &ReversePaymentUseCase{
+		paymentRepo:    pr,
+		invoiceRepo:    ir,
+		allocationRepo: ar,
+		txManager:      tm,
+		clock:          clk,
+	}
+}
+
+func (uc *ReversePaymentUseCase) Execute(ctx context.Context, paymentID string, req dto.ReversalRequest) (*dto.ReversalResponse, error) {
+	var payment *domain.Payment
+	reversedItems := []dto.ReversedAllocationParams{}
+
+	err := uc.txManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		payment, err = uc.paymentRepo.FindByID(ctx, domain.PaymentID(paymentID))
+		if err != nil {
+			return err
+		}
+		if payment.IsReversed() {
+			return domain.ErrPaymentAlreadyReversed
+		}
+
+		allocations, err := uc.allocationRepo.FindByPayment(ctx, payment.ID)
+		if err != nil {
+			return err
+		}
+
+		now := uc.clock.Now()
+		for _, allocation := range allocations {
+			if !allocation.IsActive() {
+				continue
+			}
+			invoice, err := uc.invoiceRepo.FindByID(ctx, allocation.InvoiceID)
+			if err != nil {
+				return err
+			}
+			reversed, err := reverseAllocation(ctx, allocation, payment, invoice, req.Reason, now, uc.invoiceRepo, uc.allocationRepo)
+			if err != nil {
+				return err
+			}
+			reversedItems = append(reversedItems, reversed)
+		}
+
+		if err := payment.Reverse(req.Reason, now); err != nil {
+			return err
+		}
+		return uc.paymentRepo.Save(ctx, payment)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.ReversalResponse{
+		PaymentID:           string(payment.ID),
+		PaymentReversed:     true,
+		RemainingBalance:    payment.AvailableAmount.Amount(),
+		ReversedAllocations: reversedItems,
+	}, nil
+}
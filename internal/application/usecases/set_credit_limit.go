@@ -0,0 +1,41 @@
+package usecases
+
+import (
+	"carigo/internal/application/dto"
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"context"
+	"fmt"
+)
+
+// SetCreditLimitUseCase changes a customer's credit limit in one currency.
+// Invoices already issued stay as they are, even if the customer is now over
+// the limit; only new invoices are checked.
+type SetCreditLimitUseCase struct {
+	customerRepo ports.CustomerRepository
+}
+
+func NewSetCreditLimitUseCase(cr ports.CustomerRepository) *SetCreditLimitUseCase {
+	return &SetCreditLimitUseCase{customerRepo: cr}
+}
+
+func (uc *SetCreditLimitUseCase) Execute(ctx context.Context, customerID string, req dto.SetCreditLimitRequest) (*dto.CustomerDTO, error) {
+	limit, err := domain.NewMoney(req.Limit, req.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidCreditLimit, err)
+	}
+
+	customer, err := uc.customerRepo.FindByID(ctx, domain.CustomerID(customerID))
+	if err != nil {
+		return nil, err
+	}
+	if err := customer.SetCreditLimit(limit); err != nil {
+		return nil, err
+	}
+	if err := uc.customerRepo.Save(ctx, customer); err != nil {
+		return nil, err
+	}
+
+	res := mapCustomer(customer)
+	return &res, nil
+}
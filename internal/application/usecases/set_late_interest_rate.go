@@ -0,0 +1,37 @@
+package usecases
+
+import (
+	"carigo/internal/application/dto"
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"context"
+)
+
+// SetLateInterestRateUseCase changes the late interest rate charged to a
+// customer. Interest is always calculated with the current rate.
+type SetLateInterestRateUseCase struct {
+	customerRepo ports.CustomerRepository
+}
+
+func NewSetLateInterestRateUseCase(cr ports.CustomerRepository) *SetLateInterestRateUseCase {
+	return &SetLateInterestRateUseCase{customerRepo: cr}
+}
+
+func (uc *SetLateInterestRateUseCase) Execute(ctx context.Context, customerID string, req dto.SetLateInterestRateRequest) (*dto.CustomerDTO, error) {
+	rate, err := domain.ParseLateInterestRate(req.Rate, req.Period)
+	if err != nil {
+		return nil, err
+	}
+
+	customer, err := uc.customerRepo.FindByID(ctx, domain.CustomerID(customerID))
+	if err != nil {
+		return nil, err
+	}
+	customer.SetLateInterestRate(rate)
+	if err := uc.customerRepo.Save(ctx, customer); err != nil {
+		return nil, err
+	}
+
+	res := mapCustomer(customer)
+	return &res, nil
+}
@@ -0,0 +1,37 @@
+package usecases
+
+import (
+	"carigo/internal/application/dto"
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"context"
+)
+
+// SetPaymentTermsUseCase changes the default payment terms of a customer.
+// Invoices already issued keep the terms they were issued with.
+type SetPaymentTermsUseCase struct {
+	customerRepo ports.CustomerRepository
+}
+
+func NewSetPaymentTermsUseCase(cr ports.CustomerRepository) *SetPaymentTermsUseCase {
+	return &SetPaymentTermsUseCase{customerRepo: cr}
+}
+
+func (uc *SetPaymentTermsUseCase) Execute(ctx context.Context, customerID string, req dto.PaymentTermsRequest) (*dto.CustomerDTO, error) {
+	terms, err := parsePaymentTerms(req)
+	if err != nil {
+		return nil, err
+	}
+
+	customer, err := uc.customerRepo.FindByID(ctx, domain.CustomerID(customerID))
+	if err != nil {
+		return nil, err
+	}
+	customer.SetPaymentTerms(terms)
+	if err := uc.customerRepo.Save(ctx, customer); err != nil {
+		return nil, err
+	}
+
+	res := mapCustomer(customer)
+	return &res, nil
+}
@@ -0,0 +1,136 @@
+package usecases
+
+import (
+	"carigo/internal/application/dto"
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"context"
+	"time"
+)
+
+// UnapplyAllocationUseCase takes a single allocation off its invoice and gives
+// the amount back to its payment or credit note as unallocated credit. The
+// early-payment discount the payment earned on that invoice goes with it.
+type UnapplyAllocationUseCase struct {
+	allocationRepo ports.AllocationRepository
+	paymentRepo    ports.PaymentRepository
+	creditNoteRepo ports.CreditNoteRepository
+	invoiceRepo    ports.InvoiceRepository
+	txManager      ports.TransactionManager
+	clock          ports.Clock
+}
+
+func NewUnapplyAllocationUseCase(
+	ar ports.AllocationRepository,
+	pr ports.PaymentRepository,
+	cnr ports.CreditNoteRepository,
+	ir ports.InvoiceRepository,
+	tm ports.TransactionManager,
+	clk ports.Clock,
+) *UnapplyAllocationUseCase {
+	return &UnapplyAllocationUseCase{
+		allocationRepo: ar,
+		paymentRepo:    pr,
+		creditNoteRepo: cnr,
+		invoiceRepo:    ir,
+		txManager:      tm,
+		clock:          clk,
+	}
+}
+
+func (uc *UnapplyAllocationUseCase) Execute(ctx context.Context, allocationID string, req dto.ReversalRequest) (*dto.ReversalResponse, error) {
+	var source domain.AllocationSource
+	var reversedItems []dto.ReversedAllocationParams
+
+	err := uc.txManager.Do(ctx, func(ctx context.Context) error {
+		allocation, err := uc.allocationRepo.FindByID(ctx, domain.AllocationID(allocationID))
+		if err != nil {
+			return err
+		}
+		source, err = findAllocationSource(ctx, allocation, uc.paymentRepo, uc.creditNoteRepo)
+		if err != nil {
+			return err
+		}
+		invoice, err := uc.invoiceRepo.FindByID(ctx, allocation.InvoiceID)
+		if err != nil {
+			return err
+		}
+
+		now := uc.clock.Now()
+		reversed, err := reverseAllocation(ctx, allocation, source, invoice, req.Reason, now, uc.invoiceRepo, uc.allocationRepo)
+		if err != nil {
+			return err
+		}
+		reversedItems = append(reversedItems, reversed)
+
+		if allocation.PaymentID != "" && !allocation.IsDiscount() {
+			siblings, err := uc.allocationRepo.FindByPayment(ctx, allocation.PaymentID)
+			if err != nil {
+				return err
+			}
+			for _, s := range siblings {
+				if !s.IsDiscount() || !s.IsActive() || s.InvoiceID != allocation.InvoiceID {
+					continue
+				}
+				reversed, err := reverseAllocation(ctx, s, source, invoice, req.Reason, now, uc.invoiceRepo, uc.allocationRepo)
+				if err != nil {
+					return err
+				}
+				reversedItems = append(reversedItems, reversed)
+			}
+		}
+		return saveAllocationSource(ctx, source, uc.paymentRepo, uc.creditNoteRepo)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.ReversalResponse{
+		ReversedAllocations: reversedItems,
+	}
+	switch s := source.(type) {
+	case *domain.Payment:
+		res.PaymentID = string(s.ID)
+		res.RemainingBalance = s.AvailableAmount.Amount()
+	case *domain.CreditNote:
+		res.CreditNoteID = string(s.ID)
+		res.RemainingBalance = s.AvailableAmount.Amount()
+	}
+	return res, nil
+}
+
+// reverseAllocation records a reversal entry for allocation and persists the
+// reopened invoice. The caller saves the source. It must run inside a transaction.
+func reverseAllocation(
+	ctx context.Context,
+	allocation *domain.Allocation,
+	source domain.AllocationSource,
+	invoice *domain.Invoice,
+	reason string,
+	at time.Time,
+	ir ports.InvoiceRepository,
+	ar ports.AllocationRepository,
+) (dto.ReversedAllocationParams, error) {
+	reversalID := domain.AllocationID("REV-" + string(allocation.ID))
+	reversal, err := allocation.Reverse(reversalID, source, invoice, reason, at)
+	if err != nil {
+		return dto.ReversedAllocationParams{}, err
+	}
+
+	if err := ir.Save(ctx, invoice); err != nil {
+		return dto.ReversedAllocationParams{}, err
+	}
+	if err := ar.Save(ctx, allocation); err != nil {
+		return dto.ReversedAllocationParams{}, err
+	}
+	if err := ar.Save(ctx, reversal); err != nil {
+		return dto.ReversedAllocationParams{}, err
+	}
+
+	return dto.ReversedAllocationParams{
+		AllocationID: string(allocation.ID),
+		ReversalID:   string(reversal.ID),
+		InvoiceID:    string(allocation.InvoiceID),
+		Amount:       allocation.Amount.Amount(),
+	}, nil
+}
@@ -0,0 +1,107 @@
+package usecases
+
+import (
+	"carigo/internal/application/dto"
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"context"
+)
+
+// VoidInvoiceUseCase cancels an invoice. Amounts already allocated to it are
+// released back to their payments or credit notes as on-account credit before
+// voiding. Voiding a kur farkı invoice unbills the gains it billed, so their
+// allocations can be reversed and the gains billed again.
+type VoidInvoiceUseCase struct {
+	invoiceRepo    ports.InvoiceRepository
+	paymentRepo    ports.PaymentRepository
+	creditNoteRepo ports.CreditNoteRepository
+	allocationRepo ports.AllocationRepository
+	txManager      ports.TransactionManager
+	clock          ports.Clock
+}
+
+func NewVoidInvoiceUseCase(
+	ir ports.InvoiceRepository,
+	pr ports.PaymentRepository,
+	cnr ports.CreditNoteRepository,
+	ar ports.AllocationRepository,
+	tm ports.TransactionManager,
+	clk ports.Clock,
+) *VoidInvoiceUseCase {
+	return &VoidInvoiceUseCase{
+		invoiceRepo:    ir,
+		paymentRepo:    pr,
+		creditNoteRepo: cnr,
+		allocationRepo: ar,
+		txManager:      tm,
+		clock:          clk,
+	}
+}
+
+func (uc *VoidInvoiceUseCase) Execute(ctx context.Context, invoiceID string, req dto.ReversalRequest) (*dto.VoidInvoiceResponse, error) {
+	var invoice *domain.Invoice
+	released := []dto.ReversedAllocationParams{}
+
+	err := uc.txManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		invoice, err = uc.invoiceRepo.FindByID(ctx, domain.InvoiceID(invoiceID))
+		if err != nil {
+			return err
+		}
+
+		if !invoice.PaidAmount.IsZero() {
+			allocations, err := uc.allocationRepo.FindByInvoice(ctx, invoice.ID)
+			if err != nil {
+				return err
+			}
+
+			now := uc.clock.Now()
+			for _, allocation := range allocations {
+				if !allocation.IsActive() {
+					continue
+				}
+				source, err := findAllocationSource(ctx, allocation, uc.paymentRepo, uc.creditNoteRepo)
+				if err != nil {
+					return err
+				}
+				reversed, err := reverseAllocation(ctx, allocation, source, invoice, "Fatura iptali: "+req.Reason, now, uc.invoiceRepo, uc.allocationRepo)
+				if err != nil {
+					return err
+				}
+				if err := saveAllocationSource(ctx, source, uc.paymentRepo, uc.creditNoteRepo); err != nil {
+					return err
+				}
+				released = append(released, reversed)
+			}
+		}
+
+		if err := invoice.Void(req.Reason); err != nil {
+			return err
+		}
+		if invoice.Kind == domain.InvoiceKindExchangeDifference {
+			billed, err := uc.allocationRepo.FindByExchangeDifferenceInvoice(ctx, invoice.ID)
+			if err != nil {
+				return err
+			}
+			for _, allocation := range billed {
+				if err := allocation.ReleaseExchangeDifference(invoice); err != nil {
+					return err
+				}
+				if err := uc.allocationRepo.Save(ctx, allocation); err != nil {
+					return err
+				}
+			}
+		}
+		return uc.invoiceRepo.Save(ctx, invoice)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.VoidInvoiceResponse{
+		InvoiceID:           string(invoice.ID),
+		Status:              string(invoice.Status),
+		Reason:              invoice.VoidReason,
+		ReleasedAllocations: released,
+	}, nil
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AgingBuckets are the upper edges, in days past due, of the aging buckets
+// after "current". Edges {30, 60, 90} give current, 1-30, 31-60, 61-90 and
+// 90+: len(edges)+2 buckets in total.
+type AgingBuckets []int
+
+// DefaultAgingBuckets is the usual 30/60/90 split.
+var DefaultAgingBuckets = AgingBuckets{30, 60, 90}
+
+// NewAgingBuckets validates that edges are positive and strictly increasing.
+// No edges at all means DefaultAgingBuckets.
+func NewAgingBuckets(edges []int) (AgingBuckets, error) {
+	if len(edges) == 0 {
+		return DefaultAgingBuckets, nil
+	}
+	for i, e := range edges {
+		if e <= 0 || (i > 0 && e <= edges[i-1]) {
+			return nil, ErrInvalidAgingBuckets
+		}
+	}
+	return AgingBuckets(edges), nil
+}
+
+// ParseAgingBuckets reads edges written as "30,60,90".
+func ParseAgingBuckets(s string) (AgingBuckets, error) {
+	var edges []int
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		e, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, ErrInvalidAgingBuckets
+		}
+		edges = append(edges, e)
+	}
+	return NewAgingBuckets(edges)
+}
+
+// Len is the number of buckets, including current and the open-ended last one.
+func (b AgingBuckets) Len() int {
+	return len(b) + 2
+}
+
+// Index returns the bucket of an amount that is daysPastDue days late: 0 for
+// amounts not yet due, Len()-1 for amounts older than the last edge.
+func (b AgingBuckets) Index(daysPastDue int) int {
+	if daysPastDue <= 0 {
+		return 0
+	}
+	for i, e := range b {
+		if daysPastDue <= e {
+			return i + 1
+		}
+	}
+	return len(b) + 1
+}
+
+// Labels names the buckets in order: "current", "1-30", ..., "90+".
+func (b AgingBuckets) Labels() []string {
+	labels := make([]string, 0, b.Len())
+	labels = append(labels, "current")
+	lower := 1
+	for _, e := range b {
+		labels = append(labels, strconv.Itoa(lower)+"-"+strconv.Itoa(e))
+		lower = e + 1
+	}
+	last := 0
+	if len(b) > 0 {
+		last = b[len(b)-1]
+	}
+	return append(labels, strconv.Itoa(last)+"+")
+}
+
+// DaysPastDue counts whole calendar days from dueDate to asOf. It is zero on
+// the due day and negative before it.
+func DaysPastDue(dueDate, asOf time.Time) int {
+	return int(RateDay(asOf).Sub(RateDay(dueDate)).Hours() / 24)
+}
@@ -0,0 +1,71 @@
+package domain_test
+
+import (
+	"carigo/internal/domain"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseAgingBuckets(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    domain.AgingBuckets
+		wantErr bool
+	}{
+		{"", domain.DefaultAgingBuckets, false},
+		{"30,60,90", domain.AgingBuckets{30, 60, 90}, false},
+		{" 7, 14 ,", domain.AgingBuckets{7, 14}, false},
+		{"60,30", nil, true},
+		{"30,30", nil, true},
+		{"0,30", nil, true},
+		{"30,x", nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := domain.ParseAgingBuckets(tt.in)
+		if tt.wantErr {
+			if err != domain.ErrInvalidAgingBuckets {
+				t.Errorf("ParseAgingBuckets(%q): expected ErrInvalidAgingBuckets, got %v", tt.in, err)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseAgingBuckets(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestAgingBuckets_IndexAndLabels(t *testing.T) {
+	b := domain.DefaultAgingBuckets
+
+	for days, want := range map[int]int{-5: 0, 0: 0, 1: 1, 30: 1, 31: 2, 60: 2, 90: 3, 91: 4, 400: 4} {
+		if got := b.Index(days); got != want {
+			t.Errorf("Index(%d) = %d, want %d", days, got, want)
+		}
+	}
+
+	want := []string{"current", "1-30", "31-60", "61-90", "90+"}
+	if got := b.Labels(); !reflect.DeepEqual(got, want) || b.Len() != len(want) {
+		t.Errorf("Labels() = %v (Len %d), want %v", got, b.Len(), want)
+	}
+}
+
+func TestDaysPastDue(t *testing.T) {
+	due := time.Date(2026, 3, 1, 18, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		asOf time.Time
+		want int
+	}{
+		{time.Date(2026, 3, 1, 23, 59, 0, 0, time.UTC), 0},
+		{time.Date(2026, 3, 2, 0, 1, 0, 0, time.UTC), 1},
+		{time.Date(2026, 2, 27, 12, 0, 0, 0, time.UTC), -2},
+		{time.Date(2026, 5, 30, 9, 0, 0, 0, time.UTC), 90},
+	}
+	for _, tt := range tests {
+		if got := domain.DaysPastDue(due, tt.asOf); got != tt.want {
+			t.Errorf("DaysPastDue(%s) = %d, want %d", tt.asOf, got, tt.want)
+		}
+	}
+}
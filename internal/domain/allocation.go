@@ -6,20 +6,65 @@ import (
 
 type AllocationID string
 
+type AllocationType string
+
+const (
+	AllocationTypeApplication AllocationType = "APPLICATION"
+	AllocationTypeReversal    AllocationType = "REVERSAL"
+	// AllocationTypeDiscount settles part of an invoice with an early-payment
+	// discount the payment earned. It draws nothing from the payment: Amount
+	// is zero and InvoiceAmount is the discount.
+	AllocationTypeDiscount AllocationType = "DISCOUNT"
+)
+
+// AllocationSource is a document whose amount can be applied to invoices.
+// It is implemented by Payment and CreditNote.
+type AllocationSource interface {
+	UseFunds(amount Money) error
+	RestoreFunds(amount Money) error
+	owner() CustomerID
+	available() Money
+	attach(a *Allocation)
+	owns(a *Allocation) bool
+}
+
+// Allocation applies part of a source document to an invoice. Exactly one of
+// PaymentID and CreditNoteID is set. Amount is taken from the source in its
+// currency; InvoiceAmount is what it settles on the invoice. The two differ
+// only for cross-currency allocations, which also record the ExchangeRate used.
 type Allocation struct {
-	ID        AllocationID
-	PaymentID PaymentID
-	InvoiceID InvoiceID
-	Amount    Money
-	CreatedAt time.Time
+	ID            AllocationID
+	PaymentID     PaymentID
+	CreditNoteID  CreditNoteID
+	InvoiceID     InvoiceID
+	Amount        Money
+	InvoiceAmount Money
+	ExchangeRate  *ExchangeRate
+	Type          AllocationType
+	// ReversalOf points from a reversal entry to the allocation it undoes.
+	ReversalOf AllocationID
+	// ReversedBy points from an allocation to the entry that undid it.
+	ReversedBy AllocationID
+	// ExchangeDifferenceInvoiceID is the kur farkı invoice that billed the
+	// exchange gain realized by this allocation, if any.
+	ExchangeDifferenceInvoiceID InvoiceID
+	Reason                      string
+	CreatedAt                   time.Time
 }
 
-func NewAllocation(id AllocationID, payment *Payment, invoice *Invoice, amount Money) (*Allocation, error) {
-	if payment.AvailableAmount.currency != amount.currency || invoice.TotalAmount.currency != amount.currency {
+func NewAllocation(id AllocationID, source AllocationSource, invoice *Invoice, amount Money) (*Allocation, error) {
+	if source.owner() != invoice.CustomerID {
+		return nil, ErrCustomerMismatch
+	}
+	if source.available().currency != amount.currency || invoice.TotalAmount.currency != amount.currency {
 		return nil, ErrCurrencyMismatch
 	}
 
-	if err := payment.UseFunds(amount); err != nil {
+	if err := invoice.canAllocate(amount); err != nil {
+		return nil, err
+	}
+
+	if err := source.UseFunds(amount); err != nil {
 		return nil, err
 	}
 
@@ -27,11 +72,153 @@ func NewAllocation(id AllocationID, payment *Payment, invoice *Invoice, amount M
 		return nil, err
 	}
 
+	a := &Allocation{
+		ID:            id,
+		InvoiceID:     invoice.ID,
+		Amount:        amount,
+		InvoiceAmount: amount,
+		Type:          AllocationTypeApplication,
+		CreatedAt:     time.Now(),
+	}
+	source.attach(a)
+	return a, nil
+}
+
+// NewAllocationAtRate applies a source to an invoice in another currency.
+// amount, in the source currency, is the most that may be taken: if its
+// converted value exceeds the invoice's remaining debt, only the equivalent of
+// that debt is taken from the source.
+func NewAllocationAtRate(id AllocationID, source AllocationSource, invoice *Invoice, amount Money, rate *ExchangeRate) (*Allocation, error) {
+	if source.owner() != invoice.CustomerID {
+		return nil, ErrCustomerMismatch
+	}
+	if source.available().currency != amount.currency || !rate.Converts(amount.currency, invoice.TotalAmount.currency) {
+		return nil, ErrCurrencyMismatch
+	}
+
+	sourceAmount, invoiceAmount, err := settleAtRate(amount, invoice.RemainingAmount(), rate)
+	if err != nil {
+		return nil, err
+	}
+	if invoiceAmount.IsZero() {
+		return nil, ErrNegativeAmount
+	}
+
+	if err := invoice.canAllocate(invoiceAmount); err != nil {
+		return nil, err
+	}
+	if err := source.UseFunds(sourceAmount); err != nil {
+		return nil, err
+	}
+	if err := invoice.AllocatePayment(invoiceAmount); err != nil {
+		return nil, err
+	}
+
+	a := &Allocation{
+		ID:            id,
+		InvoiceID:     invoice.ID,
+		Amount:        sourceAmount,
+		InvoiceAmount: invoiceAmount,
+		ExchangeRate:  rate,
+		Type:          AllocationTypeApplication,
+		CreatedAt:     time.Now(),
+	}
+	source.attach(a)
+	return a, nil
+}
+
+// NewDiscountAllocation posts the early-payment discount payment earned on
+// invoice as a credit against it, alongside the payment's own allocation.
+func NewDiscountAllocation(id AllocationID, payment *Payment, invoice *Invoice, discount Money) (*Allocation, error) {
+	if payment.CustomerID != invoice.CustomerID {
+		return nil, ErrCustomerMismatch
+	}
+	if invoice.TotalAmount.currency != discount.currency {
+		return nil, ErrCurrencyMismatch
+	}
+	if discount.IsZero() || discount.amount < 0 {
+		return nil, ErrNegativeAmount
+	}
+
+	if err := invoice.AllocatePayment(discount); err != nil {
+		return nil, err
+	}
+
+	a := &Allocation{
+		ID:            id,
+		InvoiceID:     invoice.ID,
+		Amount:        Money{currency: payment.Amount.currency},
+		InvoiceAmount: discount,
+		Type:          AllocationTypeDiscount,
+		Reason:        "Erken ödeme iskontosu",
+		CreatedAt:     time.Now(),
+	}
+	payment.attach(a)
+	return a, nil
+}
+
+// ReleaseExchangeDifference unlinks the allocation from the kur farkı invoice
+// that billed its gain once that invoice is void, so the gain can be billed
+// again and the allocation reversed.
+func (a *Allocation) ReleaseExchangeDifference(invoice *Invoice) error {
+	if invoice.ID != a.ExchangeDifferenceInvoiceID || invoice.Status != InvoiceStatusVoid {
+		return ErrInvalidInvoiceState
+	}
+	a.ExchangeDifferenceInvoiceID = ""
+	return nil
+}
+
+// IsActive reports whether the allocation currently settles part of an invoice.
+func (a *Allocation) IsActive() bool {
+	return (a.Type == AllocationTypeApplication || a.Type == AllocationTypeDiscount) && a.ReversedBy == ""
+}
+
+// IsDiscount reports whether the allocation is an early-payment discount.
+func (a *Allocation) IsDiscount() bool {
+	return a.Type == AllocationTypeDiscount
+}
+
+// Reverse undoes the allocation: the amount goes back to the source document
+// and the invoice debt reopens. The original entry is kept and linked to the
+// returned reversal entry, so the history stays intact. An allocation whose
+// exchange gain was billed stays until the kur farkı invoice is void, or that
+// invoice would bill a gain that was never realized.
+func (a *Allocation) Reverse(id AllocationID, source AllocationSource, invoice *Invoice, reason string, at time.Time) (*Allocation, error) {
+	if a.Type != AllocationTypeApplication && a.Type != AllocationTypeDiscount {
+		return nil, ErrInvalidAllocationReversal
+	}
+	if a.ReversedBy != "" {
+		return nil, ErrAllocationAlreadyReversed
+	}
+	if a.ExchangeDifferenceInvoiceID != "" {
+		return nil, ErrExchangeDifferenceInvoiced
+	}
+	if !source.owns(a) || invoice.ID != a.InvoiceID {
+		return nil, ErrInvalidAllocationReversal
+	}
+
+	if err := invoice.canRelease(a.InvoiceAmount); err != nil {
+		return nil, err
+	}
+	if err := source.RestoreFunds(a.Amount); err != nil {
+		return nil, err
+	}
+	if err := invoice.ReleasePayment(a.InvoiceAmount); err != nil {
+		return nil, err
+	}
+
+	a.ReversedBy = id
 	return &Allocation{
-		ID:        id,
-		PaymentID: payment.ID,
-		InvoiceID: invoice.ID,
-		Amount:    amount,
-		CreatedAt: time.Now(),
+		ID:            id,
+		PaymentID:     a.PaymentID,
+		CreditNoteID:  a.CreditNoteID,
+		InvoiceID:     a.InvoiceID,
+		Amount:        a.Amount,
+		InvoiceAmount: a.InvoiceAmount,
+		ExchangeRate:  a.ExchangeRate,
+		Type:          AllocationTypeReversal,
+		ReversalOf:    a.ID,
+		Reason:        reason,
+		CreatedAt:     at,
 	}, nil
 }
@@ -0,0 +1,208 @@
+package domain
+
+import (
+	"time"
+)
+
+type AllocationPlanID string
+
+// PlannedAllocation is a single proposed line of an AllocationPlan. Amount is
+// in the payment currency, InvoiceAmount, Discount and RemainingAfter in the
+// invoice currency. Rate is set only for cross-currency lines. Discount is
+// the early-payment discount that settles the rest of the invoice.
+type PlannedAllocation struct {
+	InvoiceID      InvoiceID
+	Amount         Money
+	InvoiceAmount  Money
+	Discount       Money
+	RemainingAfter Money
+	Rate           *ExchangeRate
+}
+
+// InvoiceSnapshot captures the state of an open invoice at planning time,
+// so a plan can detect that the ledger moved underneath it.
+type InvoiceSnapshot struct {
+	InvoiceID InvoiceID
+	Remaining Money
+}
+
+// AllocationPlan is a read-only proposal of how a payment would be spread over
+// a customer's open invoices. Nothing is booked until the plan is confirmed.
+type AllocationPlan struct {
+	ID          AllocationPlanID
+	CustomerID  CustomerID
+	Amount      Money
+	Date        time.Time
+	Strategy    AllocationStrategyName
+	Lines       []PlannedAllocation
+	Unallocated Money
+	Snapshot    []InvoiceSnapshot
+	CreatedAt   time.Time
+}
+
+// NewAllocationPlan walks the invoices in the order chosen by the strategy and
+// fills each one until the amount is exhausted. Strategies that order
+// instalments fill them one at a time, so an invoice may be reached more than
+// once; it still gets a single line. Invoices in another currency are settled
+// at the matching rate from rates, or skipped if there is none.
+func NewAllocationPlan(id AllocationPlanID, customerID CustomerID, amount Money, date time.Time, invoices []*Invoice, strategy AllocationStrategy, rates []*ExchangeRate) (*AllocationPlan, error) {
+	return newAllocationPlan(id, customerID, amount, date, invoices, strategy, rates, false)
+}
+
+// NewPaymentAllocationPlan is NewAllocationPlan for a payment received on
+// date. An invoice in the payment currency whose discount window is still
+// open that day is settled in full, less its early-payment discount, as soon
+// as the payment covers the discounted amount.
+func NewPaymentAllocationPlan(id AllocationPlanID, customerID CustomerID, amount Money, date time.Time, invoices []*Invoice, strategy AllocationStrategy, rates []*ExchangeRate) (*AllocationPlan, error) {
+	return newAllocationPlan(id, customerID, amount, date, invoices, strategy, rates, true)
+}
+
+func newAllocationPlan(id AllocationPlanID, customerID CustomerID, amount Money, date time.Time, invoices []*Invoice, strategy AllocationStrategy, rates []*ExchangeRate, discounts bool) (*AllocationPlan, error) {
+	if amount.IsZero() {
+		return nil, ErrNegativeAmount
+	}
+
+	plan := &AllocationPlan{
+		ID:         id,
+		CustomerID: customerID,
+		Amount:     amount,
+		Date:       date,
+		Strategy:   strategy.Name(),
+		Snapshot:   snapshotInvoices(invoices),
+		CreatedAt:  time.Now(),
+	}
+
+	lineIndex := make(map[InvoiceID]int)
+	available := amount
+	for _, open := range settlementOrder(strategy, invoices, amount) {
+		if available.IsZero() {
+			break
+		}
+		inv := open.invoice
+		if open.remaining.IsZero() {
+			continue
+		}
+
+		// Plan against the line so far: budget is what it could use, target
+		// what it would cover in the invoice currency once this step is done.
+		budget, target := available, open.remaining
+		idx, seen := lineIndex[inv.ID]
+		if seen {
+			if !plan.Lines[idx].Discount.IsZero() {
+				continue
+			}
+			budget, _ = budget.Add(plan.Lines[idx].Amount)
+			target, _ = target.Add(plan.Lines[idx].InvoiceAmount)
+		}
+
+		var rate *ExchangeRate
+		discount := Money{currency: target.Currency()}
+		lineAmount, invoiceAmount := target, target
+		if d := inv.EarlyPaymentDiscount(date); discounts && !d.IsZero() && d.Currency() == available.Currency() {
+			// The discount is only earned by settling everything still open,
+			// including instalments the strategy has not reached yet.
+			due, _ := inv.RemainingAmount().Subtract(d)
+			if isDueLarger, _ := due.GreaterThan(budget); !isDueLarger {
+				lineAmount, invoiceAmount, discount = due, due, d
+			}
+		}
+
+		switch {
+		case !discount.IsZero():
+		case target.Currency() != available.Currency():
+			rate = findRate(rates, available.Currency(), target.Currency())
+			if rate == nil {
+				continue
+			}
+			var err error
+			lineAmount, invoiceAmount, err = settleAtRate(budget, target, rate)
+			if err != nil || invoiceAmount.IsZero() {
+				continue
+			}
+		default:
+			if isDebtLarger, _ := target.GreaterThan(budget); isDebtLarger {
+				lineAmount, invoiceAmount = budget, budget
+			}
+		}
+
+		available, _ = budget.Subtract(lineAmount)
+		remainingAfter, _ := inv.RemainingAmount().Subtract(invoiceAmount)
+		remainingAfter, _ = remainingAfter.Subtract(discount)
+		line := PlannedAllocation{
+			InvoiceID:      inv.ID,
+			Amount:         lineAmount,
+			InvoiceAmount:  invoiceAmount,
+			Discount:       discount,
+			RemainingAfter: remainingAfter,
+			Rate:           rate,
+		}
+		if seen {
+			plan.Lines[idx] = line
+			continue
+		}
+		lineIndex[inv.ID] = len(plan.Lines)
+		plan.Lines = append(plan.Lines, line)
+	}
+	plan.Unallocated = available
+
+	return plan, nil
+}
+
+// settlementOrder lists what the strategy settles first. Strategies working
+// on whole invoices yield one entry per invoice for its full open balance.
+func settlementOrder(strategy AllocationStrategy, invoices []*Invoice, amount Money) []openInstalment {
+	if s, ok := strategy.(instalmentStrategy); ok {
+		return s.orderInstalments(invoices)
+	}
+	ordered := strategy.Order(invoices, amount)
+	open := make([]openInstalment, 0, len(ordered))
+	for _, inv := range ordered {
+		open = append(open, openInstalment{invoice: inv, dueDate: inv.NextDueDate(), remaining: inv.RemainingAmount()})
+	}
+	return open
+}
+
+// AllocatedAmount is the part of the payment the plan assigns to invoices.
+func (p *AllocationPlan) AllocatedAmount() Money {
+	allocated, _ := p.Amount.Subtract(p.Unallocated)
+	return allocated
+}
+
+// IsStale reports whether the given open invoices differ from the ones the
+// plan was built on.
+func (p *AllocationPlan) IsStale(openInvoices []*Invoice) bool {
+	if len(openInvoices) != len(p.Snapshot) {
+		return true
+	}
+	planned := make(map[InvoiceID]Money, len(p.Snapshot))
+	for _, s := range p.Snapshot {
+		planned[s.InvoiceID] = s.Remaining
+	}
+	for _, inv := range openInvoices {
+		remaining, ok := planned[inv.ID]
+		if !ok || !remaining.Equals(inv.RemainingAmount()) {
+			return true
+		}
+	}
+	return false
+}
+
+func findRate(rates []*ExchangeRate, a, b string) *ExchangeRate {
+	for _, r := range rates {
+		if r.Converts(a, b) {
+			return r
+		}
+	}
+	return nil
+}
+
+func snapshotInvoices(invoices []*Invoice) []InvoiceSnapshot {
+	snapshot := make([]InvoiceSnapshot, 0, len(invoices))
+	for _, inv := range invoices {
+		snapshot = append(snapshot, InvoiceSnapshot{
+			InvoiceID: inv.ID,
+			Remaining: inv.RemainingAmount(),
+		})
+	}
+	return snapshot
+}
@@ -0,0 +1,94 @@
+package domain_test
+
+import (
+	"carigo/internal/domain"
+	"testing"
+	"time"
+)
+
+func newTestInvoice(t *testing.T, id string, amount int64, currency string) *domain.Invoice {
+	t.Helper()
+	total, _ := domain.NewMoney(amount, currency)
+	inv, err := domain.NewInvoice(domain.InvoiceID(id), "CUST-001", total, time.Now(), time.Now().Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("failed to create invoice: %v", err)
+	}
+	return inv
+}
+
+func fifo(t *testing.T) domain.AllocationStrategy {
+	t.Helper()
+	s, err := domain.NewAllocationStrategy(domain.AllocationStrategyFIFO)
+	if err != nil {
+		t.Fatalf("failed to create strategy: %v", err)
+	}
+	return s
+}
+
+func TestNewAllocationPlan_FillsInvoicesInOrder(t *testing.T) {
+	invoices := []*domain.Invoice{
+		newTestInvoice(t, "INV-001", 1000, "TRY"),
+		newTestInvoice(t, "INV-002", 500, "USD"),
+		newTestInvoice(t, "INV-003", 2000, "TRY"),
+	}
+	amount, _ := domain.NewMoney(1500, "TRY")
+
+	plan, err := domain.NewAllocationPlan("PLAN-1", "CUST-001", amount, time.Now(), invoices, fifo(t), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(plan.Lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(plan.Lines))
+	}
+	if plan.Lines[0].InvoiceID != "INV-001" || plan.Lines[0].Amount.Amount() != 1000 || !plan.Lines[0].RemainingAfter.IsZero() {
+		t.Errorf("unexpected first line: %+v", plan.Lines[0])
+	}
+	if plan.Lines[1].InvoiceID != "INV-003" || plan.Lines[1].Amount.Amount() != 500 || plan.Lines[1].RemainingAfter.Amount() != 1500 {
+		t.Errorf("unexpected second line: %+v", plan.Lines[1])
+	}
+	if !plan.Unallocated.IsZero() {
+		t.Errorf("expected nothing unallocated, got %d", plan.Unallocated.Amount())
+	}
+
+	if invoices[0].Status != domain.InvoiceStatusOpen || !invoices[0].PaidAmount.IsZero() {
+		t.Errorf("planning must not touch invoices, got status %s", invoices[0].Status)
+	}
+}
+
+func TestNewAllocationPlan_Overpayment(t *testing.T) {
+	invoices := []*domain.Invoice{newTestInvoice(t, "INV-001", 1000, "TRY")}
+	amount, _ := domain.NewMoney(1200, "TRY")
+
+	plan, _ := domain.NewAllocationPlan("PLAN-1", "CUST-001", amount, time.Now(), invoices, fifo(t), nil)
+
+	if plan.AllocatedAmount().Amount() != 1000 {
+		t.Errorf("expected 1000 allocated, got %d", plan.AllocatedAmount().Amount())
+	}
+	if plan.Unallocated.Amount() != 200 {
+		t.Errorf("expected 200 unallocated, got %d", plan.Unallocated.Amount())
+	}
+}
+
+func TestAllocationPlan_IsStale(t *testing.T) {
+	inv1 := newTestInvoice(t, "INV-001", 1000, "TRY")
+	inv2 := newTestInvoice(t, "INV-002", 1000, "TRY")
+	amount, _ := domain.NewMoney(500, "TRY")
+
+	plan, _ := domain.NewAllocationPlan("PLAN-1", "CUST-001", amount, time.Now(), []*domain.Invoice{inv1, inv2}, fifo(t), nil)
+
+	if plan.IsStale([]*domain.Invoice{inv2, inv1}) {
+		t.Errorf("plan should not be stale when only the order differs")
+	}
+
+	partial, _ := domain.NewMoney(100, "TRY")
+	_ = inv1.AllocatePayment(partial)
+	if !plan.IsStale([]*domain.Invoice{inv1, inv2}) {
+		t.Errorf("plan should be stale after an invoice balance changed")
+	}
+
+	inv3 := newTestInvoice(t, "INV-003", 1000, "TRY")
+	if !plan.IsStale([]*domain.Invoice{inv2, inv3}) {
+		t.Errorf("plan should be stale when the set of open invoices changed")
+	}
+}
@@ -0,0 +1,171 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+type AllocationStrategyName string
+
+const (
+	AllocationStrategyFIFO              AllocationStrategyName = "FIFO"
+	AllocationStrategyLIFO              AllocationStrategyName = "LIFO"
+	AllocationStrategyOldestIssue       AllocationStrategyName = "OLDEST_ISSUE"
+	AllocationStrategySmallestRemaining AllocationStrategyName = "SMALLEST_REMAINING"
+	AllocationStrategyExactAmount       AllocationStrategyName = "EXACT_AMOUNT"
+)
+
+// DefaultAllocationStrategy is used for customers without an explicit choice.
+const DefaultAllocationStrategy = AllocationStrategyFIFO
+
+// AllocationStrategy decides in which order a payment settles open invoices.
+// Order must not modify the given slice.
+type AllocationStrategy interface {
+	Name() AllocationStrategyName
+	Order(invoices []*Invoice, amount Money) []*Invoice
+}
+
+// NewAllocationStrategy returns the built-in strategy registered under name.
+// An empty name selects DefaultAllocationStrategy.
+func NewAllocationStrategy(name AllocationStrategyName) (AllocationStrategy, error) {
+	switch name {
+	case "", AllocationStrategyFIFO:
+		return fifoStrategy{}, nil
+	case AllocationStrategyLIFO:
+		return lifoStrategy{}, nil
+	case AllocationStrategyOldestIssue:
+		return oldestIssueStrategy{}, nil
+	case AllocationStrategySmallestRemaining:
+		return smallestRemainingStrategy{}, nil
+	case AllocationStrategyExactAmount:
+		return exactAmountStrategy{}, nil
+	}
+	return nil, ErrUnknownAllocationStrategy
+}
+
+// AllocationStrategyNames lists the built-in strategies.
+func AllocationStrategyNames() []AllocationStrategyName {
+	return []AllocationStrategyName{
+		AllocationStrategyFIFO,
+		AllocationStrategyLIFO,
+		AllocationStrategyOldestIssue,
+		AllocationStrategySmallestRemaining,
+		AllocationStrategyExactAmount,
+	}
+}
+
+// instalmentStrategy is implemented by strategies that settle individual
+// instalments rather than whole invoices, so a payment can cover the first
+// instalment of one invoice before a later instalment of another.
+type instalmentStrategy interface {
+	orderInstalments(invoices []*Invoice) []openInstalment
+}
+
+// openInstalment is the unpaid part of one instalment of an invoice.
+type openInstalment struct {
+	invoice   *Invoice
+	dueDate   time.Time
+	remaining Money
+}
+
+// fifoStrategy settles the instalment with the oldest due date first. An
+// invoice without a schedule is a single instalment due on its DueDate.
+type fifoStrategy struct{}
+
+func (fifoStrategy) Name() AllocationStrategyName { return AllocationStrategyFIFO }
+
+func (fifoStrategy) Order(invoices []*Invoice, _ Money) []*Invoice {
+	return sortedInvoices(invoices, func(a, b *Invoice) bool {
+		return a.NextDueDate().Before(b.NextDueDate())
+	})
+}
+
+func (s fifoStrategy) orderInstalments(invoices []*Invoice) []openInstalment {
+	var open []openInstalment
+	for _, inv := range s.Order(invoices, Money{}) {
+		for _, st := range inv.InstalmentStatuses() {
+			if !st.Remaining.IsZero() {
+				open = append(open, openInstalment{invoice: inv, dueDate: st.DueDate, remaining: st.Remaining})
+			}
+		}
+	}
+	sort.SliceStable(open, func(i, j int) bool {
+		return open[i].dueDate.Before(open[j].dueDate)
+	})
+	return open
+}
+
+// lifoStrategy settles the most recently issued invoice first.
+type lifoStrategy struct{}
+
+func (lifoStrategy) Name() AllocationStrategyName { return AllocationStrategyLIFO }
+
+func (lifoStrategy) Order(invoices []*Invoice, _ Money) []*Invoice {
+	return sortedInvoices(invoices, func(a, b *Invoice) bool {
+		return a.IssueDate.After(b.IssueDate)
+	})
+}
+
+// oldestIssueStrategy settles the earliest issued invoice first.
+type oldestIssueStrategy struct{}
+
+func (oldestIssueStrategy) Name() AllocationStrategyName { return AllocationStrategyOldestIssue }
+
+func (oldestIssueStrategy) Order(invoices []*Invoice, _ Money) []*Invoice {
+	return sortedInvoices(invoices, func(a, b *Invoice) bool {
+		return a.IssueDate.Before(b.IssueDate)
+	})
+}
+
+// smallestRemainingStrategy closes as many invoices as possible by settling
+// the smallest open balance first.
+type smallestRemainingStrategy struct{}
+
+func (smallestRemainingStrategy) Name() AllocationStrategyName {
+	return AllocationStrategySmallestRemaining
+}
+
+func (smallestRemainingStrategy) Order(invoices []*Invoice, _ Money) []*Invoice {
+	return sortedInvoices(invoices, func(a, b *Invoice) bool {
+		return a.RemainingAmount().Amount() < b.RemainingAmount().Amount()
+	})
+}
+
+// exactAmountStrategy settles an invoice whose open balance equals the
+// payment first, then falls back to FIFO by due date.
+type exactAmountStrategy struct{}
+
+func (exactAmountStrategy) Name() AllocationStrategyName { return AllocationStrategyExactAmount }
+
+func (exactAmountStrategy) Order(invoices []*Invoice, amount Money) []*Invoice {
+	ordered := fifoStrategy{}.Order(invoices, amount)
+	for i, inv := range ordered {
+		if inv.RemainingAmount().Equals(amount) {
+			copy(ordered[1:i+1], ordered[:i])
+			ordered[0] = inv
+			break
+		}
+	}
+	return ordered
+}
+
+// sortedInvoices returns a stably sorted copy, falling back to due date and ID
+// so equal keys still produce a deterministic order.
+func sortedInvoices(invoices []*Invoice, less func(a, b *Invoice) bool) []*Invoice {
+	ordered := make([]*Invoice, len(invoices))
+	copy(ordered, invoices)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		a, b := ordered[i], ordered[j]
+		if less(a, b) {
+			return true
+		}
+		if less(b, a) {
+			return false
+		}
+		if !a.DueDate.Equal(b.DueDate) {
+			return a.DueDate.Before(b.DueDate)
+		}
+		return a.ID < b.ID
+	})
+	return ordered
+}
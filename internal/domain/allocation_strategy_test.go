@@ -0,0 +1,63 @@
+package domain_test
+
+import (
+	"carigo/internal/domain"
+	"testing"
+	"time"
+)
+
+func TestAllocationStrategies_Order(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	mk := func(id string, amount int64, issueDay, dueDay int) *domain.Invoice {
+		total, _ := domain.NewMoney(amount, "TRY")
+		inv, _ := domain.NewInvoice(domain.InvoiceID(id), "CUST-001", total, base.AddDate(0, 0, issueDay), base.AddDate(0, 0, dueDay))
+		return inv
+	}
+	invoices := []*domain.Invoice{
+		mk("INV-A", 3000, 0, 60),
+		mk("INV-B", 1000, 10, 20),
+		mk("INV-C", 2000, 20, 30),
+	}
+	payment, _ := domain.NewMoney(2000, "TRY")
+
+	tests := []struct {
+		name     domain.AllocationStrategyName
+		expected []domain.InvoiceID
+	}{
+		{domain.AllocationStrategyFIFO, []domain.InvoiceID{"INV-B", "INV-C", "INV-A"}},
+		{domain.AllocationStrategyLIFO, []domain.InvoiceID{"INV-C", "INV-B", "INV-A"}},
+		{domain.AllocationStrategyOldestIssue, []domain.InvoiceID{"INV-A", "INV-B", "INV-C"}},
+		{domain.AllocationStrategySmallestRemaining, []domain.InvoiceID{"INV-B", "INV-C", "INV-A"}},
+		{domain.AllocationStrategyExactAmount, []domain.InvoiceID{"INV-C", "INV-B", "INV-A"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.name), func(t *testing.T) {
+			s, err := domain.NewAllocationStrategy(tt.name)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			ordered := s.Order(invoices, payment)
+			for i, id := range tt.expected {
+				if ordered[i].ID != id {
+					t.Errorf("position %d: expected %s, got %s", i, id, ordered[i].ID)
+				}
+			}
+		})
+	}
+
+	if invoices[0].ID != "INV-A" {
+		t.Errorf("Order must not reorder the input slice")
+	}
+}
+
+func TestNewAllocationStrategy_Unknown(t *testing.T) {
+	if _, err := domain.NewAllocationStrategy("RANDOM"); err != domain.ErrUnknownAllocationStrategy {
+		t.Errorf("expected ErrUnknownAllocationStrategy, got %v", err)
+	}
+
+	s, err := domain.NewAllocationStrategy("")
+	if err != nil || s.Name() != domain.DefaultAllocationStrategy {
+		t.Errorf("expected default strategy for empty name, got %v (%v)", s, err)
+	}
+}
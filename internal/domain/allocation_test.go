@@ -0,0 +1,84 @@
+package domain_test
+
+import (
+	"carigo/internal/domain"
+	"testing"
+	"time"
+)
+
+func TestNewAllocation(t *testing.T) {
+	inv := newTestInvoice(t, "INV-001", 1000, "TRY")
+	amount, _ := domain.NewMoney(1500, "TRY")
+	payment := domain.NewPayment("PAY-001", "CUST-001", amount, time.Now())
+
+	part, _ := domain.NewMoney(600, "TRY")
+	alloc, err := domain.NewAllocation("AL-1", payment, inv, part)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if alloc.Amount.Amount() != 600 {
+		t.Errorf("expected 600, got %d", alloc.Amount.Amount())
+	}
+	if payment.AvailableAmount.Amount() != 900 {
+		t.Errorf("expected 900 available, got %d", payment.AvailableAmount.Amount())
+	}
+	if inv.Status != domain.InvoiceStatusPartial {
+		t.Errorf("expected status PARTIAL, got %s", inv.Status)
+	}
+
+	tooMuch, _ := domain.NewMoney(500, "TRY")
+	if _, err := domain.NewAllocation("AL-2", payment, inv, tooMuch); err != domain.ErrOverPaymentNotAllowed {
+		t.Errorf("expected ErrOverPaymentNotAllowed, got %v", err)
+	}
+	if payment.AvailableAmount.Amount() != 900 {
+		t.Errorf("payment must be untouched after a rejected allocation, got %d", payment.AvailableAmount.Amount())
+	}
+}
+
+func TestNewAllocation_CustomerMismatch(t *testing.T) {
+	inv := newTestInvoice(t, "INV-001", 1000, "TRY")
+	amount, _ := domain.NewMoney(1000, "TRY")
+	payment := domain.NewPayment("PAY-001", "CUST-002", amount, time.Now())
+
+	if _, err := domain.NewAllocation("AL-1", payment, inv, amount); err != domain.ErrCustomerMismatch {
+		t.Errorf("expected ErrCustomerMismatch, got %v", err)
+	}
+	if !payment.AvailableAmount.Equals(amount) {
+		t.Errorf("payment must be untouched after a rejected allocation")
+	}
+}
+
+func TestAllocation_Reverse(t *testing.T) {
+	inv := newTestInvoice(t, "INV-001", 1000, "TRY")
+	amount, _ := domain.NewMoney(1000, "TRY")
+	payment := domain.NewPayment("PAY-001", "CUST-001", amount, time.Now())
+
+	alloc, _ := domain.NewAllocation("AL-1", payment, inv, amount)
+	if inv.Status != domain.InvoiceStatusPaid {
+		t.Fatalf("expected status PAID, got %s", inv.Status)
+	}
+
+	reversal, err := alloc.Reverse("REV-AL-1", payment, inv, "wrong invoice", time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reversal.Type != domain.AllocationTypeReversal || reversal.ReversalOf != "AL-1" {
+		t.Errorf("unexpected reversal entry: %+v", reversal)
+	}
+	if alloc.ReversedBy != "REV-AL-1" || alloc.IsActive() {
+		t.Errorf("original allocation should be marked as reversed")
+	}
+	if inv.Status != domain.InvoiceStatusOpen || !inv.PaidAmount.IsZero() {
+		t.Errorf("expected invoice back to OPEN, got %s (%d paid)", inv.Status, inv.PaidAmount.Amount())
+	}
+	if !payment.AvailableAmount.Equals(amount) {
+		t.Errorf("expected funds restored, got %d", payment.AvailableAmount.Amount())
+	}
+
+	if _, err := alloc.Reverse("REV-AL-1b", payment, inv, "again", time.Now()); err != domain.ErrAllocationAlreadyReversed {
+		t.Errorf("expected ErrAllocationAlreadyReversed, got %v", err)
+	}
+	if _, err := reversal.Reverse("REV-REV", payment, inv, "again", time.Now()); err != domain.ErrInvalidAllocationReversal {
+		t.Errorf("expected ErrInvalidAllocationReversal, got %v", err)
+	}
+}
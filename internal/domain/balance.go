@@ -0,0 +1,62 @@
+package domain
+
+// Balance is a signed running total of a customer account in one currency.
+// A positive amount means the customer owes us, a negative one that we owe
+// the customer. Unlike Money it may go below zero.
+type Balance struct {
+	amount   int64
+	currency string
+}
+
+func NewBalance(currency string) (Balance, error) {
+	if currency == "" {
+		return Balance{}, ErrInvalidCurrency
+	}
+	return Balance{currency: currency}, nil
+}
+
+func (b Balance) Amount() int64 {
+	return b.amount
+}
+
+func (b Balance) Currency() string {
+	return b.currency
+}
+
+// Debit increases what the customer owes.
+func (b Balance) Debit(m Money) (Balance, error) {
+	if b.currency != m.currency {
+		return Balance{}, ErrCurrencyMismatch
+	}
+	return Balance{amount: b.amount + m.amount, currency: b.currency}, nil
+}
+
+// Credit decreases what the customer owes.
+func (b Balance) Credit(m Money) (Balance, error) {
+	if b.currency != m.currency {
+		return Balance{}, ErrCurrencyMismatch
+	}
+	return Balance{amount: b.amount - m.amount, currency: b.currency}, nil
+}
+
+// IsDebit reports whether the customer owes us money.
+func (b Balance) IsDebit() bool {
+	return b.amount > 0
+}
+
+// Abs returns the size of the balance regardless of its side.
+func (b Balance) Abs() Money {
+	if b.amount < 0 {
+		return Money{amount: -b.amount, currency: b.currency}
+	}
+	return Money{amount: b.amount, currency: b.currency}
+}
+
+// NewBalanceOf restores a balance of amount minor units, such as one read
+// back from storage or stated by a customer.
+func NewBalanceOf(amount int64, currency string) (Balance, error) {
+	if currency == "" {
+		return Balance{}, ErrInvalidCurrency
+	}
+	return Balance{amount: amount, currency: currency}, nil
+}
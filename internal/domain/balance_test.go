@@ -0,0 +1,43 @@
+package domain_test
+
+import (
+	"carigo/internal/domain"
+	"testing"
+)
+
+func TestBalance_DebitAndCredit(t *testing.T) {
+	b, err := domain.NewBalance("USD")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	debt, _ := domain.NewMoney(1000, "USD")
+	b, _ = b.Debit(debt)
+	if b.Amount() != 1000 || !b.IsDebit() {
+		t.Errorf("expected debit balance of 1000, got %d", b.Amount())
+	}
+
+	payment, _ := domain.NewMoney(1500, "USD")
+	b, _ = b.Credit(payment)
+	if b.Amount() != -500 || b.IsDebit() {
+		t.Errorf("expected credit balance of -500, got %d", b.Amount())
+	}
+	if b.Abs().Amount() != 500 || b.Abs().Currency() != "USD" {
+		t.Errorf("expected absolute value 500 USD, got %d %s", b.Abs().Amount(), b.Abs().Currency())
+	}
+}
+
+func TestBalance_CurrencyMismatch(t *testing.T) {
+	b, _ := domain.NewBalance("TRY")
+	eur, _ := domain.NewMoney(100, "EUR")
+
+	if _, err := b.Debit(eur); err != domain.ErrCurrencyMismatch {
+		t.Errorf("expected ErrCurrencyMismatch, got %v", err)
+	}
+	if _, err := b.Credit(eur); err != domain.ErrCurrencyMismatch {
+		t.Errorf("expected ErrCurrencyMismatch, got %v", err)
+	}
+	if _, err := domain.NewBalance(""); err != domain.ErrInvalidCurrency {
+		t.Errorf("expected ErrInvalidCurrency, got %v", err)
+	}
+}
@@ -0,0 +1,165 @@
+package domain
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type BankLineID string
+
+// BankLineState is where an imported bank line is on its way to the ledger.
+type BankLineState string
+
+const (
+	// BankLineStateNew is a line as it was imported.
+	BankLineStateNew BankLineState = "NEW"
+	// BankLineStateMatched is a line whose payer is known but which is not
+	// yet registered as a payment.
+	BankLineStateMatched BankLineState = "MATCHED"
+	// BankLineStatePosted is a line registered as a payment.
+	BankLineStatePosted BankLineState = "POSTED"
+	// BankLineStateIgnored is a line that will not become a payment, such as
+	// a transfer between the company's own accounts.
+	BankLineStateIgnored BankLineState = "IGNORED"
+)
+
+// BankStatementFormat is the layout of an uploaded bank statement.
+type BankStatementFormat string
+
+const (
+	BankStatementCAMT053 BankStatementFormat = "CAMT053"
+	BankStatementMT940   BankStatementFormat = "MT940"
+	BankStatementCSV     BankStatementFormat = "CSV"
+)
+
+// BankLine is an incoming transfer read from a bank statement and staged
+// until it is posted as a payment or ignored. Reference is the bank's own
+// reference for the transfer and is unique within Account, so importing the
+// same statement twice yields the same lines.
+type BankLine struct {
+	ID          BankLineID
+	Account     string
+	Reference   string
+	BookingDate time.Time
+	ValueDate   time.Time
+	Amount      Money
+	PayerName   string
+	PayerIBAN   string
+	PayerTaxID  string
+	Description string
+	Format      BankStatementFormat
+	State       BankLineState
+	// CustomerID is the payer once the line is matched.
+	CustomerID CustomerID
+	// PaymentID is the payment the line was posted as.
+	PaymentID  PaymentID
+	ImportedAt time.Time
+	UpdatedAt  time.Time
+}
+
+// BankPayer is who a transfer came from, as far as the bank statement says.
+type BankPayer struct {
+	Name  string
+	IBAN  string
+	TaxID string
+}
+
+// NewBankLine stages an incoming transfer of amount into account. A missing
+// value date is the booking date.
+//
+// Statements that carry no reference for a transfer, or a placeholder such
+// as NONREF or NOTPROVIDED, get one derived from its date, amount, payer and
+// description. ordinal tells apart transfers of one statement that agree in
+// all of these, such as two equal payments on the same day with a generic
+// note: it is the transfer's position among them, from 1, and keeps the
+// derived references stable when the statement is imported again.
+func NewBankLine(account, reference string, bookingDate, valueDate time.Time, amount Money, payer BankPayer, description string, ordinal int, format BankStatementFormat, importedAt time.Time) (*BankLine, error) {
+	account = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(account), " ", ""))
+	if amount.currency == "" || amount.amount <= 0 || bookingDate.IsZero() {
+		return nil, ErrInvalidBankStatement
+	}
+	if valueDate.IsZero() {
+		valueDate = bookingDate
+	}
+	description = strings.Join(strings.Fields(description), " ")
+	payer.Name = strings.Join(strings.Fields(payer.Name), " ")
+	payer.IBAN = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(payer.IBAN), " ", ""))
+
+	reference = strings.TrimSpace(reference)
+	if isPlaceholderReference(reference) {
+		reference = "H-" + digest(RateDay(bookingDate).Format("2006-01-02"), amount.Decimal(), amount.currency,
+			payer.IBAN, payer.Name, description, strconv.Itoa(ordinal))[:16]
+	}
+
+	return &BankLine{
+		ID:          BankLineID("BL-" + digest(account, reference)[:16]),
+		Account:     account,
+		Reference:   reference,
+		BookingDate: bookingDate,
+		ValueDate:   valueDate,
+		Amount:      amount,
+		PayerName:   payer.Name,
+		PayerIBAN:   payer.IBAN,
+		PayerTaxID:  strings.TrimSpace(payer.TaxID),
+		Description: description,
+		Format:      format,
+		State:       BankLineStateNew,
+		ImportedAt:  importedAt,
+		UpdatedAt:   importedAt,
+	}, nil
+}
+
+// isPlaceholderReference reports whether a statement's reference does not
+// identify the transfer: MT940 writes NONREF and ISO 20022 NOTPROVIDED when
+// the bank or the payer gave none.
+func isPlaceholderReference(reference string) bool {
+	return reference == "" || strings.EqualFold(reference, "NONREF") || strings.EqualFold(reference, "NOTPROVIDED")
+}
+
+func digest(parts ...string) string {
+	sum := sha1.Sum([]byte(strings.Join(parts, "|")))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsOpen reports whether the line still waits to be posted or ignored.
+func (l *BankLine) IsOpen() bool {
+	return l.State == BankLineStateNew || l.State == BankLineStateMatched
+}
+
+// Match records the customer who made the transfer.
+func (l *BankLine) Match(customerID CustomerID, at time.Time) error {
+	if !l.IsOpen() {
+		return ErrInvalidBankLineState
+	}
+	if customerID == "" {
+		return ErrCustomerNotFound
+	}
+	l.CustomerID = customerID
+	l.State = BankLineStateMatched
+	l.UpdatedAt = at
+	return nil
+}
+
+// Post records the payment a matched line was registered as.
+func (l *BankLine) Post(paymentID PaymentID, at time.Time) error {
+	if l.State != BankLineStateMatched {
+		return ErrInvalidBankLineState
+	}
+	l.PaymentID = paymentID
+	l.State = BankLineStatePosted
+	l.UpdatedAt = at
+	return nil
+}
+
+// Ignore keeps an open line out of the payments.
+func (l *BankLine) Ignore(at time.Time) error {
+	if !l.IsOpen() {
+		return ErrInvalidBankLineState
+	}
+	l.State = BankLineStateIgnored
+	l.UpdatedAt = at
+	return nil
+}
@@ -0,0 +1,99 @@
+package domain_test
+
+import (
+	"carigo/internal/domain"
+	"testing"
+	"time"
+)
+
+func TestNewBankLine(t *testing.T) {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	now := day.Add(10 * time.Hour)
+
+	line, err := domain.NewBankLine("tr33 0006 1005 1978 6457 8413 26", "202603020001", day, time.Time{}, tryMoney(150000), domain.BankPayer{}, "  INV-001  ödemesi ", 1, domain.BankStatementMT940, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if line.Account != "TR330006100519786457841326" || line.State != domain.BankLineStateNew || !line.ValueDate.Equal(day) || line.Description != "INV-001 ödemesi" {
+		t.Errorf("unexpected line: %+v", line)
+	}
+
+	again, _ := domain.NewBankLine("TR330006100519786457841326", "202603020001", day, day, tryMoney(150000), domain.BankPayer{}, "", 1, domain.BankStatementCSV, now.AddDate(0, 0, 1))
+	if again.ID != line.ID {
+		t.Errorf("expected the same account and reference to give the same ID, got %s and %s", line.ID, again.ID)
+	}
+	other, _ := domain.NewBankLine("TR330006100519786457841327", "202603020001", day, day, tryMoney(150000), domain.BankPayer{}, "", 1, domain.BankStatementCSV, now)
+	if other.ID == line.ID {
+		t.Error("expected another account to give another ID")
+	}
+
+	// Without a reference, the line is keyed by what the bank says about it.
+	acme := domain.BankPayer{Name: "ACME AS", IBAN: "TR120006400000112345678901"}
+	a, _ := domain.NewBankLine("TR1", "NONREF", day, day, tryMoney(100), acme, "EFT ABC", 1, domain.BankStatementMT940, now)
+	b, _ := domain.NewBankLine("TR1", "", day.Add(time.Hour), day, tryMoney(100), acme, "EFT  ABC", 1, domain.BankStatementMT940, now)
+	if a.Reference == "NONREF" || a.ID != b.ID {
+		t.Errorf("expected alike lines without a reference to agree, got %s and %s", a.Reference, b.Reference)
+	}
+	notProvided, _ := domain.NewBankLine("TR1", "NOTPROVIDED", day, day, tryMoney(100), acme, "EFT ABC", 1, domain.BankStatementCAMT053, now)
+	if notProvided.ID != a.ID {
+		t.Errorf("expected NOTPROVIDED to be no reference, got %s", notProvided.Reference)
+	}
+	others := map[string]*domain.BankLine{}
+	others["amount"], _ = domain.NewBankLine("TR1", "", day, day, tryMoney(200), acme, "EFT ABC", 1, domain.BankStatementMT940, now)
+	others["payer IBAN"], _ = domain.NewBankLine("TR1", "", day, day, tryMoney(100), domain.BankPayer{Name: "ACME AS", IBAN: "TR1"}, "EFT ABC", 1, domain.BankStatementMT940, now)
+	others["payer name"], _ = domain.NewBankLine("TR1", "", day, day, tryMoney(100), domain.BankPayer{Name: "XYZ LTD", IBAN: acme.IBAN}, "EFT ABC", 1, domain.BankStatementMT940, now)
+	others["ordinal"], _ = domain.NewBankLine("TR1", "", day, day, tryMoney(100), acme, "EFT ABC", 2, domain.BankStatementMT940, now)
+	for name, other := range others {
+		if other.ID == a.ID {
+			t.Errorf("expected another %s to give another ID", name)
+		}
+	}
+
+	invalid := []struct {
+		name   string
+		amount domain.Money
+		booked time.Time
+	}{
+		{"zero amount", tryMoney(0), day},
+		{"no currency", domain.Money{}, day},
+		{"no booking date", tryMoney(100), time.Time{}},
+	}
+	for _, tt := range invalid {
+		if _, err := domain.NewBankLine("TR1", "R1", tt.booked, tt.booked, tt.amount, domain.BankPayer{}, "", 1, domain.BankStatementCSV, now); err != domain.ErrInvalidBankStatement {
+			t.Errorf("%s: expected ErrInvalidBankStatement, got %v", tt.name, err)
+		}
+	}
+}
+
+func TestBankLine_States(t *testing.T) {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	newLine := func() *domain.BankLine {
+		l, _ := domain.NewBankLine("TR1", "R1", day, day, tryMoney(100), domain.BankPayer{}, "", 1, domain.BankStatementCSV, day)
+		return l
+	}
+
+	line := newLine()
+	if err := line.Post("PAY-1", day); err != domain.ErrInvalidBankLineState {
+		t.Errorf("expected an unmatched line not to post, got %v", err)
+	}
+	if err := line.Match("CUST-001", day); err != nil || line.State != domain.BankLineStateMatched {
+		t.Fatalf("expected the line to match, got %s, %v", line.State, err)
+	}
+	if err := line.Match("CUST-002", day); err != nil || line.CustomerID != "CUST-002" {
+		t.Errorf("expected a matched line to be corrected, got %s, %v", line.CustomerID, err)
+	}
+	if err := line.Post("PAY-1", day); err != nil || line.State != domain.BankLineStatePosted || line.PaymentID != "PAY-1" {
+		t.Fatalf("expected the line to post, got %+v, %v", line, err)
+	}
+	if err := line.Ignore(day); err != domain.ErrInvalidBankLineState {
+		t.Errorf("expected a posted line not to be ignored, got %v", err)
+	}
+
+	ignored := newLine()
+	if err := ignored.Ignore(day); err != nil || ignored.IsOpen() {
+		t.Fatalf("expected the line to be ignored, got %s, %v", ignored.State, err)
+	}
+	if err := ignored.Match("CUST-001", day); err != domain.ErrInvalidBankLineState {
+		t.Errorf("expected an ignored line not to match, got %v", err)
+	}
+}
@@ -0,0 +1,205 @@
+package domain
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// BankMatchRule says that transfers from IBAN are payments of CustomerID.
+// Rules are learned from the customers clerks pick for bank lines.
+type BankMatchRule struct {
+	IBAN       string
+	CustomerID CustomerID
+	CreatedAt  time.Time
+}
+
+func NewBankMatchRule(iban string, customerID CustomerID, at time.Time) (*BankMatchRule, error) {
+	iban = strings.ToUpper(strings.Join(strings.Fields(iban), ""))
+	if iban == "" || customerID == "" {
+		return nil, ErrInvalidBankMatchRule
+	}
+	return &BankMatchRule{IBAN: iban, CustomerID: customerID, CreatedAt: at}, nil
+}
+
+// MatchReason is a piece of evidence that a bank line comes from a customer.
+type MatchReason string
+
+const (
+	MatchReasonIBAN          MatchReason = "IBAN"
+	MatchReasonTaxID         MatchReason = "TAX_ID"
+	MatchReasonInvoiceNumber MatchReason = "INVOICE_NUMBER"
+	MatchReasonExactAmount   MatchReason = "EXACT_AMOUNT"
+)
+
+// matchScores weigh the reasons. A learned IBAN rule is enough on its own to
+// post a line; a tax ID or an invoice number needs another reason with it.
+var matchScores = map[MatchReason]int{
+	MatchReasonIBAN:          80,
+	MatchReasonTaxID:         50,
+	MatchReasonInvoiceNumber: 50,
+	MatchReasonExactAmount:   30,
+}
+
+const (
+	// AutoPostScore is the score from which the best candidate is posted
+	// without review, provided it leads the next one by MatchLead.
+	AutoPostScore = 80
+	// SuggestScore is the score from which the best candidate is suggested
+	// to the clerk, provided it leads the next one by MatchLead.
+	SuggestScore = 50
+	MatchLead    = 30
+	maxScore     = 100
+)
+
+// BankMatchCandidate is a customer who may have made a transfer, and why.
+type BankMatchCandidate struct {
+	CustomerID CustomerID
+	// Score is the weight of the reasons, capped at 100.
+	Score   int
+	Reasons []MatchReason
+	// Invoices are the customer's open invoices the transfer names or pays
+	// exactly.
+	Invoices []InvoiceID
+}
+
+// BankMatch is the outcome of matching a bank line: its candidates, best
+// first.
+type BankMatch struct {
+	Candidates []BankMatchCandidate
+}
+
+// taxIDPattern finds VKNs (10 digits) and TCKNs (11 digits) in free text.
+var taxIDPattern = regexp.MustCompile(`\b\d{10,11}\b`)
+
+// MatchBankLine scores every customer against the line: a rule for the
+// payer's IBAN, the payer's tax ID, given by the bank or written in the
+// description, against Customer.TaxID, invoice numbers of the customer's open
+// invoices in the description, and open invoices whose remaining amount is
+// exactly the transferred amount. open holds the open invoices per customer.
+func MatchBankLine(line *BankLine, customers []*Customer, open map[CustomerID][]*Invoice, rules []*BankMatchRule) BankMatch {
+	taxIDs := map[string]bool{}
+	if id := digitsOnly(line.PayerTaxID); id != "" {
+		taxIDs[id] = true
+	}
+	for _, id := range taxIDPattern.FindAllString(line.Description, -1) {
+		taxIDs[id] = true
+	}
+
+	var match BankMatch
+	for _, c := range customers {
+		cand := BankMatchCandidate{CustomerID: c.ID}
+		add := func(reason MatchReason) {
+			for _, r := range cand.Reasons {
+				if r == reason {
+					return
+				}
+			}
+			cand.Reasons = append(cand.Reasons, reason)
+			cand.Score += matchScores[reason]
+		}
+
+		for _, r := range rules {
+			if line.PayerIBAN != "" && r.IBAN == line.PayerIBAN && r.CustomerID == c.ID {
+				add(MatchReasonIBAN)
+			}
+		}
+		if id := digitsOnly(c.TaxID); id != "" && taxIDs[id] {
+			add(MatchReasonTaxID)
+		}
+		for _, inv := range open[c.ID] {
+			if inv.Status != InvoiceStatusOpen && inv.Status != InvoiceStatusPartial {
+				continue
+			}
+			named := mentions(line.Description, string(inv.ID))
+			exact := inv.RemainingAmount().Equals(line.Amount)
+			if named {
+				add(MatchReasonInvoiceNumber)
+			}
+			if exact {
+				add(MatchReasonExactAmount)
+			}
+			if named || exact {
+				cand.Invoices = append(cand.Invoices, inv.ID)
+			}
+		}
+
+		if cand.Score > 0 {
+			cand.Score = min(cand.Score, maxScore)
+			match.Candidates = append(match.Candidates, cand)
+		}
+	}
+
+	sort.SliceStable(match.Candidates, func(i, j int) bool {
+		if match.Candidates[i].Score != match.Candidates[j].Score {
+			return match.Candidates[i].Score > match.Candidates[j].Score
+		}
+		return match.Candidates[i].CustomerID < match.Candidates[j].CustomerID
+	})
+	return match
+}
+
+// Best is the top candidate if it reaches score and leads the runner-up by
+// MatchLead.
+func (m BankMatch) Best(score int) (BankMatchCandidate, bool) {
+	if len(m.Candidates) == 0 || m.Candidates[0].Score < score {
+		return BankMatchCandidate{}, false
+	}
+	if len(m.Candidates) > 1 && m.Candidates[0].Score-m.Candidates[1].Score < MatchLead {
+		return BankMatchCandidate{}, false
+	}
+	return m.Candidates[0], true
+}
+
+func digitsOnly(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// mentions reports whether text names the invoice number id. Banks often
+// strip the dashes and spaces from invoice numbers, so only letters and
+// digits are compared, ignoring case. The number must stand on its own in
+// text, though: "INV-10" and "XINV-1" do not name INV-1.
+func mentions(text, id string) bool {
+	var want []rune
+	for _, r := range strings.ToUpper(id) {
+		if isAlphanumeric(r) {
+			want = append(want, r)
+		}
+	}
+	if len(want) == 0 {
+		return false
+	}
+
+	runes := []rune(strings.ToUpper(text))
+	var letters []rune
+	var at []int // at[i] is where letters[i] is in runes
+	for i, r := range runes {
+		if isAlphanumeric(r) {
+			letters = append(letters, r)
+			at = append(at, i)
+		}
+	}
+	for i := 0; i+len(want) <= len(letters); i++ {
+		if string(letters[i:i+len(want)]) != string(want) {
+			continue
+		}
+		start, end := at[i], at[i+len(want)-1]
+		if start > 0 && isAlphanumeric(runes[start-1]) || end+1 < len(runes) && isAlphanumeric(runes[end+1]) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+func isAlphanumeric(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,132 @@
+package domain_test
+
+import (
+	"carigo/internal/domain"
+	"testing"
+	"time"
+)
+
+func TestMatchBankLine(t *testing.T) {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	abc, _ := domain.NewCustomer("CUST-ABC", "ABC", "a@b.com", "1234567890")
+	xyz, _ := domain.NewCustomer("CUST-XYZ", "XYZ", "x@y.com", "9876543210")
+	customers := []*domain.Customer{abc, xyz}
+
+	invoice := func(id domain.InvoiceID, customer domain.CustomerID, amount int64) *domain.Invoice {
+		inv, _ := domain.NewInvoice(id, customer, tryMoney(amount), day.AddDate(0, -1, 0), day)
+		return inv
+	}
+	open := map[domain.CustomerID][]*domain.Invoice{
+		"CUST-ABC": {invoice("INV-1001", "CUST-ABC", 150000), invoice("INV-1002", "CUST-ABC", 20000)},
+		"CUST-XYZ": {invoice("INV-2001", "CUST-XYZ", 150000)},
+	}
+	rules := []*domain.BankMatchRule{{IBAN: "TR120006400000112345678901", CustomerID: "CUST-XYZ"}}
+
+	line := func(amount int64, iban, taxID, description string) *domain.BankLine {
+		l, _ := domain.NewBankLine("TR1", description, day, day, tryMoney(amount), domain.BankPayer{IBAN: iban, TaxID: taxID}, description, 1, domain.BankStatementCSV, day)
+		return l
+	}
+
+	tests := []struct {
+		name     string
+		line     *domain.BankLine
+		best     domain.CustomerID
+		score    int
+		reasons  []domain.MatchReason
+		autoPost bool
+		suggest  bool
+	}{
+		{"learned IBAN", line(500, "TR120006400000112345678901", "", "havale"), "CUST-XYZ", 80, []domain.MatchReason{domain.MatchReasonIBAN}, true, true},
+		{"tax ID and invoice number", line(500, "", "1234567890", "INV1002 odemesi"), "CUST-ABC", 100, []domain.MatchReason{domain.MatchReasonTaxID, domain.MatchReasonInvoiceNumber}, true, true},
+		{"tax ID in the description and the exact amount", line(20000, "", "", "VKN 1234567890"), "CUST-ABC", 80, []domain.MatchReason{domain.MatchReasonTaxID, domain.MatchReasonExactAmount}, true, true},
+		{"invoice number and the exact amount", line(150000, "", "", "Fatura INV-2001"), "CUST-XYZ", 80, []domain.MatchReason{domain.MatchReasonInvoiceNumber, domain.MatchReasonExactAmount}, true, true},
+		{"tax ID alone", line(500, "", "9876543210", ""), "CUST-XYZ", 50, []domain.MatchReason{domain.MatchReasonTaxID}, false, true},
+		{"amount shared by two customers", line(150000, "", "", "EFT"), "CUST-ABC", 30, []domain.MatchReason{domain.MatchReasonExactAmount}, false, false},
+	}
+
+	for _, tt := range tests {
+		match := domain.MatchBankLine(tt.line, customers, open, rules)
+		if len(match.Candidates) == 0 {
+			t.Errorf("%s: expected candidates", tt.name)
+			continue
+		}
+		top := match.Candidates[0]
+		if top.CustomerID != tt.best || top.Score != tt.score || len(top.Reasons) != len(tt.reasons) {
+			t.Errorf("%s: expected %s scoring %d for %v, got %+v", tt.name, tt.best, tt.score, tt.reasons, top)
+			continue
+		}
+		for i, r := range tt.reasons {
+			if top.Reasons[i] != r {
+				t.Errorf("%s: expected reasons %v, got %v", tt.name, tt.reasons, top.Reasons)
+			}
+		}
+		if _, ok := match.Best(domain.AutoPostScore); ok != tt.autoPost {
+			t.Errorf("%s: expected auto-post %v", tt.name, tt.autoPost)
+		}
+		if _, ok := match.Best(domain.SuggestScore); ok != tt.suggest {
+			t.Errorf("%s: expected suggestion %v", tt.name, tt.suggest)
+		}
+	}
+
+	if match := domain.MatchBankLine(line(999, "", "", "kira"), customers, open, rules); len(match.Candidates) != 0 {
+		t.Errorf("expected no candidates, got %+v", match.Candidates)
+	}
+}
+
+func TestNewBankMatchRule(t *testing.T) {
+	rule, err := domain.NewBankMatchRule("tr12 0006 4000 0011 2345 6789 01", "CUST-001", time.Now())
+	if err != nil || rule.IBAN != "TR120006400000112345678901" {
+		t.Errorf("unexpected rule %+v, %v", rule, err)
+	}
+	if _, err := domain.NewBankMatchRule("", "CUST-001", time.Now()); err != domain.ErrInvalidBankMatchRule {
+		t.Errorf("expected ErrInvalidBankMatchRule, got %v", err)
+	}
+}
+
+func TestMatchBankLine_InvoiceNumber(t *testing.T) {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	abc, _ := domain.NewCustomer("CUST-ABC", "ABC", "a@b.com", "")
+	xyz, _ := domain.NewCustomer("CUST-XYZ", "XYZ", "x@y.com", "")
+	customers := []*domain.Customer{abc, xyz}
+
+	// The invoice numbers are prefixes of one another, and the amounts
+	// match no line, so only the numbers decide.
+	invoice := func(id domain.InvoiceID, customer domain.CustomerID) *domain.Invoice {
+		inv, _ := domain.NewInvoice(id, customer, tryMoney(100000), day.AddDate(0, -1, 0), day)
+		return inv
+	}
+	open := map[domain.CustomerID][]*domain.Invoice{
+		"CUST-ABC": {invoice("INV-1", "CUST-ABC"), invoice("INV-100", "CUST-ABC")},
+		"CUST-XYZ": {invoice("INV-10", "CUST-XYZ")},
+	}
+
+	tests := []struct {
+		description string
+		want        map[domain.CustomerID][]domain.InvoiceID
+	}{
+		{"INV-10 odemesi", map[domain.CustomerID][]domain.InvoiceID{"CUST-XYZ": {"INV-10"}}},
+		{"Fatura inv 1.", map[domain.CustomerID][]domain.InvoiceID{"CUST-ABC": {"INV-1"}}},
+		{"INV100", map[domain.CustomerID][]domain.InvoiceID{"CUST-ABC": {"INV-100"}}},
+		{"INV-1, INV-10", map[domain.CustomerID][]domain.InvoiceID{"CUST-ABC": {"INV-1"}, "CUST-XYZ": {"INV-10"}}},
+		{"INV-1000", nil},
+		{"XINV-1", nil},
+		{"INV-1Ş", nil},
+	}
+	for _, tt := range tests {
+		l, _ := domain.NewBankLine("TR1", tt.description, day, day, tryMoney(500), domain.BankPayer{}, tt.description, 1, domain.BankStatementCSV, day)
+		match := domain.MatchBankLine(l, customers, open, nil)
+		got := map[domain.CustomerID][]domain.InvoiceID{}
+		for _, c := range match.Candidates {
+			got[c.CustomerID] = c.Invoices
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%q: expected %v, got %v", tt.description, tt.want, got)
+			continue
+		}
+		for customer, invoices := range tt.want {
+			if len(got[customer]) != len(invoices) || got[customer][0] != invoices[0] {
+				t.Errorf("%q: expected %v, got %v", tt.description, tt.want, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"time"
+)
+
+type CreditNoteID string
+
+// CreditNote reduces a customer's debt without cash changing hands, e.g. for
+// returns or price corrections. Like a Payment, its amount is allocated
+// against open invoices and any remainder stays on account. InvoiceID is
+// empty when the note is not issued for a specific invoice.
+type CreditNote struct {
+	ID              CreditNoteID
+	CustomerID      CustomerID
+	InvoiceID       InvoiceID
+	Amount          Money
+	AvailableAmount Money
+	Date            time.Time
+	Reason          string
+	CreatedAt       time.Time
+}
+
+func NewCreditNote(id CreditNoteID, customerID CustomerID, invoiceID InvoiceID, amount Money, date time.Time, reason string) (*CreditNote, error) {
+	if amount.IsZero() || amount.amount < 0 {
+		return nil, ErrNegativeAmount
+	}
+
+	return &CreditNote{
+		ID:              id,
+		CustomerID:      customerID,
+		InvoiceID:       invoiceID,
+		Amount:          amount,
+		AvailableAmount: amount,
+		Date:            date,
+		Reason:          reason,
+		CreatedAt:       time.Now(),
+	}, nil
+}
+
+func (c *CreditNote) UseFunds(amount Money) error {
+	if amount.currency != c.AvailableAmount.currency {
+		return ErrCurrencyMismatch
+	}
+	greater, _ := amount.GreaterThan(c.AvailableAmount)
+	if greater {
+		return ErrInsufficientPaymentBalance
+	}
+
+	newAvailable, err := c.AvailableAmount.Subtract(amount)
+	if err != nil {
+		return err
+	}
+	c.AvailableAmount = newAvailable
+	return nil
+}
+
+// RestoreFunds gives back an amount previously taken with UseFunds.
+func (c *CreditNote) RestoreFunds(amount Money) error {
+	if amount.currency != c.AvailableAmount.currency {
+		return ErrCurrencyMismatch
+	}
+
+	newAvailable, err := c.AvailableAmount.Add(amount)
+	if err != nil {
+		return err
+	}
+	if exceeds, _ := newAvailable.GreaterThan(c.Amount); exceeds {
+		return ErrInvalidAllocationReversal
+	}
+	c.AvailableAmount = newAvailable
+	return nil
+}
+
+func (c *CreditNote) owner() CustomerID { return c.CustomerID }
+
+func (c *CreditNote) available() Money { return c.AvailableAmount }
+
+func (c *CreditNote) attach(a *Allocation) { a.CreditNoteID = c.ID }
+
+func (c *CreditNote) owns(a *Allocation) bool { return a.CreditNoteID == c.ID }
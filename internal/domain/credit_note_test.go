@@ -0,0 +1,66 @@
+package domain_test
+
+import (
+	"carigo/internal/domain"
+	"testing"
+	"time"
+)
+
+func TestNewCreditNote_RejectsNonPositiveAmount(t *testing.T) {
+	zero, _ := domain.NewMoney(0, "TRY")
+	if _, err := domain.NewCreditNote("CN-001", "CUST-001", "", zero, time.Now(), "iade"); err != domain.ErrNegativeAmount {
+		t.Errorf("expected ErrNegativeAmount, got %v", err)
+	}
+}
+
+func TestCreditNote_AllocateAndReverse(t *testing.T) {
+	inv := newTestInvoice(t, "INV-001", 1000, "TRY")
+	amount, _ := domain.NewMoney(400, "TRY")
+	note, err := domain.NewCreditNote("CN-001", "CUST-001", inv.ID, amount, time.Now(), "iade")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	alloc, err := domain.NewAllocation("AL-1", note, inv, amount)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if alloc.CreditNoteID != "CN-001" || alloc.PaymentID != "" {
+		t.Errorf("expected allocation to reference the credit note, got %+v", alloc)
+	}
+	if !note.AvailableAmount.IsZero() {
+		t.Errorf("expected credit note to be used up, got %d", note.AvailableAmount.Amount())
+	}
+	if inv.Status != domain.InvoiceStatusPartial {
+		t.Errorf("expected status PARTIAL, got %s", inv.Status)
+	}
+
+	payment := domain.NewPayment("PAY-001", "CUST-001", amount, time.Now())
+	if _, err := alloc.Reverse("REV-1", payment, inv, "yanlış", time.Now()); err != domain.ErrInvalidAllocationReversal {
+		t.Errorf("expected ErrInvalidAllocationReversal for a foreign source, got %v", err)
+	}
+
+	reversal, err := alloc.Reverse("REV-1", note, inv, "yanlış", time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reversal.CreditNoteID != "CN-001" {
+		t.Errorf("expected reversal to reference the credit note, got %q", reversal.CreditNoteID)
+	}
+	if !note.AvailableAmount.Equals(amount) {
+		t.Errorf("expected credit note amount to be restored, got %d", note.AvailableAmount.Amount())
+	}
+	if inv.Status != domain.InvoiceStatusOpen {
+		t.Errorf("expected status OPEN, got %s", inv.Status)
+	}
+}
+
+func TestCreditNote_CustomerMismatch(t *testing.T) {
+	inv := newTestInvoice(t, "INV-001", 1000, "TRY")
+	amount, _ := domain.NewMoney(400, "TRY")
+	note, _ := domain.NewCreditNote("CN-001", "CUST-002", "", amount, time.Now(), "iade")
+
+	if _, err := domain.NewAllocation("AL-1", note, inv, amount); err != domain.ErrCustomerMismatch {
+		t.Errorf("expected ErrCustomerMismatch, got %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// CreditAction is what happens to a new invoice that breaks the credit policy.
+type CreditAction string
+
+const (
+	// CreditActionReject refuses the invoice.
+	CreditActionReject CreditAction = "REJECT"
+	// CreditActionFlag issues the invoice with a warning.
+	CreditActionFlag CreditAction = "FLAG"
+)
+
+// CreditPolicy decides how new invoices are checked against the customer's
+// risk. A positive MaxDaysOverdue also holds back invoices for customers with
+// an invoice overdue by more than that many days.
+type CreditPolicy struct {
+	Action         CreditAction
+	MaxDaysOverdue int
+}
+
+// NewCreditPolicy validates the policy; an empty action rejects.
+func NewCreditPolicy(action CreditAction, maxDaysOverdue int) (CreditPolicy, error) {
+	switch action {
+	case "":
+		action = CreditActionReject
+	case CreditActionReject, CreditActionFlag:
+	default:
+		return CreditPolicy{}, ErrInvalidCreditPolicy
+	}
+	if maxDaysOverdue < 0 {
+		return CreditPolicy{}, ErrInvalidCreditPolicy
+	}
+	return CreditPolicy{Action: action, MaxDaysOverdue: maxDaysOverdue}, nil
+}
+
+// CreditRisk is where a customer stands against their credit limits and the
+// policy's overdue threshold.
+type CreditRisk struct {
+	// Exposure is the open amount of the customer's invoices per currency.
+	Exposure map[string]Money
+	// OverLimit lists, in order, the currencies whose exposure is above the
+	// customer's limit.
+	OverLimit []string
+	// Overdue lists the invoices overdue by more than the policy allows.
+	Overdue []InvoiceID
+
+	limits         map[string]Money
+	maxDaysOverdue int
+}
+
+// NewCreditRisk assesses the customer's open invoices at now.
+func NewCreditRisk(customer *Customer, open []*Invoice, policy CreditPolicy, now time.Time) (CreditRisk, error) {
+	r := CreditRisk{
+		Exposure:       map[string]Money{},
+		limits:         customer.CreditLimits,
+		maxDaysOverdue: policy.MaxDaysOverdue,
+	}
+	for _, inv := range open {
+		if inv.CustomerID != customer.ID {
+			return CreditRisk{}, ErrCustomerMismatch
+		}
+		if inv.Status != InvoiceStatusOpen && inv.Status != InvoiceStatusPartial {
+			continue
+		}
+		currency := inv.TotalAmount.currency
+		exposure, ok := r.Exposure[currency]
+		if !ok {
+			exposure = Money{currency: currency}
+		}
+		exposure, err := exposure.Add(inv.RemainingAmount())
+		if err != nil {
+			return CreditRisk{}, err
+		}
+		r.Exposure[currency] = exposure
+
+		if policy.MaxDaysOverdue > 0 && inv.DaysOverdue(now) > policy.MaxDaysOverdue {
+			r.Overdue = append(r.Overdue, inv.ID)
+		}
+	}
+
+	for currency, exposure := range r.Exposure {
+		if limit, ok := customer.CreditLimits[currency]; ok && exposure.amount > limit.amount {
+			r.OverLimit = append(r.OverLimit, currency)
+		}
+	}
+	sort.Strings(r.OverLimit)
+	sort.Slice(r.Overdue, func(i, j int) bool { return r.Overdue[i] < r.Overdue[j] })
+	return r, nil
+}
+
+// IsRisky reports whether the customer is over a limit or too long overdue.
+func (r CreditRisk) IsRisky() bool {
+	return len(r.OverLimit) > 0 || len(r.Overdue) > 0
+}
+
+// Breaches are the rules an invoice in currency, already counted in the
+// exposure, breaks: the limit of its own currency, and any invoice too long
+// overdue. There are none when the invoice may be issued.
+func (r CreditRisk) Breaches(currency string) []error {
+	var errs []error
+	for _, c := range r.OverLimit {
+		if c == currency {
+			errs = append(errs, fmt.Errorf("%w: open %s %s, limit %s",
+				ErrCreditLimitExceeded, currency, r.Exposure[c].Decimal(), r.limits[c].Decimal()))
+		}
+	}
+	if len(r.Overdue) > 0 {
+		errs = append(errs, fmt.Errorf("%w: %d invoice(s) more than %d days overdue, e.g. %s",
+			ErrCustomerOverdue, len(r.Overdue), r.maxDaysOverdue, r.Overdue[0]))
+	}
+	return errs
+}
@@ -0,0 +1,107 @@
+package domain_test
+
+import (
+	"carigo/internal/domain"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewCreditPolicy(t *testing.T) {
+	tests := []struct {
+		action domain.CreditAction
+		days   int
+		want   domain.CreditPolicy
+		err    error
+	}{
+		{"", 0, domain.CreditPolicy{Action: domain.CreditActionReject}, nil},
+		{domain.CreditActionFlag, 30, domain.CreditPolicy{Action: domain.CreditActionFlag, MaxDaysOverdue: 30}, nil},
+		{"WARN", 0, domain.CreditPolicy{}, domain.ErrInvalidCreditPolicy},
+		{domain.CreditActionReject, -1, domain.CreditPolicy{}, domain.ErrInvalidCreditPolicy},
+	}
+
+	for _, tt := range tests {
+		got, err := domain.NewCreditPolicy(tt.action, tt.days)
+		if err != tt.err || got != tt.want {
+			t.Errorf("NewCreditPolicy(%q, %d) = %+v, %v; want %+v, %v", tt.action, tt.days, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestCustomer_SetCreditLimit(t *testing.T) {
+	customer, _ := domain.NewCustomer("CUST-001", "ABC", "a@b.com", "1")
+
+	if err := customer.SetCreditLimit(tryMoney(5000)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if limit := customer.CreditLimits["TRY"]; limit.Amount() != 5000 {
+		t.Errorf("expected a 5000 TRY limit, got %+v", customer.CreditLimits)
+	}
+	if err := customer.SetCreditLimit(tryMoney(0)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := customer.CreditLimits["TRY"]; ok {
+		t.Errorf("expected a zero limit to remove the cap, got %+v", customer.CreditLimits)
+	}
+	if err := customer.SetCreditLimit(domain.Money{}); err != domain.ErrInvalidCreditLimit {
+		t.Errorf("expected ErrInvalidCreditLimit, got %v", err)
+	}
+}
+
+func TestNewCreditRisk(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	invoice := func(id string, amount int64, currency string, due time.Time) *domain.Invoice {
+		total, _ := domain.NewMoney(amount, currency)
+		inv, _ := domain.NewInvoice(domain.InvoiceID(id), "CUST-001", total, due.AddDate(0, -1, 0), due)
+		return inv
+	}
+
+	customer, _ := domain.NewCustomer("CUST-001", "ABC", "a@b.com", "1")
+	_ = customer.SetCreditLimit(tryMoney(10000))
+	usdLimit, _ := domain.NewMoney(100, "USD")
+	_ = customer.SetCreditLimit(usdLimit)
+
+	paid := invoice("INV-000", 90000, "TRY", now.AddDate(0, 0, -90))
+	_ = paid.AllocatePayment(paid.TotalAmount)
+	partial := invoice("INV-001", 8000, "TRY", now.AddDate(0, 0, -45))
+	_ = partial.AllocatePayment(tryMoney(3000))
+	open := []*domain.Invoice{
+		paid,
+		partial,
+		invoice("INV-002", 6000, "TRY", now.AddDate(0, 0, 10)),
+		invoice("INV-003", 100, "USD", now.AddDate(0, 0, -5)),
+	}
+
+	policy := domain.CreditPolicy{Action: domain.CreditActionReject, MaxDaysOverdue: 30}
+	risk, err := domain.NewCreditRisk(customer, open, policy, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if risk.Exposure["TRY"].Amount() != 11000 || risk.Exposure["USD"].Amount() != 100 {
+		t.Errorf("expected 11000 TRY and 100 USD open, got %+v", risk.Exposure)
+	}
+	if len(risk.OverLimit) != 1 || risk.OverLimit[0] != "TRY" {
+		t.Errorf("expected only TRY over its limit, got %v", risk.OverLimit)
+	}
+	if len(risk.Overdue) != 1 || risk.Overdue[0] != "INV-001" {
+		t.Errorf("expected INV-001 overdue by more than 30 days, got %v", risk.Overdue)
+	}
+	if !risk.IsRisky() {
+		t.Error("expected the customer to be risky")
+	}
+
+	try := risk.Breaches("TRY")
+	if len(try) != 2 || !errors.Is(try[0], domain.ErrCreditLimitExceeded) || !errors.Is(try[1], domain.ErrCustomerOverdue) {
+		t.Errorf("expected the TRY limit and the overdue invoice to be breached, got %v", try)
+	}
+	usd := risk.Breaches("USD")
+	if len(usd) != 1 || !errors.Is(usd[0], domain.ErrCustomerOverdue) {
+		t.Errorf("expected only the overdue invoice to be breached in USD, got %v", usd)
+	}
+
+	relaxed, _ := domain.NewCreditRisk(customer, open[2:], domain.CreditPolicy{Action: domain.CreditActionReject}, now)
+	if relaxed.IsRisky() || len(relaxed.Breaches("TRY")) != 0 {
+		t.Errorf("expected no risk within the limits, got %+v", relaxed)
+	}
+}
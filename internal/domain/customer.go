@@ -7,12 +7,21 @@ import (
 
 type CustomerID string
 type Customer struct {
-	ID        CustomerID
-	Name      string
-	Email     string
-	TaxID     string
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID                 CustomerID
+	Name               string
+	Email              string
+	TaxID              string
+	AllocationStrategy AllocationStrategyName
+	// LateInterest is charged on overdue invoices; the zero rate charges none.
+	LateInterest LateInterestRate
+	// PaymentTerms are given to the customer's new invoices unless they
+	// come with their own.
+	PaymentTerms PaymentTerms
+	// CreditLimits caps the open amount of the customer's invoices per
+	// currency. Currencies without a limit are not capped.
+	CreditLimits map[string]Money
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
 }
 
 func NewCustomer(id CustomerID, name, email, taxID string) (*Customer, error) {
@@ -23,11 +32,55 @@ func NewCustomer(id CustomerID, name, email, taxID string) (*Customer, error) {
 		return nil, errors.New("customer name is required")
 	}
 	return &Customer{
-		ID:        id,
-		Name:      name,
-		Email:     email,
-		TaxID:     taxID,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		ID:                 id,
+		Name:               name,
+		Email:              email,
+		TaxID:              taxID,
+		AllocationStrategy: DefaultAllocationStrategy,
+		CreatedAt:          time.Now(),
+		UpdatedAt:          time.Now(),
 	}, nil
 }
+
+// SetAllocationStrategy changes the customer's default allocation order.
+func (c *Customer) SetAllocationStrategy(name AllocationStrategyName) error {
+	if _, err := NewAllocationStrategy(name); err != nil {
+		return err
+	}
+	if name == "" {
+		name = DefaultAllocationStrategy
+	}
+	c.AllocationStrategy = name
+	c.UpdatedAt = time.Now()
+	return nil
+}
+
+// SetLateInterestRate changes the interest charged on the customer's overdue invoices.
+func (c *Customer) SetLateInterestRate(rate LateInterestRate) {
+	c.LateInterest = rate
+	c.UpdatedAt = time.Now()
+}
+
+// SetPaymentTerms changes the default terms of the customer's new invoices.
+func (c *Customer) SetPaymentTerms(terms PaymentTerms) {
+	c.PaymentTerms = terms
+	c.UpdatedAt = time.Now()
+}
+
+// SetCreditLimit caps the customer's open invoices in the limit's currency.
+// A zero limit removes the cap.
+func (c *Customer) SetCreditLimit(limit Money) error {
+	if limit.currency == "" || limit.amount < 0 {
+		return ErrInvalidCreditLimit
+	}
+	if limit.IsZero() {
+		delete(c.CreditLimits, limit.currency)
+	} else {
+		if c.CreditLimits == nil {
+			c.CreditLimits = map[string]Money{}
+		}
+		c.CreditLimits[limit.currency] = limit
+	}
+	c.UpdatedAt = time.Now()
+	return nil
+}
@@ -3,12 +3,57 @@ package domain
 import "errors"
 
 var (
-	ErrNegativeAmount = errors.New("amount cannot be negative")
-	ErrCurrencyMismatch = errors.New("cannot operate on different currencies")
-	ErrInvalidCurrency = errors.New("invalid currency")
-	ErrInvalidInvoiceState = errors.New("invalid invoice state transition")
-	ErrInvoiceAlreadyPaid = errors.New("invoice is already paid")
-	ErrPaymentAmountMismatch = errors.New("payment amount mismatch")
-	ErrOverPaymentNotAllowed = errors.New("overpayment is not allowed for this operation")
+	ErrNegativeAmount             = errors.New("amount cannot be negative")
+	ErrInvalidAmount              = errors.New("invalid amount")
+	ErrInvalidRatios              = errors.New("ratios must be non-negative and not all zero")
+	ErrUnknownRoundingMode        = errors.New("unknown rounding mode")
+	ErrInvalidInvoiceLine         = errors.New("invalid invoice line")
+	ErrInvalidVATRate             = errors.New("VAT rate must be 0, 1, 10 or 20")
+	ErrInvalidWithholding         = errors.New("withholding ratio must be between 0/10 and 10/10")
+	ErrInvalidInstalments         = errors.New("instalments must be positive, in due date order and add up to the invoice total")
+	ErrInvalidAgingBuckets        = errors.New("aging bucket edges must be positive and increasing")
+	ErrCurrencyMismatch           = errors.New("cannot operate on different currencies")
+	ErrInvalidCurrency            = errors.New("invalid currency")
+	ErrInvalidInvoiceState        = errors.New("invalid invoice state transition")
+	ErrInvoiceAlreadyPaid         = errors.New("invoice is already paid")
+	ErrPaymentAmountMismatch      = errors.New("payment amount mismatch")
+	ErrOverPaymentNotAllowed      = errors.New("overpayment is not allowed for this operation")
 	ErrInsufficientPaymentBalance = errors.New("insufficient payment balance")
+	ErrAllocationPlanNotFound     = errors.New("allocation plan not found")
+	ErrAllocationPlanStale        = errors.New("open invoices changed since the allocation plan was made")
+	ErrUnknownAllocationStrategy  = errors.New("unknown allocation strategy")
+	ErrCustomerMismatch           = errors.New("payment and invoice belong to different customers")
+	ErrCustomerNotFound           = errors.New("customer not found")
+	ErrInvoiceNotFound            = errors.New("invoice not found")
+	ErrPaymentNotFound            = errors.New("payment not found")
+	ErrAllocationNotFound         = errors.New("allocation not found")
+	ErrAllocationAlreadyReversed  = errors.New("allocation is already reversed")
+	ErrInvalidAllocationReversal  = errors.New("allocation cannot be reversed")
+	ErrPaymentAlreadyReversed     = errors.New("payment is already reversed")
+	ErrPaymentHasAllocations      = errors.New("payment still has active allocations")
+	ErrInvoiceHasAllocations      = errors.New("invoice still has active allocations")
+	ErrCreditNoteNotFound         = errors.New("credit note not found")
+	ErrInvalidExchangeRate        = errors.New("invalid exchange rate")
+	ErrExchangeRateNotFound       = errors.New("exchange rate not found")
+	ErrNotForeignCurrency         = errors.New("allocation does not settle a foreign-currency invoice")
+	ErrExchangeDifferenceInvoiced = errors.New("exchange difference is already invoiced")
+	ErrNoExchangeGain             = errors.New("no exchange gain to invoice")
+	ErrInvalidInterestRate        = errors.New("late interest rate must be a non-negative percentage per MONTHLY or ANNUAL period")
+	ErrNoLateInterest             = errors.New("no late interest to invoice")
+	ErrInvalidPaymentTerms        = errors.New("invalid payment terms")
+	ErrDueDateRequired            = errors.New("due date is required when there are no payment terms")
+	ErrInvalidCreditLimit         = errors.New("credit limit must be a non-negative amount in a known currency")
+	ErrInvalidCreditPolicy        = errors.New("credit policy action must be REJECT or FLAG with non-negative overdue days")
+	ErrCreditLimitExceeded        = errors.New("invoice would exceed the customer's credit limit")
+	ErrCustomerOverdue            = errors.New("customer has invoices overdue beyond the allowed days")
+	ErrInvalidBankStatement       = errors.New("invalid bank statement")
+	ErrUnknownBankStatementFormat = errors.New("bank statement format must be CAMT053, MT940 or CSV with a known profile")
+	ErrBankLineNotFound           = errors.New("bank line not found")
+	ErrInvalidBankLineState       = errors.New("invalid bank line state transition")
+	ErrInvalidBankMatchRule       = errors.New("a bank match rule needs an IBAN and a customer")
+	ErrInvalidReconciliation      = errors.New("a reconciliation needs a customer, a date that is not in the future and one balance per currency")
+	ErrReconciliationNotFound     = errors.New("reconciliation not found")
+	ErrInvalidReconciliationState = errors.New("reconciliation is already answered")
+	ErrNoReconciliationDifference = errors.New("a disputed reconciliation must claim a balance that differs from ours")
+	ErrInvalidStatementPeriod     = errors.New("statement period cannot end before it starts")
 )
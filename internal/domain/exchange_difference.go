@@ -0,0 +1,103 @@
+package domain
+
+import "time"
+
+// LocalCurrency is the currency the books are kept in. Foreign-currency
+// receivables are valued in it when exchange differences are realized.
+const LocalCurrency = "TRY"
+
+// ExchangeDifference is the realized gain or loss (kur farkı) on an allocation
+// that settled a foreign-currency invoice. The settled invoice amount is valued
+// in LocalCurrency twice: at the invoice-date rate, as it was booked, and at
+// the settlement-date rate, as it was collected.
+type ExchangeDifference struct {
+	Allocation     *Allocation
+	CustomerID     CustomerID
+	InvoiceDate    time.Time
+	SettledAt      time.Time
+	InvoiceRate    *ExchangeRate
+	SettlementRate *ExchangeRate
+	BookedValue    Money
+	SettledValue   Money
+}
+
+func NewExchangeDifference(allocation *Allocation, invoice *Invoice, settledAt time.Time, invoiceRate, settlementRate *ExchangeRate) (*ExchangeDifference, error) {
+	if allocation.InvoiceID != invoice.ID {
+		return nil, ErrInvalidInvoiceState
+	}
+	foreign := allocation.InvoiceAmount
+	if foreign.currency == LocalCurrency {
+		return nil, ErrNotForeignCurrency
+	}
+	if !invoiceRate.Converts(foreign.currency, LocalCurrency) || !settlementRate.Converts(foreign.currency, LocalCurrency) {
+		return nil, ErrCurrencyMismatch
+	}
+
+	booked, err := invoiceRate.Convert(foreign)
+	if err != nil {
+		return nil, err
+	}
+	settled, err := settlementRate.Convert(foreign)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExchangeDifference{
+		Allocation:     allocation,
+		CustomerID:     invoice.CustomerID,
+		InvoiceDate:    invoice.IssueDate,
+		SettledAt:      settledAt,
+		InvoiceRate:    invoiceRate,
+		SettlementRate: settlementRate,
+		BookedValue:    booked,
+		SettledValue:   settled,
+	}, nil
+}
+
+// Difference is SettledValue minus BookedValue. A positive amount is a gain
+// the customer is billed for; a negative one is a loss.
+func (d *ExchangeDifference) Difference() Balance {
+	return Balance{amount: d.SettledValue.amount - d.BookedValue.amount, currency: LocalCurrency}
+}
+
+func (d *ExchangeDifference) IsGain() bool {
+	return d.Difference().IsDebit()
+}
+
+// IsInvoiced reports whether the gain has already been billed.
+func (d *ExchangeDifference) IsInvoiced() bool {
+	return d.Allocation.ExchangeDifferenceInvoiceID != ""
+}
+
+// NewExchangeDifferenceInvoice bills the customer for the given realized
+// gains with a single kur farkı invoice in LocalCurrency. Each underlying
+// allocation is linked to the invoice so the gain is not billed twice.
+// Losses are not netted off: they are for the customer to invoice.
+func NewExchangeDifferenceInvoice(id InvoiceID, customerID CustomerID, gains []*ExchangeDifference, issueDate, dueDate time.Time) (*Invoice, error) {
+	total := Money{currency: LocalCurrency}
+	for _, d := range gains {
+		if d.CustomerID != customerID {
+			return nil, ErrCustomerMismatch
+		}
+		if d.IsInvoiced() {
+			return nil, ErrExchangeDifferenceInvoiced
+		}
+		if !d.IsGain() {
+			return nil, ErrNoExchangeGain
+		}
+		total.amount += d.Difference().amount
+	}
+	if total.IsZero() {
+		return nil, ErrNoExchangeGain
+	}
+
+	inv, err := NewInvoice(id, customerID, total, issueDate, dueDate)
+	if err != nil {
+		return nil, err
+	}
+	inv.Kind = InvoiceKindExchangeDifference
+	for _, d := range gains {
+		d.Allocation.ExchangeDifferenceInvoiceID = id
+	}
+	return inv, nil
+}
@@ -0,0 +1,144 @@
+package domain_test
+
+import (
+	"carigo/internal/domain"
+	"testing"
+	"time"
+)
+
+func usdRate(t *testing.T, rate string, day time.Time) *domain.ExchangeRate {
+	t.Helper()
+	value, err := domain.ParseRate(rate)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r, err := domain.NewExchangeRate("USD", "TRY", value, day, domain.ExchangeRateSourceManual)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return r
+}
+
+func settleUSD(t *testing.T, id string, amount int64) (*domain.Invoice, *domain.Allocation) {
+	t.Helper()
+	inv := newTestInvoice(t, "INV-"+id, 100000, "USD")
+	money, _ := domain.NewMoney(amount, "USD")
+	payment := domain.NewPayment(domain.PaymentID("PAY-"+id), "CUST-001", money, time.Now())
+	alloc, err := domain.NewAllocation(domain.AllocationID("AL-"+id), payment, inv, money)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return inv, alloc
+}
+
+func TestNewExchangeDifference(t *testing.T) {
+	inv, alloc := settleUSD(t, "1", 40000)
+	issued := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	paid := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	gain, err := domain.NewExchangeDifference(alloc, inv, paid, usdRate(t, "36.5", issued), usdRate(t, "38.25", paid))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gain.BookedValue.Amount() != 1460000 || gain.SettledValue.Amount() != 1530000 {
+		t.Errorf("expected 14600.00 booked and 15300.00 settled TRY, got %d and %d", gain.BookedValue.Amount(), gain.SettledValue.Amount())
+	}
+	if !gain.IsGain() || gain.Difference().Amount() != 70000 || gain.Difference().Currency() != "TRY" {
+		t.Errorf("expected a 700.00 TRY gain, got %d %s", gain.Difference().Amount(), gain.Difference().Currency())
+	}
+
+	loss, err := domain.NewExchangeDifference(alloc, inv, paid, usdRate(t, "36.5", issued), usdRate(t, "36", paid))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if loss.IsGain() || loss.Difference().Amount() != -20000 {
+		t.Errorf("expected a 200.00 TRY loss, got %d", loss.Difference().Amount())
+	}
+}
+
+func TestNewExchangeDifference_RejectsLocalCurrency(t *testing.T) {
+	inv := newTestInvoice(t, "INV-001", 1000, "TRY")
+	amount, _ := domain.NewMoney(1000, "TRY")
+	payment := domain.NewPayment("PAY-001", "CUST-001", amount, time.Now())
+	alloc, err := domain.NewAllocation("AL-1", payment, inv, amount)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rate := usdRate(t, "36.5", time.Now())
+	if _, err := domain.NewExchangeDifference(alloc, inv, time.Now(), rate, rate); err != domain.ErrNotForeignCurrency {
+		t.Errorf("expected ErrNotForeignCurrency, got %v", err)
+	}
+}
+
+func TestNewExchangeDifferenceInvoice(t *testing.T) {
+	issued := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	paid := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	inv1, alloc1 := settleUSD(t, "1", 40000)
+	inv2, alloc2 := settleUSD(t, "2", 10000)
+	first, _ := domain.NewExchangeDifference(alloc1, inv1, paid, usdRate(t, "36.5", issued), usdRate(t, "38.25", paid))
+	second, _ := domain.NewExchangeDifference(alloc2, inv2, paid, usdRate(t, "36.5", issued), usdRate(t, "37", paid))
+
+	kf, err := domain.NewExchangeDifferenceInvoice("KF-1", "CUST-001", []*domain.ExchangeDifference{first, second}, paid, paid)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if kf.Kind != domain.InvoiceKindExchangeDifference {
+		t.Errorf("expected kind EXCHANGE_DIFFERENCE, got %s", kf.Kind)
+	}
+	if kf.TotalAmount.Amount() != 75000 || kf.TotalAmount.Currency() != "TRY" {
+		t.Errorf("expected 750.00 TRY, got %d %s", kf.TotalAmount.Amount(), kf.TotalAmount.Currency())
+	}
+	if alloc1.ExchangeDifferenceInvoiceID != "KF-1" || alloc2.ExchangeDifferenceInvoiceID != "KF-1" {
+		t.Errorf("expected both allocations linked to KF-1")
+	}
+
+	if _, err := domain.NewExchangeDifferenceInvoice("KF-2", "CUST-001", []*domain.ExchangeDifference{first}, paid, paid); err != domain.ErrExchangeDifferenceInvoiced {
+		t.Errorf("expected ErrExchangeDifferenceInvoiced, got %v", err)
+	}
+
+	inv3, alloc3 := settleUSD(t, "3", 10000)
+	loss, _ := domain.NewExchangeDifference(alloc3, inv3, paid, usdRate(t, "36.5", issued), usdRate(t, "36", paid))
+	if _, err := domain.NewExchangeDifferenceInvoice("KF-3", "CUST-001", []*domain.ExchangeDifference{loss}, paid, paid); err != domain.ErrNoExchangeGain {
+		t.Errorf("expected ErrNoExchangeGain, got %v", err)
+	}
+	if alloc3.ExchangeDifferenceInvoiceID != "" {
+		t.Errorf("a rejected invoice must not link the allocation")
+	}
+}
+
+func TestAllocation_Reverse_BilledGain(t *testing.T) {
+	issued := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	paid := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	inv := newTestInvoice(t, "INV-1", 100000, "USD")
+	money, _ := domain.NewMoney(40000, "USD")
+	payment := domain.NewPayment("PAY-1", "CUST-001", money, paid)
+	alloc, _ := domain.NewAllocation("AL-1", payment, inv, money)
+	gain, _ := domain.NewExchangeDifference(alloc, inv, paid, usdRate(t, "36.5", issued), usdRate(t, "38.25", paid))
+	kf, err := domain.NewExchangeDifferenceInvoice("KF-1", "CUST-001", []*domain.ExchangeDifference{gain}, paid, paid)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := alloc.Reverse("AL-1-R", payment, inv, "wrong invoice", paid); err != domain.ErrExchangeDifferenceInvoiced {
+		t.Fatalf("expected ErrExchangeDifferenceInvoiced, got %v", err)
+	}
+	if !alloc.IsActive() || inv.PaidAmount.Amount() != 40000 || !payment.AvailableAmount.IsZero() {
+		t.Errorf("a refused reversal must change nothing")
+	}
+
+	if err := alloc.ReleaseExchangeDifference(kf); err != domain.ErrInvalidInvoiceState {
+		t.Errorf("expected ErrInvalidInvoiceState while the kur farkı invoice is open, got %v", err)
+	}
+	if err := kf.Void("kur farkı iptali"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := alloc.ReleaseExchangeDifference(kf); err != nil || gain.IsInvoiced() {
+		t.Fatalf("expected the gain unbilled once the kur farkı invoice is void, got %v", err)
+	}
+	if _, err := alloc.Reverse("AL-1-R", payment, inv, "wrong invoice", paid); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
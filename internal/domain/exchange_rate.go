@@ -0,0 +1,167 @@
+package domain
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateScale is the fixed-point scale of ExchangeRate.Rate (six decimal places).
+const RateScale = 1_000_000
+
+// ExchangeRate says how many units of To one unit of From is worth on Date.
+// Rates are daily: Date is normalised to midnight UTC of the calendar day.
+type ExchangeRate struct {
+	From   string
+	To     string
+	Rate   int64
+	Date   time.Time
+	Source string
+}
+
+const (
+	ExchangeRateSourceManual = "MANUAL"
+	ExchangeRateSourceTCMB   = "TCMB"
+)
+
+func NewExchangeRate(from, to string, rate int64, date time.Time, source string) (*ExchangeRate, error) {
+	if from == to {
+		return nil, ErrInvalidCurrency
+	}
+	for _, code := range []string{from, to} {
+		if _, err := LookupCurrency(code); err != nil {
+			return nil, err
+		}
+	}
+	if rate <= 0 {
+		return nil, ErrInvalidExchangeRate
+	}
+
+	return &ExchangeRate{
+		From:   from,
+		To:     to,
+		Rate:   rate,
+		Date:   RateDay(date),
+		Source: source,
+	}, nil
+}
+
+// RateMaxAgeDays is how many days before the day asked for a rate may date
+// from. No bulletin is published at weekends and on public holidays, and
+// Kurban Bayramı with the days bridged to it can run to nine; a rate older
+// than that means an import is missing, not that the kur held.
+const RateMaxAgeDays = 10
+
+// RateDay returns the calendar day of t, as seen in t's own location, at
+// midnight UTC. Rates are stored and looked up by this key.
+func RateDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// ParseRate reads a decimal rate such as "34.1234" into RateScale units.
+// Digits beyond the sixth decimal place are rejected rather than rounded.
+func ParseRate(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	if intPart == "" || len(fracPart) > 6 || strings.HasPrefix(intPart, "-") || strings.HasPrefix(intPart, "+") {
+		return 0, ErrInvalidExchangeRate
+	}
+	fracPart += strings.Repeat("0", 6-len(fracPart))
+
+	whole, err := strconv.ParseInt(intPart, 10, 64)
+	if err != nil {
+		return 0, ErrInvalidExchangeRate
+	}
+	frac, err := strconv.ParseInt(fracPart, 10, 64)
+	if err != nil || frac < 0 {
+		return 0, ErrInvalidExchangeRate
+	}
+	if whole > (1<<63-1)/RateScale-1 {
+		return 0, ErrInvalidExchangeRate
+	}
+
+	rate := whole*RateScale + frac
+	if rate <= 0 {
+		return 0, ErrInvalidExchangeRate
+	}
+	return rate, nil
+}
+
+// FormatRate renders a RateScale value as a decimal without trailing zeros.
+func FormatRate(rate int64) string {
+	s := strconv.FormatInt(rate/RateScale, 10) + "." + strconv.FormatInt(RateScale+rate%RateScale, 10)[1:]
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+func (r *ExchangeRate) String() string {
+	return FormatRate(r.Rate)
+}
+
+// Converts reports whether the rate can convert between the two currencies,
+// in either direction.
+func (r *ExchangeRate) Converts(a, b string) bool {
+	return (r.From == a && r.To == b) || (r.From == b && r.To == a)
+}
+
+// Convert turns m into the other currency of the pair, rounding half up to
+// the minor unit. Both From->To and To->From are supported. Rates are quoted
+// per major unit, so currencies with different minor units (USD cents, whole
+// JPY) are scaled accordingly.
+func (r *ExchangeRate) Convert(m Money) (Money, error) {
+	amount := big.NewInt(m.amount)
+	rate := big.NewInt(r.Rate)
+	scale := big.NewInt(RateScale)
+	fromUnits := pow10(minorUnits(r.From))
+	toUnits := pow10(minorUnits(r.To))
+
+	var num, den *big.Int
+	var currency string
+	switch m.currency {
+	case r.From:
+		num, den, currency = amount.Mul(amount, rate).Mul(amount, toUnits), scale.Mul(scale, fromUnits), r.To
+	case r.To:
+		num, den, currency = amount.Mul(amount, scale).Mul(amount, fromUnits), rate.Mul(rate, toUnits), r.From
+	default:
+		return Money{}, ErrCurrencyMismatch
+	}
+
+	converted, err := divRound(num, den, RoundHalfUp)
+	if err != nil {
+		return Money{}, err
+	}
+	return NewMoney(converted, currency)
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// settleAtRate decides how much of available (in the source currency) goes to
+// an invoice with the given remaining debt. If the source can cover the whole
+// debt, the debt is settled in full and only the converted equivalent is
+// taken; otherwise all of available is taken. Re-running it with the returned
+// source amount yields the same split, so a booked plan matches its proposal.
+func settleAtRate(available, remaining Money, rate *ExchangeRate) (sourceAmount, invoiceAmount Money, err error) {
+	needed, err := rate.Convert(remaining)
+	if err != nil {
+		return Money{}, Money{}, err
+	}
+	if needed.currency != available.currency {
+		return Money{}, Money{}, ErrCurrencyMismatch
+	}
+	if tooMuch, _ := needed.GreaterThan(available); !tooMuch {
+		return needed, remaining, nil
+	}
+
+	converted, err := rate.Convert(available)
+	if err != nil {
+		return Money{}, Money{}, err
+	}
+	if exceeds, _ := converted.GreaterThan(remaining); exceeds {
+		converted = remaining
+	}
+	return available, converted, nil
+}
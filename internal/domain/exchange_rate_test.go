@@ -0,0 +1,168 @@
+package domain_test
+
+import (
+	"carigo/internal/domain"
+	"testing"
+	"time"
+)
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{"34.1234", 34123400, false},
+		{"1", 1000000, false},
+		{" 0.028571 ", 28571, false},
+		{"35.", 35000000, false},
+		{"0", 0, true},
+		{"-1.5", 0, true},
+		{"1.1234567", 0, true},
+		{"abc", 0, true},
+		{"", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := domain.ParseRate(tt.in)
+		if tt.wantErr {
+			if err != domain.ErrInvalidExchangeRate {
+				t.Errorf("ParseRate(%q): expected ErrInvalidExchangeRate, got %v", tt.in, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseRate(%q) = %d, %v; want %d", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestFormatRate(t *testing.T) {
+	if got := domain.FormatRate(34123400); got != "34.1234" {
+		t.Errorf("expected 34.1234, got %s", got)
+	}
+	if got := domain.FormatRate(2000000); got != "2" {
+		t.Errorf("expected 2, got %s", got)
+	}
+	if got := domain.FormatRate(28571); got != "0.028571" {
+		t.Errorf("expected 0.028571, got %s", got)
+	}
+}
+
+func TestExchangeRate_Convert(t *testing.T) {
+	rate, err := domain.NewExchangeRate("EUR", "TRY", 35123400, time.Now(), domain.ExchangeRateSourceManual)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	eur, _ := domain.NewMoney(1000, "EUR")
+	try, err := rate.Convert(eur)
+	if err != nil || try.Amount() != 35123 || try.Currency() != "TRY" {
+		t.Errorf("expected 35123 TRY, got %d %s (%v)", try.Amount(), try.Currency(), err)
+	}
+
+	back, _ := domain.NewMoney(35124, "TRY")
+	converted, err := rate.Convert(back)
+	if err != nil || converted.Amount() != 1000 || converted.Currency() != "EUR" {
+		t.Errorf("expected 1000 EUR, got %d %s (%v)", converted.Amount(), converted.Currency(), err)
+	}
+
+	half, _ := domain.NewMoney(1, "EUR")
+	halfRate, _ := domain.NewExchangeRate("EUR", "TRY", 500000, time.Now(), domain.ExchangeRateSourceManual)
+	if rounded, _ := halfRate.Convert(half); rounded.Amount() != 1 {
+		t.Errorf("expected 0.5 to round up to 1, got %d", rounded.Amount())
+	}
+
+	usd, _ := domain.NewMoney(100, "USD")
+	if _, err := rate.Convert(usd); err != domain.ErrCurrencyMismatch {
+		t.Errorf("expected ErrCurrencyMismatch, got %v", err)
+	}
+	if _, err := domain.NewExchangeRate("EUR", "EUR", 1, time.Now(), ""); err != domain.ErrInvalidCurrency {
+		t.Errorf("expected ErrInvalidCurrency for same-currency pair, got %v", err)
+	}
+}
+
+func TestNewAllocationAtRate(t *testing.T) {
+	rate, _ := domain.NewExchangeRate("EUR", "TRY", 35000000, time.Now(), domain.ExchangeRateSourceManual)
+
+	t.Run("partial", func(t *testing.T) {
+		inv := newTestInvoice(t, "INV-001", 70000, "TRY")
+		amount, _ := domain.NewMoney(1000, "EUR")
+		payment := domain.NewPayment("PAY-001", "CUST-001", amount, time.Now())
+
+		alloc, err := domain.NewAllocationAtRate("AL-1", payment, inv, amount, rate)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if alloc.Amount.Amount() != 1000 || alloc.InvoiceAmount.Amount() != 35000 || alloc.ExchangeRate != rate {
+			t.Errorf("unexpected allocation: %d EUR -> %d TRY", alloc.Amount.Amount(), alloc.InvoiceAmount.Amount())
+		}
+		if !payment.AvailableAmount.IsZero() || inv.PaidAmount.Amount() != 35000 {
+			t.Errorf("expected payment used up and 35000 paid, got %d / %d", payment.AvailableAmount.Amount(), inv.PaidAmount.Amount())
+		}
+	})
+
+	t.Run("settles only what the invoice needs", func(t *testing.T) {
+		inv := newTestInvoice(t, "INV-001", 10001, "TRY")
+		amount, _ := domain.NewMoney(1000, "EUR")
+		payment := domain.NewPayment("PAY-001", "CUST-001", amount, time.Now())
+
+		alloc, err := domain.NewAllocationAtRate("AL-1", payment, inv, amount, rate)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if alloc.InvoiceAmount.Amount() != 10001 || alloc.Amount.Amount() != 286 {
+			t.Errorf("expected 286 EUR -> 10001 TRY, got %d -> %d", alloc.Amount.Amount(), alloc.InvoiceAmount.Amount())
+		}
+		if inv.Status != domain.InvoiceStatusPaid || payment.AvailableAmount.Amount() != 714 {
+			t.Errorf("expected invoice PAID and 714 EUR left, got %s / %d", inv.Status, payment.AvailableAmount.Amount())
+		}
+
+		reversal, err := alloc.Reverse("REV-1", payment, inv, "yanlış kur", time.Now())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if reversal.InvoiceAmount.Amount() != 10001 || !payment.AvailableAmount.Equals(amount) || !inv.PaidAmount.IsZero() {
+			t.Errorf("expected both sides restored, got %d EUR available and %d TRY paid", payment.AvailableAmount.Amount(), inv.PaidAmount.Amount())
+		}
+	})
+
+	t.Run("rate must cover the pair", func(t *testing.T) {
+		inv := newTestInvoice(t, "INV-001", 1000, "USD")
+		amount, _ := domain.NewMoney(1000, "EUR")
+		payment := domain.NewPayment("PAY-001", "CUST-001", amount, time.Now())
+
+		if _, err := domain.NewAllocationAtRate("AL-1", payment, inv, amount, rate); err != domain.ErrCurrencyMismatch {
+			t.Errorf("expected ErrCurrencyMismatch, got %v", err)
+		}
+	})
+}
+
+func TestNewAllocationPlan_CrossCurrency(t *testing.T) {
+	rate, _ := domain.NewExchangeRate("EUR", "TRY", 35000000, time.Now(), domain.ExchangeRateSourceManual)
+	invoices := []*domain.Invoice{
+		newTestInvoice(t, "INV-001", 17500, "TRY"),
+		newTestInvoice(t, "INV-002", 1000, "USD"),
+		newTestInvoice(t, "INV-003", 70000, "TRY"),
+	}
+	amount, _ := domain.NewMoney(1000, "EUR")
+
+	plan, err := domain.NewAllocationPlan("PLAN-1", "CUST-001", amount, time.Now(), invoices, fifo(t), []*domain.ExchangeRate{rate})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(plan.Lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(plan.Lines))
+	}
+	first, second := plan.Lines[0], plan.Lines[1]
+	if first.Amount.Amount() != 500 || first.InvoiceAmount.Amount() != 17500 || !first.RemainingAfter.IsZero() || first.Rate != rate {
+		t.Errorf("unexpected first line: %+v", first)
+	}
+	if second.InvoiceID != "INV-003" || second.Amount.Amount() != 500 || second.RemainingAfter.Amount() != 52500 {
+		t.Errorf("unexpected second line: %+v", second)
+	}
+	if !plan.Unallocated.IsZero() {
+		t.Errorf("expected nothing unallocated, got %d", plan.Unallocated.Amount())
+	}
+}
@@ -0,0 +1,68 @@
+package domain
+
+import "time"
+
+// turkishFixedHolidays are the public holidays on the same day every year.
+var turkishFixedHolidays = []struct {
+	month time.Month
+	day   int
+}{
+	{time.January, 1},  // Yılbaşı
+	{time.April, 23},   // Ulusal Egemenlik ve Çocuk Bayramı
+	{time.May, 1},      // Emek ve Dayanışma Günü
+	{time.May, 19},     // Atatürk'ü Anma, Gençlik ve Spor Bayramı
+	{time.July, 15},    // Demokrasi ve Millî Birlik Günü
+	{time.August, 30},  // Zafer Bayramı
+	{time.October, 29}, // Cumhuriyet Bayramı
+}
+
+// turkishReligiousHolidays are the first days of Ramazan Bayramı (3 days)
+// and Kurban Bayramı (4 days), which follow the lunar calendar. Years that
+// are not listed only get the fixed holidays; add them as they are announced.
+var turkishReligiousHolidays = map[int][2]time.Time{
+	2023: {calendarDay(2023, time.April, 21), calendarDay(2023, time.June, 28)},
+	2024: {calendarDay(2024, time.April, 10), calendarDay(2024, time.June, 16)},
+	2025: {calendarDay(2025, time.March, 30), calendarDay(2025, time.June, 6)},
+	2026: {calendarDay(2026, time.March, 20), calendarDay(2026, time.May, 27)},
+	2027: {calendarDay(2027, time.March, 9), calendarDay(2027, time.May, 16)},
+}
+
+func calendarDay(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// IsTurkishPublicHoliday reports whether day is a full-day public holiday in
+// Turkey. Half-day arife afternoons are working days.
+func IsTurkishPublicHoliday(day time.Time) bool {
+	d := RateDay(day)
+	for _, h := range turkishFixedHolidays {
+		if d.Month() == h.month && d.Day() == h.day {
+			return true
+		}
+	}
+	bayrams, ok := turkishReligiousHolidays[d.Year()]
+	if !ok {
+		return false
+	}
+	ramazan, kurban := bayrams[0], bayrams[1]
+	return !d.Before(ramazan) && d.Before(ramazan.AddDate(0, 0, 3)) ||
+		!d.Before(kurban) && d.Before(kurban.AddDate(0, 0, 4))
+}
+
+// IsBusinessDay reports whether day is neither a weekend nor a public holiday.
+func IsBusinessDay(day time.Time) bool {
+	switch RateDay(day).Weekday() {
+	case time.Saturday, time.Sunday:
+		return false
+	}
+	return !IsTurkishPublicHoliday(day)
+}
+
+// NextBusinessDay is day itself if it is a business day, or else the first
+// business day after it.
+func NextBusinessDay(day time.Time) time.Time {
+	for !IsBusinessDay(day) {
+		day = day.AddDate(0, 0, 1)
+	}
+	return day
+}
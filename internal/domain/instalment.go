@@ -0,0 +1,139 @@
+package domain
+
+import (
+	"time"
+)
+
+// Instalment is one scheduled part of an invoice (taksit).
+type Instalment struct {
+	DueDate time.Time
+	Amount  Money
+}
+
+// InstalmentStatus is an instalment together with the part of the invoice's
+// paid amount that covers it. Payments settle instalments in schedule order.
+type InstalmentStatus struct {
+	Instalment
+	// Number is the 1-based position in the schedule.
+	Number    int
+	Paid      Money
+	Remaining Money
+}
+
+// IsOverdue reports whether the instalment is still open after its due day.
+func (s InstalmentStatus) IsOverdue(asOf time.Time) bool {
+	return !s.Remaining.IsZero() && RateDay(s.DueDate).Before(RateDay(asOf))
+}
+
+// NewEvenInstalments splits total into count monthly instalments, the first
+// due on firstDueDate. The kuruş that do not divide evenly go to the earliest
+// instalments, one each, so the same input always yields the same schedule.
+func NewEvenInstalments(total Money, count int, firstDueDate time.Time) ([]Instalment, error) {
+	if count < 1 {
+		return nil, ErrInvalidInstalments
+	}
+	ratios := make([]int, count)
+	for i := range ratios {
+		ratios[i] = 1
+	}
+	parts, err := total.Allocate(ratios...)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule := make([]Instalment, count)
+	for i, part := range parts {
+		schedule[i] = Instalment{DueDate: addMonths(firstDueDate, i), Amount: part}
+	}
+	return schedule, nil
+}
+
+// SetInstalments replaces the invoice's payment schedule. The instalments must
+// be positive, in the invoice currency, strictly ordered by due date and add
+// up to TotalAmount. DueDate becomes the due date of the last instalment.
+func (i *Invoice) SetInstalments(schedule []Instalment) error {
+	if i.Status == InvoiceStatusVoid {
+		return ErrInvalidInvoiceState
+	}
+	if len(schedule) == 0 {
+		return ErrInvalidInstalments
+	}
+
+	sum := Money{currency: i.TotalAmount.currency}
+	for n, inst := range schedule {
+		if inst.Amount.currency != i.TotalAmount.currency {
+			return ErrCurrencyMismatch
+		}
+		if inst.Amount.amount <= 0 {
+			return ErrInvalidInstalments
+		}
+		if n > 0 && !RateDay(inst.DueDate).After(RateDay(schedule[n-1].DueDate)) {
+			return ErrInvalidInstalments
+		}
+		sum, _ = sum.Add(inst.Amount)
+	}
+	if !sum.Equals(i.TotalAmount) {
+		return ErrInvalidInstalments
+	}
+
+	i.Instalments = schedule
+	i.DueDate = schedule[len(schedule)-1].DueDate
+	i.UpdatedAt = time.Now()
+	return nil
+}
+
+// HasInstalments reports whether the invoice is payable in more than one part.
+func (i *Invoice) HasInstalments() bool {
+	return len(i.Instalments) > 0
+}
+
+// InstalmentStatuses spreads PaidAmount over the schedule, earliest
+// instalment first. An invoice without a schedule is a single instalment of
+// TotalAmount due on DueDate.
+func (i *Invoice) InstalmentStatuses() []InstalmentStatus {
+	schedule := i.Instalments
+	if len(schedule) == 0 {
+		schedule = []Instalment{{DueDate: i.DueDate, Amount: i.TotalAmount}}
+	}
+
+	paid := i.PaidAmount
+	statuses := make([]InstalmentStatus, len(schedule))
+	for n, inst := range schedule {
+		covered := inst.Amount
+		if moreThanPaid, _ := covered.GreaterThan(paid); moreThanPaid {
+			covered = paid
+		}
+		paid, _ = paid.Subtract(covered)
+		remaining, _ := inst.Amount.Subtract(covered)
+		statuses[n] = InstalmentStatus{
+			Instalment: inst,
+			Number:     n + 1,
+			Paid:       covered,
+			Remaining:  remaining,
+		}
+	}
+	return statuses
+}
+
+// NextDueDate is the due date of the earliest instalment that is not fully
+// paid, or DueDate once everything is settled.
+func (i *Invoice) NextDueDate() time.Time {
+	for _, s := range i.InstalmentStatuses() {
+		if !s.Remaining.IsZero() {
+			return s.DueDate
+		}
+	}
+	return i.DueDate
+}
+
+// addMonths moves t by n calendar months, keeping the day of month where
+// possible and falling back to the month's last day (31 Jan + 1 = 28/29 Feb).
+func addMonths(t time.Time, n int) time.Time {
+	y, m, d := t.Date()
+	firstOfTarget := time.Date(y, m+time.Month(n), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	lastDay := firstOfTarget.AddDate(0, 1, -1).Day()
+	if d > lastDay {
+		d = lastDay
+	}
+	return firstOfTarget.AddDate(0, 0, d-1)
+}
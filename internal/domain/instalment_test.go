@@ -0,0 +1,154 @@
+package domain_test
+
+import (
+	"carigo/internal/domain"
+	"testing"
+	"time"
+)
+
+func TestNewEvenInstalments(t *testing.T) {
+	first := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	schedule, err := domain.NewEvenInstalments(tryMoney(10000), 3, first)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantAmounts := []int64{3334, 3333, 3333}
+	wantDates := []time.Time{
+		first,
+		time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC),
+	}
+	for i, inst := range schedule {
+		if inst.Amount.Amount() != wantAmounts[i] {
+			t.Errorf("instalment %d: expected %d, got %d", i+1, wantAmounts[i], inst.Amount.Amount())
+		}
+		if !inst.DueDate.Equal(wantDates[i]) {
+			t.Errorf("instalment %d: expected due %s, got %s", i+1, wantDates[i].Format("2006-01-02"), inst.DueDate.Format("2006-01-02"))
+		}
+	}
+
+	if _, err := domain.NewEvenInstalments(tryMoney(10000), 0, first); err != domain.ErrInvalidInstalments {
+		t.Errorf("expected ErrInvalidInstalments, got %v", err)
+	}
+}
+
+func TestInvoice_SetInstalments(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 1, d, 0, 0, 0, 0, time.UTC) }
+	usd, _ := domain.NewMoney(500, "USD")
+
+	tests := []struct {
+		name     string
+		schedule []domain.Instalment
+		want     error
+	}{
+		{"valid", []domain.Instalment{{DueDate: day(10), Amount: tryMoney(400)}, {DueDate: day(20), Amount: tryMoney(600)}}, nil},
+		{"empty", nil, domain.ErrInvalidInstalments},
+		{"short of total", []domain.Instalment{{DueDate: day(10), Amount: tryMoney(400)}, {DueDate: day(20), Amount: tryMoney(500)}}, domain.ErrInvalidInstalments},
+		{"same due day", []domain.Instalment{{DueDate: day(10), Amount: tryMoney(400)}, {DueDate: day(10).Add(time.Hour), Amount: tryMoney(600)}}, domain.ErrInvalidInstalments},
+		{"out of order", []domain.Instalment{{DueDate: day(20), Amount: tryMoney(400)}, {DueDate: day(10), Amount: tryMoney(600)}}, domain.ErrInvalidInstalments},
+		{"zero instalment", []domain.Instalment{{DueDate: day(10), Amount: tryMoney(0)}, {DueDate: day(20), Amount: tryMoney(1000)}}, domain.ErrInvalidInstalments},
+		{"other currency", []domain.Instalment{{DueDate: day(10), Amount: usd}, {DueDate: day(20), Amount: tryMoney(500)}}, domain.ErrCurrencyMismatch},
+	}
+
+	for _, tt := range tests {
+		inv := newTestInvoice(t, "INV-001", 1000, "TRY")
+		err := inv.SetInstalments(tt.schedule)
+		if err != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, err)
+			continue
+		}
+		if err == nil && !inv.DueDate.Equal(day(20)) {
+			t.Errorf("%s: DueDate must move to the last instalment, got %s", tt.name, inv.DueDate)
+		}
+		if err != nil && inv.HasInstalments() {
+			t.Errorf("%s: a rejected schedule must not be kept", tt.name)
+		}
+	}
+}
+
+func TestInvoice_InstalmentStatuses(t *testing.T) {
+	first := time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+	inv := newTestInvoice(t, "INV-001", 10000, "TRY")
+	schedule, _ := domain.NewEvenInstalments(inv.TotalAmount, 3, first)
+	if err := inv.SetInstalments(schedule); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := inv.AllocatePayment(tryMoney(5000)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	statuses := inv.InstalmentStatuses()
+	wantPaid := []int64{3334, 1666, 0}
+	wantRemaining := []int64{0, 1667, 3333}
+	for i, st := range statuses {
+		if st.Number != i+1 || st.Paid.Amount() != wantPaid[i] || st.Remaining.Amount() != wantRemaining[i] {
+			t.Errorf("instalment %d: expected paid %d remaining %d, got #%d paid %d remaining %d",
+				i+1, wantPaid[i], wantRemaining[i], st.Number, st.Paid.Amount(), st.Remaining.Amount())
+		}
+	}
+
+	asOf := time.Date(2026, 2, 20, 12, 0, 0, 0, time.UTC)
+	if statuses[0].IsOverdue(asOf) {
+		t.Errorf("a paid instalment is never overdue")
+	}
+	if !statuses[1].IsOverdue(asOf) {
+		t.Errorf("the second instalment is past due and partly open, expected overdue")
+	}
+	if statuses[2].IsOverdue(asOf) {
+		t.Errorf("the third instalment is not due yet")
+	}
+	if statuses[1].IsOverdue(statuses[1].DueDate.Add(10 * time.Hour)) {
+		t.Errorf("an instalment is not overdue on its due day")
+	}
+
+	if got := inv.NextDueDate(); !got.Equal(statuses[1].DueDate) {
+		t.Errorf("expected next due date %s, got %s", statuses[1].DueDate, got)
+	}
+
+	plain := newTestInvoice(t, "INV-002", 1000, "TRY")
+	if s := plain.InstalmentStatuses(); len(s) != 1 || !s[0].Amount.Equals(plain.TotalAmount) || !s[0].DueDate.Equal(plain.DueDate) {
+		t.Errorf("an invoice without a schedule must be one instalment, got %+v", s)
+	}
+}
+
+func TestNewAllocationPlan_FIFOByInstalment(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// INV-A: 3 x 1000 due 10 Jan, 10 Feb, 10 Mar. INV-B: 1500 due 20 Jan.
+	a, _ := domain.NewInvoice("INV-A", "CUST-001", tryMoney(3000), base, base)
+	schedule, _ := domain.NewEvenInstalments(a.TotalAmount, 3, base.AddDate(0, 0, 9))
+	if err := a.SetInstalments(schedule); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, _ := domain.NewInvoice("INV-B", "CUST-001", tryMoney(1500), base, base.AddDate(0, 0, 19))
+
+	plan, err := domain.NewAllocationPlan("PLAN-1", "CUST-001", tryMoney(3000), base, []*domain.Invoice{a, b}, fifo(t), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 1000 for A's first instalment, 1500 for B, then 500 towards A's second.
+	if len(plan.Lines) != 2 {
+		t.Fatalf("expected one line per invoice, got %d", len(plan.Lines))
+	}
+	if plan.Lines[0].InvoiceID != "INV-A" || plan.Lines[0].Amount.Amount() != 1500 || plan.Lines[0].RemainingAfter.Amount() != 1500 {
+		t.Errorf("unexpected line for INV-A: %+v", plan.Lines[0])
+	}
+	if plan.Lines[1].InvoiceID != "INV-B" || plan.Lines[1].Amount.Amount() != 1500 || !plan.Lines[1].RemainingAfter.IsZero() {
+		t.Errorf("unexpected line for INV-B: %+v", plan.Lines[1])
+	}
+	if !plan.Unallocated.IsZero() {
+		t.Errorf("expected nothing unallocated, got %d", plan.Unallocated.Amount())
+	}
+
+	// Once A's first instalment is paid, B (due 20 Jan) comes before A (next due 10 Feb).
+	if err := a.AllocatePayment(tryMoney(1000)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ordered := fifo(t).Order([]*domain.Invoice{a, b}, tryMoney(100))
+	if ordered[0].ID != "INV-B" {
+		t.Errorf("expected INV-B first by next instalment due date, got %s", ordered[0].ID)
+	}
+}
@@ -13,30 +13,55 @@ const (
 	InvoiceStatusVoid    InvoiceStatus = "VOID"
 )
 
+// InvoiceKind tells sales invoices apart from invoices CariGo issues itself
+// to settle differences arising from other documents.
+type InvoiceKind string
+
+const (
+	InvoiceKindSales InvoiceKind = "SALES"
+	// InvoiceKindExchangeDifference bills a realized exchange gain (kur farkı).
+	InvoiceKindExchangeDifference InvoiceKind = "EXCHANGE_DIFFERENCE"
+	// InvoiceKindLateInterest bills late-payment interest (vade farkı).
+	InvoiceKindLateInterest InvoiceKind = "LATE_INTEREST"
+)
+
 type InvoiceID string
 
 type Invoice struct {
-	ID          InvoiceID
-	CustomerID  CustomerID
+	ID         InvoiceID
+	CustomerID CustomerID
+	Kind       InvoiceKind
+	// Lines is empty for invoices issued as a single amount.
+	Lines []InvoiceLine
+	// Instalments is empty for invoices payable in one go on DueDate.
+	Instalments []Instalment
+	// Terms decide the early-payment discount, if any.
+	Terms       PaymentTerms
 	TotalAmount Money
 	PaidAmount  Money
 	IssueDate   time.Time
 	DueDate     time.Time
 	Status      InvoiceStatus
+	VoidedAt    *time.Time
+	VoidReason  string
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
+	// InterestInvoicedThrough is the day up to which late interest on the
+	// invoice has been billed; zero if it never was.
+	InterestInvoicedThrough time.Time
 }
 
 func NewInvoice(id InvoiceID, customerID CustomerID, total Money, issueDate, dueDate time.Time) (*Invoice, error) {
 	if total.IsZero() || total.amount < 0 {
 		return nil, ErrNegativeAmount
 	}
-	
+
 	zeroMoney, _ := NewMoney(0, total.Currency())
 
 	return &Invoice{
 		ID:          id,
 		CustomerID:  customerID,
+		Kind:        InvoiceKindSales,
 		TotalAmount: total,
 		PaidAmount:  zeroMoney,
 		IssueDate:   issueDate,
@@ -53,6 +78,76 @@ func (i *Invoice) RemainingAmount() Money {
 }
 
 func (i *Invoice) AllocatePayment(amount Money) error {
+	if err := i.canAllocate(amount); err != nil {
+		return err
+	}
+
+	newPaid, err := i.PaidAmount.Add(amount)
+	if err != nil {
+		return err
+	}
+	i.PaidAmount = newPaid
+	i.updateStatus()
+	i.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// ReleasePayment is the inverse of AllocatePayment: it takes a previously
+// allocated amount off the invoice, moving PAID back to PARTIAL or OPEN.
+func (i *Invoice) ReleasePayment(amount Money) error {
+	if err := i.canRelease(amount); err != nil {
+		return err
+	}
+
+	newPaid, err := i.PaidAmount.Subtract(amount)
+	if err != nil {
+		return err
+	}
+	i.PaidAmount = newPaid
+	i.updateStatus()
+	i.UpdatedAt = time.Now()
+
+	return nil
+}
+
+func (i *Invoice) canRelease(amount Money) error {
+	if i.Status == InvoiceStatusVoid {
+		return ErrInvalidInvoiceState
+	}
+	if amount.currency != i.PaidAmount.currency {
+		return ErrCurrencyMismatch
+	}
+	if exceedsPaid, _ := amount.GreaterThan(i.PaidAmount); exceedsPaid {
+		return ErrInvalidAllocationReversal
+	}
+	return nil
+}
+
+// Void cancels the invoice. Only an invoice without allocations can be voided:
+// a PARTIAL or PAID invoice must have its payments released first.
+func (i *Invoice) Void(reason string) error {
+	switch i.Status {
+	case InvoiceStatusOpen:
+	case InvoiceStatusPartial, InvoiceStatusPaid:
+		return ErrInvoiceHasAllocations
+	default:
+		return ErrInvalidInvoiceState
+	}
+	if !i.PaidAmount.IsZero() {
+		return ErrInvoiceHasAllocations
+	}
+
+	now := time.Now()
+	i.Status = InvoiceStatusVoid
+	i.VoidedAt = &now
+	i.VoidReason = reason
+	i.UpdatedAt = now
+	return nil
+}
+
+// canAllocate checks whether amount could be allocated without changing state.
+func (i *Invoice) canAllocate(amount Money) error {
 	if i.Status == InvoiceStatusPaid || i.Status == InvoiceStatusVoid {
 		return ErrInvoiceAlreadyPaid
 	}
@@ -66,15 +161,6 @@ func (i *Invoice) AllocatePayment(amount Money) error {
 	if isOverpayment {
 		return ErrOverPaymentNotAllowed
 	}
-
-	newPaid, err := i.PaidAmount.Add(amount)
-	if err != nil {
-		return err
-	}
-	i.PaidAmount = newPaid
-	i.updateStatus()
-	i.UpdatedAt = time.Now()
-	
 	return nil
 }
 
@@ -0,0 +1,216 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// QuantityScale is the fixed-point scale of InvoiceLine.Quantity (three
+// decimal places, e.g. 2.5 kg is 2500).
+const QuantityScale = 1000
+
+// VATRate is a KDV rate in percent.
+type VATRate int
+
+const (
+	VATRate0  VATRate = 0
+	VATRate1  VATRate = 1
+	VATRate10 VATRate = 10
+	VATRate20 VATRate = 20
+)
+
+func (r VATRate) valid() bool {
+	switch r {
+	case VATRate0, VATRate1, VATRate10, VATRate20:
+		return true
+	}
+	return false
+}
+
+// WithholdingRatio is the share of the VAT withheld by the buyer (KDV
+// tevkifatı), in tenths: 9 means 9/10. Zero means no withholding.
+type WithholdingRatio int
+
+// ParseWithholdingRatio reads ratios written as "5/10". An empty string or "0"
+// means no withholding.
+func ParseWithholdingRatio(s string) (WithholdingRatio, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "0" {
+		return 0, nil
+	}
+	num, den, ok := strings.Cut(s, "/")
+	if !ok || strings.TrimSpace(den) != "10" {
+		return 0, ErrInvalidWithholding
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(num))
+	if err != nil || n < 0 || n > 10 {
+		return 0, ErrInvalidWithholding
+	}
+	return WithholdingRatio(n), nil
+}
+
+func (w WithholdingRatio) String() string {
+	if w == 0 {
+		return ""
+	}
+	return strconv.Itoa(int(w)) + "/10"
+}
+
+// InvoiceLine is one item sold on an invoice. Discount is an amount taken off
+// the line before VAT. NetAmount (the VAT base), VATAmount and WithheldVAT are
+// derived by NewInvoiceLine, each rounded half up to the minor unit.
+type InvoiceLine struct {
+	Description string
+	Quantity    int64
+	UnitPrice   Money
+	Discount    Money
+	VATRate     VATRate
+	Withholding WithholdingRatio
+	NetAmount   Money
+	VATAmount   Money
+	WithheldVAT Money
+}
+
+func NewInvoiceLine(description string, quantity int64, unitPrice, discount Money, vat VATRate, withholding WithholdingRatio) (InvoiceLine, error) {
+	if strings.TrimSpace(description) == "" || quantity <= 0 {
+		return InvoiceLine{}, ErrInvalidInvoiceLine
+	}
+	if !vat.valid() {
+		return InvoiceLine{}, ErrInvalidVATRate
+	}
+	if withholding < 0 || withholding > 10 {
+		return InvoiceLine{}, ErrInvalidWithholding
+	}
+	if discount.currency != unitPrice.currency {
+		return InvoiceLine{}, ErrCurrencyMismatch
+	}
+
+	gross, err := unitPrice.Multiply(quantity*(RateScale/QuantityScale), RoundHalfUp)
+	if err != nil {
+		return InvoiceLine{}, err
+	}
+	if tooMuch, _ := discount.GreaterThan(gross); tooMuch {
+		return InvoiceLine{}, ErrInvalidInvoiceLine
+	}
+	net, err := gross.Subtract(discount)
+	if err != nil {
+		return InvoiceLine{}, err
+	}
+	vatAmount, err := net.Multiply(int64(vat)*(RateScale/100), RoundHalfUp)
+	if err != nil {
+		return InvoiceLine{}, err
+	}
+	withheld, err := vatAmount.Multiply(int64(withholding)*(RateScale/10), RoundHalfUp)
+	if err != nil {
+		return InvoiceLine{}, err
+	}
+
+	return InvoiceLine{
+		Description: description,
+		Quantity:    quantity,
+		UnitPrice:   unitPrice,
+		Discount:    discount,
+		VATRate:     vat,
+		Withholding: withholding,
+		NetAmount:   net,
+		VATAmount:   vatAmount,
+		WithheldVAT: withheld,
+	}, nil
+}
+
+// ParseQuantity reads a decimal quantity such as "2.5" or "2,5" into
+// QuantityScale units.
+func ParseQuantity(s string) (int64, error) {
+	s = strings.TrimSpace(strings.Replace(s, ",", ".", 1))
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" || len(frac) > 3 || !isDigits(whole) || !isDigits(frac) {
+		return 0, ErrInvalidInvoiceLine
+	}
+	q, err := strconv.ParseInt(whole+frac+strings.Repeat("0", 3-len(frac)), 10, 64)
+	if err != nil || q <= 0 {
+		return 0, ErrInvalidInvoiceLine
+	}
+	return q, nil
+}
+
+// FormatQuantity renders a QuantityScale value without trailing zeros.
+func FormatQuantity(q int64) string {
+	s := strconv.FormatInt(q/QuantityScale, 10)
+	if frac := q % QuantityScale; frac != 0 {
+		s += "." + strings.TrimRight(strconv.FormatInt(QuantityScale+frac, 10)[1:], "0")
+	}
+	return s
+}
+
+// InvoiceTotals sums an invoice's lines. Total is Subtotal plus VAT; Payable
+// is what the customer owes after withholding and is the invoice TotalAmount.
+type InvoiceTotals struct {
+	Subtotal    Money
+	VAT         Money
+	Withholding Money
+	Total       Money
+	Payable     Money
+}
+
+func sumLines(lines []InvoiceLine) (InvoiceTotals, error) {
+	if len(lines) == 0 {
+		return InvoiceTotals{}, ErrInvalidInvoiceLine
+	}
+	zero := Money{currency: lines[0].UnitPrice.currency}
+	t := InvoiceTotals{Subtotal: zero, VAT: zero, Withholding: zero}
+
+	var err error
+	for _, l := range lines {
+		if t.Subtotal, err = t.Subtotal.Add(l.NetAmount); err != nil {
+			return InvoiceTotals{}, err
+		}
+		if t.VAT, err = t.VAT.Add(l.VATAmount); err != nil {
+			return InvoiceTotals{}, err
+		}
+		if t.Withholding, err = t.Withholding.Add(l.WithheldVAT); err != nil {
+			return InvoiceTotals{}, err
+		}
+	}
+	if t.Total, err = t.Subtotal.Add(t.VAT); err != nil {
+		return InvoiceTotals{}, err
+	}
+	if t.Payable, err = t.Total.Subtract(t.Withholding); err != nil {
+		return InvoiceTotals{}, err
+	}
+	return t, nil
+}
+
+// NewInvoiceWithLines creates an invoice whose TotalAmount is the payable
+// amount derived from its lines. All lines must share one currency.
+func NewInvoiceWithLines(id InvoiceID, customerID CustomerID, lines []InvoiceLine, issueDate, dueDate time.Time) (*Invoice, error) {
+	totals, err := sumLines(lines)
+	if err != nil {
+		return nil, err
+	}
+	inv, err := NewInvoice(id, customerID, totals.Payable, issueDate, dueDate)
+	if err != nil {
+		return nil, err
+	}
+	inv.Lines = lines
+	return inv, nil
+}
+
+// Totals breaks the invoice amount down by VAT and withholding. An invoice
+// without lines, issued as a single amount, has no VAT breakdown: all of
+// TotalAmount is reported as Subtotal.
+func (i *Invoice) Totals() InvoiceTotals {
+	if len(i.Lines) > 0 {
+		if t, err := sumLines(i.Lines); err == nil {
+			return t
+		}
+	}
+	zero := Money{currency: i.TotalAmount.currency}
+	return InvoiceTotals{
+		Subtotal:    i.TotalAmount,
+		VAT:         zero,
+		Withholding: zero,
+		Total:       i.TotalAmount,
+		Payable:     i.TotalAmount,
+	}
+}
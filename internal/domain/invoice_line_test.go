@@ -0,0 +1,176 @@
+package domain_test
+
+import (
+	"carigo/internal/domain"
+	"testing"
+	"time"
+)
+
+func tryMoney(amount int64) domain.Money {
+	m, _ := domain.NewMoney(amount, "TRY")
+	return m
+}
+
+func TestNewInvoiceLine(t *testing.T) {
+	line, err := domain.NewInvoiceLine("Danışmanlık", 3000, tryMoney(123456), tryMoney(1000), domain.VATRate20, 9)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if line.NetAmount.Amount() != 369368 {
+		t.Errorf("expected net 369368, got %d", line.NetAmount.Amount())
+	}
+	if line.VATAmount.Amount() != 73874 {
+		t.Errorf("expected VAT 73874, got %d", line.VATAmount.Amount())
+	}
+	if line.WithheldVAT.Amount() != 66487 {
+		t.Errorf("expected withheld VAT 66487, got %d", line.WithheldVAT.Amount())
+	}
+
+	fractional, err := domain.NewInvoiceLine("Kablo", 2500, tryMoney(999), tryMoney(0), domain.VATRate10, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fractional.NetAmount.Amount() != 2498 || fractional.VATAmount.Amount() != 250 || !fractional.WithheldVAT.IsZero() {
+		t.Errorf("expected 2498 net, 250 VAT, no withholding; got %d, %d, %d",
+			fractional.NetAmount.Amount(), fractional.VATAmount.Amount(), fractional.WithheldVAT.Amount())
+	}
+}
+
+func TestNewInvoiceLine_Invalid(t *testing.T) {
+	usd, _ := domain.NewMoney(0, "USD")
+	tests := []struct {
+		name        string
+		description string
+		quantity    int64
+		discount    domain.Money
+		vat         domain.VATRate
+		withholding domain.WithholdingRatio
+		want        error
+	}{
+		{"no description", " ", 1000, tryMoney(0), domain.VATRate20, 0, domain.ErrInvalidInvoiceLine},
+		{"zero quantity", "x", 0, tryMoney(0), domain.VATRate20, 0, domain.ErrInvalidInvoiceLine},
+		{"unknown VAT rate", "x", 1000, tryMoney(0), 18, 0, domain.ErrInvalidVATRate},
+		{"withholding above 10/10", "x", 1000, tryMoney(0), domain.VATRate20, 11, domain.ErrInvalidWithholding},
+		{"discount above gross", "x", 1000, tryMoney(1001), domain.VATRate20, 0, domain.ErrInvalidInvoiceLine},
+		{"discount in other currency", "x", 1000, usd, domain.VATRate20, 0, domain.ErrCurrencyMismatch},
+	}
+
+	for _, tt := range tests {
+		if _, err := domain.NewInvoiceLine(tt.description, tt.quantity, tryMoney(1000), tt.discount, tt.vat, tt.withholding); err != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, err)
+		}
+	}
+}
+
+func TestParseWithholdingRatio(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    domain.WithholdingRatio
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"0", 0, false},
+		{"5/10", 5, false},
+		{" 9/10 ", 9, false},
+		{"10/10", 10, false},
+		{"11/10", 0, true},
+		{"1/2", 0, true},
+		{"9", 0, true},
+		{"x/10", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := domain.ParseWithholdingRatio(tt.in)
+		if tt.wantErr {
+			if err != domain.ErrInvalidWithholding {
+				t.Errorf("ParseWithholdingRatio(%q): expected ErrInvalidWithholding, got %v", tt.in, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseWithholdingRatio(%q) = %d, %v; want %d", tt.in, got, err, tt.want)
+		}
+	}
+	if got := domain.WithholdingRatio(9).String(); got != "9/10" {
+		t.Errorf("expected 9/10, got %s", got)
+	}
+}
+
+func TestParseQuantity(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{"1", 1000, false},
+		{"2.5", 2500, false},
+		{"2,5", 2500, false},
+		{"0.125", 125, false},
+		{"0", 0, true},
+		{"1.2345", 0, true},
+		{"-1", 0, true},
+		{"", 0, true},
+		{"1.2.3", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := domain.ParseQuantity(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseQuantity(%q): expected an error, got %d", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseQuantity(%q) = %d, %v; want %d", tt.in, got, err, tt.want)
+		}
+	}
+
+	for q, want := range map[int64]string{1000: "1", 2500: "2.5", 125: "0.125", 10010: "10.01"} {
+		if got := domain.FormatQuantity(q); got != want {
+			t.Errorf("FormatQuantity(%d) = %s, want %s", q, got, want)
+		}
+	}
+}
+
+func TestNewInvoiceWithLines(t *testing.T) {
+	first, _ := domain.NewInvoiceLine("Danışmanlık", 3000, tryMoney(123456), tryMoney(1000), domain.VATRate20, 9)
+	second, _ := domain.NewInvoiceLine("Kablo", 2500, tryMoney(999), tryMoney(0), domain.VATRate10, 0)
+
+	inv, err := domain.NewInvoiceWithLines("INV-001", "CUST-001", []domain.InvoiceLine{first, second}, time.Now(), time.Now().Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	totals := inv.Totals()
+	if totals.Subtotal.Amount() != 371866 || totals.VAT.Amount() != 74124 || totals.Withholding.Amount() != 66487 {
+		t.Errorf("unexpected totals: subtotal %d, VAT %d, withholding %d",
+			totals.Subtotal.Amount(), totals.VAT.Amount(), totals.Withholding.Amount())
+	}
+	if totals.Total.Amount() != 445990 || totals.Payable.Amount() != 379503 {
+		t.Errorf("expected total 445990 and payable 379503, got %d and %d", totals.Total.Amount(), totals.Payable.Amount())
+	}
+	if !inv.TotalAmount.Equals(totals.Payable) {
+		t.Errorf("TotalAmount must be the payable amount, got %d", inv.TotalAmount.Amount())
+	}
+
+	if _, err := domain.NewInvoiceWithLines("INV-002", "CUST-001", nil, time.Now(), time.Now()); err != domain.ErrInvalidInvoiceLine {
+		t.Errorf("expected ErrInvalidInvoiceLine, got %v", err)
+	}
+
+	usdPrice, _ := domain.NewMoney(100, "USD")
+	usdZero, _ := domain.NewMoney(0, "USD")
+	usdLine, _ := domain.NewInvoiceLine("Lisans", 1000, usdPrice, usdZero, domain.VATRate0, 0)
+	if _, err := domain.NewInvoiceWithLines("INV-003", "CUST-001", []domain.InvoiceLine{first, usdLine}, time.Now(), time.Now()); err != domain.ErrCurrencyMismatch {
+		t.Errorf("expected ErrCurrencyMismatch, got %v", err)
+	}
+}
+
+func TestInvoice_TotalsWithoutLines(t *testing.T) {
+	inv := newTestInvoice(t, "INV-001", 1000, "TRY")
+
+	totals := inv.Totals()
+	if totals.Subtotal.Amount() != 1000 || totals.Payable.Amount() != 1000 || !totals.VAT.IsZero() || !totals.Withholding.IsZero() {
+		t.Errorf("a single-amount invoice must report its amount as subtotal without VAT, got %+v", totals)
+	}
+}
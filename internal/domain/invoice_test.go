@@ -1,15 +1,15 @@
 package domain_test
 
 import (
+	"carigo/internal/domain"
 	"testing"
 	"time"
-	"carigo/internal/domain"
 )
 
 func TestInvoice_Lifecycle(t *testing.T) {
 	total, _ := domain.NewMoney(1000, "TRY")
 	inv, err := domain.NewInvoice("INV-001", "CUST-001", total, time.Now(), time.Now().Add(24*time.Hour))
-	
+
 	if err != nil {
 		t.Fatalf("failed to create invoice: %v", err)
 	}
@@ -47,3 +47,59 @@ func TestInvoice_Lifecycle(t *testing.T) {
 		t.Errorf("expected ErrInvoiceAlreadyPaid, got %v", err)
 	}
 }
+
+func TestInvoice_ReleasePayment(t *testing.T) {
+	total, _ := domain.NewMoney(1000, "TRY")
+	inv, _ := domain.NewInvoice("INV-001", "CUST-001", total, time.Now(), time.Now().Add(24*time.Hour))
+	_ = inv.AllocatePayment(total)
+
+	part, _ := domain.NewMoney(300, "TRY")
+	if err := inv.ReleasePayment(part); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if inv.Status != domain.InvoiceStatusPartial {
+		t.Errorf("expected status PARTIAL, got %s", inv.Status)
+	}
+
+	rest, _ := domain.NewMoney(700, "TRY")
+	if err := inv.ReleasePayment(rest); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if inv.Status != domain.InvoiceStatusOpen {
+		t.Errorf("expected status OPEN, got %s", inv.Status)
+	}
+
+	one, _ := domain.NewMoney(1, "TRY")
+	if err := inv.ReleasePayment(one); err != domain.ErrInvalidAllocationReversal {
+		t.Errorf("expected ErrInvalidAllocationReversal, got %v", err)
+	}
+}
+
+func TestInvoice_Void(t *testing.T) {
+	total, _ := domain.NewMoney(1000, "TRY")
+	inv, _ := domain.NewInvoice("INV-001", "CUST-001", total, time.Now(), time.Now().Add(24*time.Hour))
+
+	part, _ := domain.NewMoney(400, "TRY")
+	_ = inv.AllocatePayment(part)
+	if err := inv.Void("hatalı fatura"); err != domain.ErrInvoiceHasAllocations {
+		t.Errorf("expected ErrInvoiceHasAllocations, got %v", err)
+	}
+
+	_ = inv.ReleasePayment(part)
+	if err := inv.Void("hatalı fatura"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if inv.Status != domain.InvoiceStatusVoid {
+		t.Errorf("expected status VOID, got %s", inv.Status)
+	}
+	if inv.VoidReason != "hatalı fatura" || inv.VoidedAt == nil {
+		t.Errorf("expected void reason and time to be recorded")
+	}
+
+	if err := inv.Void("tekrar"); err != domain.ErrInvalidInvoiceState {
+		t.Errorf("expected ErrInvalidInvoiceState, got %v", err)
+	}
+	if err := inv.AllocatePayment(part); err == nil {
+		t.Errorf("expected allocation to a void invoice to fail")
+	}
+}
@@ -0,0 +1,245 @@
+package domain
+
+import (
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+)
+
+// InterestPeriod is the period a late interest rate is quoted for.
+type InterestPeriod string
+
+const (
+	InterestPeriodMonthly InterestPeriod = "MONTHLY"
+	InterestPeriodAnnual  InterestPeriod = "ANNUAL"
+)
+
+// days is the number of days the period's rate is spread over: interest
+// accrues daily at a 30th of a monthly rate or a 365th of an annual one.
+func (p InterestPeriod) days() (int64, bool) {
+	switch p {
+	case InterestPeriodMonthly:
+		return 30, true
+	case InterestPeriodAnnual:
+		return 365, true
+	}
+	return 0, false
+}
+
+// LateInterestRate is the late-payment interest (gecikme faizi) charged to a
+// customer. Percent is in RateScale units, so 3_500_000 is 3.5% per Period.
+// The zero value charges no interest.
+type LateInterestRate struct {
+	Percent int64
+	Period  InterestPeriod
+}
+
+func NewLateInterestRate(percent int64, period InterestPeriod) (LateInterestRate, error) {
+	if percent == 0 {
+		return LateInterestRate{}, nil
+	}
+	if _, ok := period.days(); !ok || percent < 0 {
+		return LateInterestRate{}, ErrInvalidInterestRate
+	}
+	return LateInterestRate{Percent: percent, Period: period}, nil
+}
+
+// ParseLateInterestRate reads a percentage such as "3.5" and a period name.
+// An empty percentage means no interest.
+func ParseLateInterestRate(percent, period string) (LateInterestRate, error) {
+	if strings.TrimSpace(percent) == "" {
+		return LateInterestRate{}, nil
+	}
+	p, err := ParseRate(percent)
+	if err != nil {
+		return LateInterestRate{}, ErrInvalidInterestRate
+	}
+	return NewLateInterestRate(p, InterestPeriod(strings.ToUpper(strings.TrimSpace(period))))
+}
+
+func (r LateInterestRate) IsZero() bool {
+	return r.Percent == 0
+}
+
+// interest is principal × rate × days, rounded half up to the minor unit.
+func (r LateInterestRate) interest(principal Money, days int) (Money, error) {
+	base, ok := r.Period.days()
+	if !ok {
+		return Money{}, ErrInvalidInterestRate
+	}
+	num := new(big.Int).Mul(big.NewInt(principal.amount), big.NewInt(r.Percent))
+	num.Mul(num, big.NewInt(int64(days)))
+	den := big.NewInt(RateScale * 100 * base)
+	amount, err := divRound(num, den, RoundHalfUp)
+	if err != nil {
+		return Money{}, err
+	}
+	return NewMoney(amount, principal.currency)
+}
+
+// InvoiceSettlement is an amount settled on an invoice, dated by the day the
+// money was received.
+type InvoiceSettlement struct {
+	Date   time.Time
+	Amount Money
+}
+
+// LateInterestPortion is a part of one instalment that stayed unpaid after
+// its due day. It accrues interest for the Days from Since to Until: the day
+// it was settled, or the as-of day while it is still open.
+type LateInterestPortion struct {
+	Instalment int
+	DueDate    time.Time
+	Since      time.Time
+	Until      time.Time
+	Days       int
+	Principal  Money
+	Interest   Money
+	Settled    bool
+}
+
+// LateInterest is the interest accrued on an invoice up to AsOf.
+type LateInterest struct {
+	Invoice  *Invoice
+	Rate     LateInterestRate
+	AsOf     time.Time
+	Portions []LateInterestPortion
+	Total    Money
+}
+
+// NewLateInterest charges rate on every part of the invoice that was paid
+// after its due day or is still unpaid at asOf. Settlements cover the
+// instalments in schedule order, as payments do, so a partly paid instalment
+// accrues interest on the smaller remainder from the day of each payment.
+// Settlements after asOf are ignored. Interest already billed, up to the
+// invoice's InterestInvoicedThrough day, is not charged again.
+func NewLateInterest(inv *Invoice, settlements []InvoiceSettlement, rate LateInterestRate, asOf time.Time) (*LateInterest, error) {
+	if inv.Status == InvoiceStatusVoid {
+		return nil, ErrInvalidInvoiceState
+	}
+	li := &LateInterest{
+		Invoice: inv,
+		Rate:    rate,
+		AsOf:    RateDay(asOf),
+		Total:   Money{currency: inv.TotalAmount.currency},
+	}
+	if rate.IsZero() {
+		return li, nil
+	}
+
+	sorted := make([]InvoiceSettlement, 0, len(settlements))
+	for _, s := range settlements {
+		if s.Amount.currency != inv.TotalAmount.currency {
+			return nil, ErrCurrencyMismatch
+		}
+		if s.Amount.amount < 0 {
+			return nil, ErrNegativeAmount
+		}
+		if !RateDay(s.Date).After(li.AsOf) {
+			sorted = append(sorted, s)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Date.Before(sorted[j].Date)
+	})
+
+	next := 0
+	var settled InvoiceSettlement
+	for _, st := range inv.InstalmentStatuses() {
+		open := st.Amount
+		for !open.IsZero() {
+			for settled.Amount.IsZero() && next < len(sorted) {
+				settled = sorted[next]
+				next++
+			}
+			if settled.Amount.IsZero() {
+				if err := li.accrue(st, open, li.AsOf, false); err != nil {
+					return nil, err
+				}
+				break
+			}
+
+			part := open
+			if moreThanSettled, _ := part.GreaterThan(settled.Amount); moreThanSettled {
+				part = settled.Amount
+			}
+			if err := li.accrue(st, part, settled.Date, true); err != nil {
+				return nil, err
+			}
+			open, _ = open.Subtract(part)
+			settled.Amount, _ = settled.Amount.Subtract(part)
+		}
+	}
+	return li, nil
+}
+
+// accrue adds the interest on principal of instalment st from its due day,
+// or the day interest was last billed through, to until.
+func (li *LateInterest) accrue(st InstalmentStatus, principal Money, until time.Time, settled bool) error {
+	since := RateDay(st.DueDate)
+	if billed := li.Invoice.InterestInvoicedThrough; !billed.IsZero() && RateDay(billed).After(since) {
+		since = RateDay(billed)
+	}
+	until = RateDay(until)
+	days := DaysPastDue(since, until)
+	if days <= 0 {
+		return nil
+	}
+
+	interest, err := li.Rate.interest(principal, days)
+	if err != nil {
+		return err
+	}
+	li.Total, err = li.Total.Add(interest)
+	if err != nil {
+		return err
+	}
+	li.Portions = append(li.Portions, LateInterestPortion{
+		Instalment: st.Number,
+		DueDate:    st.DueDate,
+		Since:      since,
+		Until:      until,
+		Days:       days,
+		Principal:  principal,
+		Interest:   interest,
+		Settled:    settled,
+	})
+	return nil
+}
+
+// NewLateInterestInvoice bills the customer for the given interest with a
+// single vade farkı invoice. Each source invoice is marked as billed through
+// the interest's AsOf day so the same days are not charged twice.
+func NewLateInterestInvoice(id InvoiceID, customerID CustomerID, interests []*LateInterest, issueDate, dueDate time.Time) (*Invoice, error) {
+	if len(interests) == 0 {
+		return nil, ErrNoLateInterest
+	}
+	total := Money{currency: interests[0].Total.currency}
+	for _, li := range interests {
+		if li.Invoice.CustomerID != customerID {
+			return nil, ErrCustomerMismatch
+		}
+		if li.Invoice.Kind == InvoiceKindLateInterest {
+			return nil, ErrInvalidInvoiceState
+		}
+		if li.Total.IsZero() {
+			return nil, ErrNoLateInterest
+		}
+		var err error
+		if total, err = total.Add(li.Total); err != nil {
+			return nil, err
+		}
+	}
+
+	inv, err := NewInvoice(id, customerID, total, issueDate, dueDate)
+	if err != nil {
+		return nil, err
+	}
+	inv.Kind = InvoiceKindLateInterest
+	for _, li := range interests {
+		li.Invoice.InterestInvoicedThrough = li.AsOf
+		li.Invoice.UpdatedAt = time.Now()
+	}
+	return inv, nil
+}
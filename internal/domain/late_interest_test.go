@@ -0,0 +1,128 @@
+package domain_test
+
+import (
+	"carigo/internal/domain"
+	"testing"
+	"time"
+)
+
+func TestParseLateInterestRate(t *testing.T) {
+	tests := []struct {
+		percent, period string
+		want            domain.LateInterestRate
+		err             error
+	}{
+		{"3.5", "monthly", domain.LateInterestRate{Percent: 3_500_000, Period: domain.InterestPeriodMonthly}, nil},
+		{"42", "ANNUAL", domain.LateInterestRate{Percent: 42_000_000, Period: domain.InterestPeriodAnnual}, nil},
+		{"", "", domain.LateInterestRate{}, nil},
+		{"3.5", "", domain.LateInterestRate{}, domain.ErrInvalidInterestRate},
+		{"3.5", "WEEKLY", domain.LateInterestRate{}, domain.ErrInvalidInterestRate},
+		{"-1", "MONTHLY", domain.LateInterestRate{}, domain.ErrInvalidInterestRate},
+		{"abc", "MONTHLY", domain.LateInterestRate{}, domain.ErrInvalidInterestRate},
+	}
+
+	for _, tt := range tests {
+		got, err := domain.ParseLateInterestRate(tt.percent, tt.period)
+		if err != tt.err || got != tt.want {
+			t.Errorf("ParseLateInterestRate(%q, %q) = %+v, %v; want %+v, %v", tt.percent, tt.period, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestNewLateInterest(t *testing.T) {
+	day := func(m time.Month, d int) time.Time { return time.Date(2026, m, d, 0, 0, 0, 0, time.UTC) }
+	monthly3 := domain.LateInterestRate{Percent: 3_000_000, Period: domain.InterestPeriodMonthly}
+	annual365 := domain.LateInterestRate{Percent: 36_500_000, Period: domain.InterestPeriodAnnual}
+	paid := func(d time.Time, amount int64) domain.InvoiceSettlement {
+		return domain.InvoiceSettlement{Date: d, Amount: tryMoney(amount)}
+	}
+
+	tests := []struct {
+		name        string
+		rate        domain.LateInterestRate
+		settlements []domain.InvoiceSettlement
+		asOf        time.Time
+		want        int64
+	}{
+		// 4000 x 3% x 10/30 + 6000 x 3% x 30/30
+		{"partial payment shrinks the base", monthly3, []domain.InvoiceSettlement{paid(day(1, 11), 4000)}, day(1, 31), 40 + 180},
+		{"annual rate accrues per 365 days", annual365, nil, day(1, 11), 100},
+		{"payments after as-of are ignored", monthly3, []domain.InvoiceSettlement{paid(day(1, 11), 4000), paid(day(1, 25), 6000)}, day(1, 21), 40 + 120},
+		{"paid on the due day", monthly3, []domain.InvoiceSettlement{paid(day(1, 1), 10000)}, day(3, 1), 0},
+		{"not due yet", monthly3, nil, day(1, 1), 0},
+		{"no rate", domain.LateInterestRate{}, nil, day(3, 1), 0},
+	}
+
+	for _, tt := range tests {
+		inv, _ := domain.NewInvoice("INV-001", "CUST-001", tryMoney(10000), day(1, 1).AddDate(0, -1, 0), day(1, 1))
+		li, err := domain.NewLateInterest(inv, tt.settlements, tt.rate, tt.asOf)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if li.Total.Amount() != tt.want {
+			t.Errorf("%s: expected interest %d, got %d (%+v)", tt.name, tt.want, li.Total.Amount(), li.Portions)
+		}
+	}
+}
+
+func TestNewLateInterest_Instalments(t *testing.T) {
+	day := func(m time.Month, d int) time.Time { return time.Date(2026, m, d, 0, 0, 0, 0, time.UTC) }
+	rate := domain.LateInterestRate{Percent: 3_000_000, Period: domain.InterestPeriodMonthly}
+
+	inv, _ := domain.NewInvoice("INV-001", "CUST-001", tryMoney(10000), day(1, 1), day(1, 1))
+	schedule, _ := domain.NewEvenInstalments(inv.TotalAmount, 2, day(1, 1))
+	if err := inv.SetInstalments(schedule); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 6000 on 11 Jan settles the first instalment 10 days late and 1000 of the
+	// second before it is due; the other 4000 is 10 days late on 11 Feb.
+	settlements := []domain.InvoiceSettlement{{Date: day(1, 11), Amount: tryMoney(6000)}}
+	li, err := domain.NewLateInterest(inv, settlements, rate, day(2, 11))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(li.Portions) != 2 {
+		t.Fatalf("expected 2 portions, got %+v", li.Portions)
+	}
+	first, second := li.Portions[0], li.Portions[1]
+	if first.Instalment != 1 || first.Days != 10 || first.Principal.Amount() != 5000 || !first.Settled || first.Interest.Amount() != 50 {
+		t.Errorf("unexpected first portion: %+v", first)
+	}
+	if second.Instalment != 2 || second.Days != 10 || second.Principal.Amount() != 4000 || second.Settled || second.Interest.Amount() != 40 {
+		t.Errorf("unexpected second portion: %+v", second)
+	}
+}
+
+func TestNewLateInterestInvoice(t *testing.T) {
+	day := func(m time.Month, d int) time.Time { return time.Date(2026, m, d, 0, 0, 0, 0, time.UTC) }
+	rate := domain.LateInterestRate{Percent: 3_000_000, Period: domain.InterestPeriodMonthly}
+
+	inv, _ := domain.NewInvoice("INV-001", "CUST-001", tryMoney(10000), day(1, 1), day(1, 1))
+	li, _ := domain.NewLateInterest(inv, nil, rate, day(1, 31))
+
+	if _, err := domain.NewLateInterestInvoice("VF-1", "CUST-002", []*domain.LateInterest{li}, day(1, 31), day(1, 31)); err != domain.ErrCustomerMismatch {
+		t.Errorf("expected ErrCustomerMismatch, got %v", err)
+	}
+
+	vf, err := domain.NewLateInterestInvoice("VF-1", "CUST-001", []*domain.LateInterest{li}, day(1, 31), day(1, 31))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if vf.Kind != domain.InvoiceKindLateInterest || vf.TotalAmount.Amount() != 300 {
+		t.Errorf("expected a 300 vade farkı invoice, got %s %d", vf.Kind, vf.TotalAmount.Amount())
+	}
+	if !inv.InterestInvoicedThrough.Equal(day(1, 31)) {
+		t.Errorf("expected the invoice to be billed through 31 Jan, got %s", inv.InterestInvoicedThrough)
+	}
+
+	// Only the days after 31 Jan are charged again.
+	later, _ := domain.NewLateInterest(inv, nil, rate, day(2, 10))
+	if later.Total.Amount() != 100 {
+		t.Errorf("expected 100 for the 10 days after billing, got %d", later.Total.Amount())
+	}
+	billed, _ := domain.NewLateInterest(inv, nil, rate, day(1, 20))
+	if _, err := domain.NewLateInterestInvoice("VF-2", "CUST-001", []*domain.LateInterest{billed}, day(1, 20), day(1, 20)); err != domain.ErrNoLateInterest {
+		t.Errorf("expected ErrNoLateInterest for days already billed, got %v", err)
+	}
+}
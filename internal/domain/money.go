@@ -9,8 +9,8 @@ func NewMoney(amount int64, currency string) (Money, error) {
 	if amount < 0 {
 		return Money{}, ErrNegativeAmount
 	}
-	if currency == "" {
-		return Money{}, ErrInvalidCurrency
+	if _, err := LookupCurrency(currency); err != nil {
+		return Money{}, err
 	}
 	return Money{
 		amount:   amount,
@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"math/big"
+	"sort"
+)
+
+// RoundingMode decides what happens to a fraction of the minor unit.
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds halves away from zero: 2.5 -> 3.
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven rounds halves to the even neighbour (banker's rounding): 2.5 -> 2, 3.5 -> 4.
+	RoundHalfEven
+	// RoundDown truncates the fraction: 2.9 -> 2.
+	RoundDown
+)
+
+// Multiply scales the amount by rate, a fixed-point factor in RateScale units
+// (200_000 is 20%, 1_500_000 is 1.5), rounding the result to the minor unit
+// with the given mode.
+func (m Money) Multiply(rate int64, mode RoundingMode) (Money, error) {
+	if rate < 0 {
+		return Money{}, ErrNegativeAmount
+	}
+	num := new(big.Int).Mul(big.NewInt(m.amount), big.NewInt(rate))
+	amount, err := divRound(num, big.NewInt(RateScale), mode)
+	if err != nil {
+		return Money{}, err
+	}
+	return NewMoney(amount, m.currency)
+}
+
+// Allocate splits the amount into parts proportional to ratios. Each part is
+// rounded down and the minor units left over go, one each, to the parts with
+// the largest discarded fractions; ties go to the earlier part. The parts
+// always add up to the original amount, and a zero ratio always gets zero.
+func (m Money) Allocate(ratios ...int) ([]Money, error) {
+	if len(ratios) == 0 {
+		return nil, ErrInvalidRatios
+	}
+	total := int64(0)
+	for _, r := range ratios {
+		if r < 0 {
+			return nil, ErrInvalidRatios
+		}
+		total += int64(r)
+	}
+	if total == 0 {
+		return nil, ErrInvalidRatios
+	}
+
+	type share struct {
+		index    int
+		fraction *big.Int
+	}
+	parts := make([]Money, len(ratios))
+	shares := make([]share, len(ratios))
+	remainder := m.amount
+	den := big.NewInt(total)
+	for i, r := range ratios {
+		num := new(big.Int).Mul(big.NewInt(m.amount), big.NewInt(int64(r)))
+		q, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+		parts[i] = Money{amount: q.Int64(), currency: m.currency}
+		shares[i] = share{index: i, fraction: rem}
+		remainder -= q.Int64()
+	}
+
+	sort.SliceStable(shares, func(i, j int) bool {
+		return shares[i].fraction.Cmp(shares[j].fraction) > 0
+	})
+	for i := int64(0); i < remainder; i++ {
+		parts[shares[i].index].amount++
+	}
+	return parts, nil
+}
+
+// divRound divides two non-negative integers, rounding the quotient with mode.
+func divRound(num, den *big.Int, mode RoundingMode) (int64, error) {
+	q, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	twice := new(big.Int).Mul(rem, big.NewInt(2))
+
+	switch mode {
+	case RoundHalfUp:
+		if twice.Cmp(den) >= 0 {
+			q.Add(q, big.NewInt(1))
+		}
+	case RoundHalfEven:
+		if c := twice.Cmp(den); c > 0 || (c == 0 && q.Bit(0) == 1) {
+			q.Add(q, big.NewInt(1))
+		}
+	case RoundDown:
+	default:
+		return 0, ErrUnknownRoundingMode
+	}
+
+	if !q.IsInt64() {
+		return 0, ErrInvalidAmount
+	}
+	return q.Int64(), nil
+}
@@ -0,0 +1,160 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Locale selects separators when formatting amounts for people.
+type Locale string
+
+const (
+	// LocaleTR formats as "1.234,56 TRY".
+	LocaleTR Locale = "tr"
+	// LocaleEN formats as "TRY 1,234.56".
+	LocaleEN Locale = "en"
+)
+
+// ParseMoney reads a decimal amount in the currency's major unit. Both Turkish
+// ("1.234,56") and English ("1,234.56") separators are accepted: a separator
+// is taken as the decimal point when the other one appears before it, or when
+// it appears once and is followed by no more digits than the currency has
+// minor units. So "1.234" is 1234 TRY but 1.234 KWD. More decimal places than
+// the currency has are rejected rather than rounded.
+func ParseMoney(s, currency string) (Money, error) {
+	cur, err := LookupCurrency(currency)
+	if err != nil {
+		return Money{}, err
+	}
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "-") {
+		return Money{}, ErrNegativeAmount
+	}
+
+	whole, frac := s, ""
+	if last := strings.LastIndexAny(s, ".,"); last >= 0 {
+		sep, head, tail := s[last:last+1], s[:last], s[last+1:]
+		other := ","
+		if sep == "," {
+			other = "."
+		}
+		if strings.Contains(head, other) || (!strings.Contains(head, sep) && len(tail) <= cur.MinorUnits) {
+			if strings.Contains(head, sep) || len(tail) > cur.MinorUnits {
+				return Money{}, ErrInvalidAmount
+			}
+			whole, frac = head, tail
+		}
+	}
+
+	digits, err := ungroup(whole)
+	if err != nil || !isDigits(frac) {
+		return Money{}, ErrInvalidAmount
+	}
+	digits += frac + strings.Repeat("0", cur.MinorUnits-len(frac))
+
+	amount, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return Money{}, ErrInvalidAmount
+	}
+	return NewMoney(amount, cur.Code)
+}
+
+// ungroup strips thousands separators from the integer part, checking that
+// every group after the first has exactly three digits.
+func ungroup(s string) (string, error) {
+	sep := ""
+	switch {
+	case strings.Contains(s, ".") && strings.Contains(s, ","):
+		return "", ErrInvalidAmount
+	case strings.Contains(s, "."):
+		sep = "."
+	case strings.Contains(s, ","):
+		sep = ","
+	}
+	if sep == "" {
+		if s == "" || !isDigits(s) {
+			return "", ErrInvalidAmount
+		}
+		return s, nil
+	}
+
+	groups := strings.Split(s, sep)
+	if len(groups[0]) == 0 || len(groups[0]) > 3 {
+		return "", ErrInvalidAmount
+	}
+	for i, g := range groups {
+		if !isDigits(g) || (i > 0 && len(g) != 3) {
+			return "", ErrInvalidAmount
+		}
+	}
+	return strings.Join(groups, ""), nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Decimal renders the amount exactly in the major unit, with a dot and as many
+// decimals as the currency has minor units, e.g. "1234.56". It is the form
+// used in API responses.
+func (m Money) Decimal() string {
+	return formatMinor(m.amount, minorUnits(m.currency), ".", "")
+}
+
+// Format renders the amount with the currency code for display.
+// Unknown locales fall back to English.
+func (m Money) Format(locale Locale) string {
+	return formatAmount(m.amount, m.currency, locale)
+}
+
+// Decimal renders the signed balance like Money.Decimal, e.g. "-12.50".
+func (b Balance) Decimal() string {
+	return formatMinor(b.amount, minorUnits(b.currency), ".", "")
+}
+
+// Format renders the signed balance with the currency code for display.
+func (b Balance) Format(locale Locale) string {
+	return formatAmount(b.amount, b.currency, locale)
+}
+
+func formatAmount(amount int64, currency string, locale Locale) string {
+	units := minorUnits(currency)
+	if locale == LocaleTR {
+		return formatMinor(amount, units, ",", ".") + " " + currency
+	}
+	return currency + " " + formatMinor(amount, units, ".", ",")
+}
+
+func minorUnits(currency string) int {
+	if cur, err := LookupCurrency(currency); err == nil {
+		return cur.MinorUnits
+	}
+	return 2
+}
+
+func formatMinor(amount int64, units int, decimalSep, groupSep string) string {
+	sign := ""
+	digits := strconv.FormatInt(amount, 10)
+	if amount < 0 {
+		sign, digits = "-", digits[1:]
+	}
+	if len(digits) <= units {
+		digits = strings.Repeat("0", units-len(digits)+1) + digits
+	}
+
+	whole, frac := digits[:len(digits)-units], digits[len(digits)-units:]
+	if groupSep != "" {
+		for i := len(whole) - 3; i > 0; i -= 3 {
+			whole = whole[:i] + groupSep + whole[i:]
+		}
+	}
+	if units == 0 {
+		return sign + whole
+	}
+	return sign + whole + decimalSep + frac
+}
@@ -0,0 +1,143 @@
+package domain_test
+
+import (
+	"carigo/internal/domain"
+	"testing"
+	"time"
+)
+
+func TestNewMoney_ValidatesCurrency(t *testing.T) {
+	for _, code := range []string{"", "XYZ", "try", "TL"} {
+		if _, err := domain.NewMoney(100, code); err != domain.ErrInvalidCurrency {
+			t.Errorf("NewMoney(100, %q): expected ErrInvalidCurrency, got %v", code, err)
+		}
+	}
+
+	for code, units := range map[string]int{"TRY": 2, "JPY": 0, "KWD": 3} {
+		cur, err := domain.LookupCurrency(code)
+		if err != nil || cur.MinorUnits != units {
+			t.Errorf("LookupCurrency(%q) = %+v, %v; want %d minor units", code, cur, err, units)
+		}
+	}
+}
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		in       string
+		currency string
+		want     int64
+		wantErr  error
+	}{
+		{"1.234,56", "TRY", 123456, nil},
+		{"1,234.56", "USD", 123456, nil},
+		{"1234,5", "TRY", 123450, nil},
+		{"1234.56", "TRY", 123456, nil},
+		{" 35 ", "TRY", 3500, nil},
+		{"35,", "TRY", 3500, nil},
+		{"1.234", "TRY", 123400, nil},
+		{"1,234", "USD", 123400, nil},
+		{"1.234.567,89", "TRY", 123456789, nil},
+		{"1.234", "KWD", 1234, nil},
+		{"1.234,567", "KWD", 1234567, nil},
+		{"1.234", "JPY", 1234, nil},
+		{"1234", "JPY", 1234, nil},
+		{"0,01", "TRY", 1, nil},
+		{"1,234,56", "TRY", 0, domain.ErrInvalidAmount},
+		{"1.5", "JPY", 0, domain.ErrInvalidAmount},
+		{"12.34.56", "TRY", 0, domain.ErrInvalidAmount},
+		{"1,234.567", "TRY", 0, domain.ErrInvalidAmount},
+		{"12.3456", "TRY", 0, domain.ErrInvalidAmount},
+		{"1.23,45", "TRY", 0, domain.ErrInvalidAmount},
+		{",50", "TRY", 0, domain.ErrInvalidAmount},
+		{"abc", "TRY", 0, domain.ErrInvalidAmount},
+		{"", "TRY", 0, domain.ErrInvalidAmount},
+		{"-5,00", "TRY", 0, domain.ErrNegativeAmount},
+		{"5,00", "XYZ", 0, domain.ErrInvalidCurrency},
+		{"99999999999999999999", "TRY", 0, domain.ErrInvalidAmount},
+	}
+
+	for _, tt := range tests {
+		got, err := domain.ParseMoney(tt.in, tt.currency)
+		if tt.wantErr != nil {
+			if err != tt.wantErr {
+				t.Errorf("ParseMoney(%q, %s): expected %v, got %v", tt.in, tt.currency, tt.wantErr, err)
+			}
+			continue
+		}
+		if err != nil || got.Amount() != tt.want || got.Currency() != tt.currency {
+			t.Errorf("ParseMoney(%q, %s) = %d %s, %v; want %d", tt.in, tt.currency, got.Amount(), got.Currency(), err, tt.want)
+		}
+	}
+}
+
+func TestMoney_DecimalAndFormat(t *testing.T) {
+	tests := []struct {
+		amount   int64
+		currency string
+		decimal  string
+		tr       string
+		en       string
+	}{
+		{123456789, "TRY", "1234567.89", "1.234.567,89 TRY", "TRY 1,234,567.89"},
+		{5, "TRY", "0.05", "0,05 TRY", "TRY 0.05"},
+		{0, "USD", "0.00", "0,00 USD", "USD 0.00"},
+		{1234, "JPY", "1234", "1.234 JPY", "JPY 1,234"},
+		{1234567, "KWD", "1234.567", "1.234,567 KWD", "KWD 1,234.567"},
+		{100, "EUR", "1.00", "1,00 EUR", "EUR 1.00"},
+	}
+
+	for _, tt := range tests {
+		m, err := domain.NewMoney(tt.amount, tt.currency)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := m.Decimal(); got != tt.decimal {
+			t.Errorf("Decimal() = %q, want %q", got, tt.decimal)
+		}
+		if got := m.Format(domain.LocaleTR); got != tt.tr {
+			t.Errorf("Format(tr) = %q, want %q", got, tt.tr)
+		}
+		if got := m.Format(domain.LocaleEN); got != tt.en {
+			t.Errorf("Format(en) = %q, want %q", got, tt.en)
+		}
+
+		back, err := domain.ParseMoney(tt.decimal, tt.currency)
+		if err != nil || !back.Equals(m) {
+			t.Errorf("ParseMoney(%q) did not round-trip: %d, %v", tt.decimal, back.Amount(), err)
+		}
+	}
+}
+
+func TestBalance_Decimal(t *testing.T) {
+	b, _ := domain.NewBalance("TRY")
+	m, _ := domain.NewMoney(123450, "TRY")
+	b, _ = b.Credit(m)
+	if got := b.Decimal(); got != "-1234.50" {
+		t.Errorf("expected -1234.50, got %s", got)
+	}
+	if got := b.Format(domain.LocaleTR); got != "-1.234,50 TRY" {
+		t.Errorf("expected -1.234,50 TRY, got %s", got)
+	}
+}
+
+func TestExchangeRate_ConvertAcrossMinorUnits(t *testing.T) {
+	rate, err := domain.NewExchangeRate("USD", "JPY", 150_250000, time.Now(), domain.ExchangeRateSourceManual)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	usd, _ := domain.NewMoney(1000, "USD")
+	jpy, err := rate.Convert(usd)
+	if err != nil || jpy.Amount() != 1503 || jpy.Currency() != "JPY" {
+		t.Errorf("expected 1503 JPY for 10.00 USD, got %d %s, %v", jpy.Amount(), jpy.Currency(), err)
+	}
+
+	back, err := rate.Convert(jpy)
+	if err != nil || back.Amount() != 1000 {
+		t.Errorf("expected 10.00 USD back, got %d, %v", back.Amount(), err)
+	}
+
+	if _, err := domain.NewExchangeRate("USD", "XYZ", 1_000000, time.Now(), domain.ExchangeRateSourceManual); err != domain.ErrInvalidCurrency {
+		t.Errorf("expected ErrInvalidCurrency, got %v", err)
+	}
+}
@@ -1,8 +1,8 @@
 package domain_test
 
 import (
-	"testing"
 	"carigo/internal/domain"
+	"testing"
 )
 
 func TestNewMoney(t *testing.T) {
@@ -26,7 +26,7 @@ func TestNewMoney(t *testing.T) {
 func TestMoney_Add(t *testing.T) {
 	m1, _ := domain.NewMoney(100, "USD")
 	m2, _ := domain.NewMoney(50, "USD")
-	
+
 	sum, err := m1.Add(m2)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -54,3 +54,126 @@ func TestMoney_Subtract(t *testing.T) {
 		t.Errorf("expected 60, got %d", res.Amount())
 	}
 }
+
+func TestMoney_Multiply(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount int64
+		rate   int64
+		mode   domain.RoundingMode
+		want   int64
+	}{
+		{"exact half-up", 1000, 200_000, domain.RoundHalfUp, 200},
+		{"exact half-even", 1000, 200_000, domain.RoundHalfEven, 200},
+		{"exact down", 1000, 200_000, domain.RoundDown, 200},
+		{"below half half-up", 1001, 200_000, domain.RoundHalfUp, 200},
+		{"below half half-even", 1001, 200_000, domain.RoundHalfEven, 200},
+		{"below half down", 1001, 200_000, domain.RoundDown, 200},
+		{"above half half-up", 1004, 200_000, domain.RoundHalfUp, 201},
+		{"above half half-even", 1004, 200_000, domain.RoundHalfEven, 201},
+		{"above half down", 1004, 200_000, domain.RoundDown, 200},
+		{"half to odd half-up", 25, 100_000, domain.RoundHalfUp, 3},
+		{"half to odd half-even", 25, 100_000, domain.RoundHalfEven, 2},
+		{"half to odd down", 25, 100_000, domain.RoundDown, 2},
+		{"half to even half-up", 35, 100_000, domain.RoundHalfUp, 4},
+		{"half to even half-even", 35, 100_000, domain.RoundHalfEven, 4},
+		{"half to even down", 35, 100_000, domain.RoundDown, 3},
+		{"tiny half-up", 1, 500_000, domain.RoundHalfUp, 1},
+		{"tiny half-even", 1, 500_000, domain.RoundHalfEven, 0},
+		{"tiny down", 1, 500_000, domain.RoundDown, 0},
+		{"zero rate", 12345, 0, domain.RoundHalfUp, 0},
+		{"zero amount", 0, 200_000, domain.RoundHalfUp, 0},
+		{"identity", 12345, 1_000_000, domain.RoundDown, 12345},
+		{"growth", 10000, 1_500_000, domain.RoundHalfUp, 15000},
+		{"six decimals", 100000, 1, domain.RoundHalfUp, 0},
+		{"six decimals half", 500000, 1, domain.RoundHalfUp, 1},
+		{"kdv 18 on odd kurus", 333, 180_000, domain.RoundHalfUp, 60},
+		{"kdv 18 on odd kurus half-even", 333, 180_000, domain.RoundHalfEven, 60},
+		{"large", 9_000_000_000_000_000, 1_000_000, domain.RoundHalfUp, 9_000_000_000_000_000},
+	}
+
+	for _, tt := range tests {
+		m, _ := domain.NewMoney(tt.amount, "TRY")
+		got, err := m.Multiply(tt.rate, tt.mode)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if got.Amount() != tt.want || got.Currency() != "TRY" {
+			t.Errorf("%s: %d x %d = %d %s, want %d", tt.name, tt.amount, tt.rate, got.Amount(), got.Currency(), tt.want)
+		}
+	}
+}
+
+func TestMoney_Multiply_Errors(t *testing.T) {
+	m, _ := domain.NewMoney(100, "TRY")
+	if _, err := m.Multiply(-1, domain.RoundHalfUp); err != domain.ErrNegativeAmount {
+		t.Errorf("expected ErrNegativeAmount, got %v", err)
+	}
+	if _, err := m.Multiply(1_000_000, domain.RoundingMode(99)); err != domain.ErrUnknownRoundingMode {
+		t.Errorf("expected ErrUnknownRoundingMode, got %v", err)
+	}
+
+	huge, _ := domain.NewMoney(9_000_000_000_000_000_000, "TRY")
+	if _, err := huge.Multiply(2_000_000, domain.RoundHalfUp); err != domain.ErrInvalidAmount {
+		t.Errorf("expected ErrInvalidAmount on overflow, got %v", err)
+	}
+}
+
+func TestMoney_Allocate(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount int64
+		ratios []int
+		want   []int64
+	}{
+		{"even split", 100, []int{1, 1}, []int64{50, 50}},
+		{"thirds", 100, []int{1, 1, 1}, []int64{34, 33, 33}},
+		{"two thirds left over", 101, []int{1, 1, 1}, []int64{34, 34, 33}},
+		{"single part", 999, []int{7}, []int64{999}},
+		{"largest fraction wins", 5, []int{3, 7}, []int64{2, 3}},
+		{"largest fraction wins late", 10, []int{1, 2, 4}, []int64{1, 3, 6}},
+		{"zero ratio stays zero", 100, []int{0, 1, 1}, []int64{0, 50, 50}},
+		{"zero ratio with remainder", 101, []int{1, 0, 1}, []int64{51, 0, 50}},
+		{"zero amount", 0, []int{1, 2, 3}, []int64{0, 0, 0}},
+		{"fewer units than parts", 2, []int{1, 1, 1}, []int64{1, 1, 0}},
+		{"one unit", 1, []int{1, 1, 1, 1}, []int64{1, 0, 0, 0}},
+		{"percentages", 10000, []int{70, 20, 10}, []int64{7000, 2000, 1000}},
+		{"uneven percentages", 3333, []int{50, 30, 20}, []int64{1666, 1000, 667}},
+		{"instalments", 100000, []int{1, 1, 1, 1, 1, 1, 1}, []int64{14286, 14286, 14286, 14286, 14286, 14285, 14285}},
+		{"large", 9_000_000_000_000_001, []int{1, 2}, []int64{3_000_000_000_000_000, 6_000_000_000_000_001}},
+	}
+
+	for _, tt := range tests {
+		m, _ := domain.NewMoney(tt.amount, "TRY")
+		parts, err := m.Allocate(tt.ratios...)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if len(parts) != len(tt.want) {
+			t.Errorf("%s: expected %d parts, got %d", tt.name, len(tt.want), len(parts))
+			continue
+		}
+
+		sum := int64(0)
+		for i, p := range parts {
+			if p.Amount() != tt.want[i] || p.Currency() != "TRY" {
+				t.Errorf("%s: part %d = %d %s, want %d", tt.name, i, p.Amount(), p.Currency(), tt.want[i])
+			}
+			sum += p.Amount()
+		}
+		if sum != tt.amount {
+			t.Errorf("%s: parts sum to %d, want %d", tt.name, sum, tt.amount)
+		}
+	}
+}
+
+func TestMoney_Allocate_InvalidRatios(t *testing.T) {
+	m, _ := domain.NewMoney(100, "TRY")
+	for _, ratios := range [][]int{nil, {0}, {0, 0}, {1, -1}} {
+		if _, err := m.Allocate(ratios...); err != domain.ErrInvalidRatios {
+			t.Errorf("Allocate(%v): expected ErrInvalidRatios, got %v", ratios, err)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"strconv"
+	"time"
+)
+
+// InvoiceStatusOverdue is never stored. EffectiveStatus derives it for an
+// OPEN or PARTIAL invoice that has an instalment past its due date.
+const InvoiceStatusOverdue InvoiceStatus = "OVERDUE"
+
+// IsOverdue reports whether some part of the invoice is still unpaid after
+// its due day. Only OPEN and PARTIAL invoices can be overdue.
+func (i *Invoice) IsOverdue(now time.Time) bool {
+	if i.Status != InvoiceStatusOpen && i.Status != InvoiceStatusPartial {
+		return false
+	}
+	for _, s := range i.InstalmentStatuses() {
+		if s.IsOverdue(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// DaysOverdue counts the days since the earliest unpaid instalment fell due,
+// or zero if the invoice is not overdue.
+func (i *Invoice) DaysOverdue(now time.Time) int {
+	if !i.IsOverdue(now) {
+		return 0
+	}
+	return DaysPastDue(i.NextDueDate(), now)
+}
+
+// EffectiveStatus is Status, except that an overdue invoice reports OVERDUE.
+func (i *Invoice) EffectiveStatus(now time.Time) InvoiceStatus {
+	if i.IsOverdue(now) {
+		return InvoiceStatusOverdue
+	}
+	return i.Status
+}
+
+type OverdueEventID string
+
+// OverdueEvent records that an instalment of an invoice became overdue. An
+// invoice without a schedule has a single instalment, number 1.
+type OverdueEvent struct {
+	ID         OverdueEventID
+	InvoiceID  InvoiceID
+	CustomerID CustomerID
+	Instalment int
+	DueDate    time.Time
+	// Amount is what was still open when the event was detected.
+	Amount     Money
+	DetectedAt time.Time
+}
+
+// NewOverdueEvents returns an event for every instalment overdue at now whose
+// number is not in recorded. Event IDs are derived from the invoice and the
+// instalment, so the same instalment always yields the same ID.
+func (i *Invoice) NewOverdueEvents(now time.Time, recorded map[int]bool) []*OverdueEvent {
+	if !i.IsOverdue(now) {
+		return nil
+	}
+	var events []*OverdueEvent
+	for _, s := range i.InstalmentStatuses() {
+		if !s.IsOverdue(now) || recorded[s.Number] {
+			continue
+		}
+		events = append(events, &OverdueEvent{
+			ID:         OverdueEventID("OD-" + string(i.ID) + "-" + strconv.Itoa(s.Number)),
+			InvoiceID:  i.ID,
+			CustomerID: i.CustomerID,
+			Instalment: s.Number,
+			DueDate:    s.DueDate,
+			Amount:     s.Remaining,
+			DetectedAt: now,
+		})
+	}
+	return events
+}
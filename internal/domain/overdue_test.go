@@ -0,0 +1,82 @@
+package domain_test
+
+import (
+	"carigo/internal/domain"
+	"testing"
+	"time"
+)
+
+func TestInvoice_IsOverdue(t *testing.T) {
+	due := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	issue := due.AddDate(0, 0, -30)
+	mk := func() *domain.Invoice {
+		inv, _ := domain.NewInvoice("INV-001", "CUST-001", tryMoney(1000), issue, due)
+		return inv
+	}
+
+	tests := []struct {
+		name    string
+		setup   func(inv *domain.Invoice)
+		asOf    time.Time
+		overdue bool
+		days    int
+		status  domain.InvoiceStatus
+	}{
+		{"before due", func(*domain.Invoice) {}, due.AddDate(0, 0, -1), false, 0, domain.InvoiceStatusOpen},
+		{"on due day", func(*domain.Invoice) {}, due.Add(20 * time.Hour), false, 0, domain.InvoiceStatusOpen},
+		{"day after due", func(*domain.Invoice) {}, due.AddDate(0, 0, 1), true, 1, domain.InvoiceStatusOverdue},
+		{"partly paid", func(inv *domain.Invoice) { inv.AllocatePayment(tryMoney(400)) }, due.AddDate(0, 0, 12), true, 12, domain.InvoiceStatusOverdue},
+		{"paid", func(inv *domain.Invoice) { inv.AllocatePayment(tryMoney(1000)) }, due.AddDate(0, 0, 12), false, 0, domain.InvoiceStatusPaid},
+		{"void", func(inv *domain.Invoice) { inv.Void("hata") }, due.AddDate(0, 0, 12), false, 0, domain.InvoiceStatusVoid},
+	}
+
+	for _, tt := range tests {
+		inv := mk()
+		tt.setup(inv)
+		if got := inv.IsOverdue(tt.asOf); got != tt.overdue {
+			t.Errorf("%s: IsOverdue = %v, want %v", tt.name, got, tt.overdue)
+		}
+		if got := inv.DaysOverdue(tt.asOf); got != tt.days {
+			t.Errorf("%s: DaysOverdue = %d, want %d", tt.name, got, tt.days)
+		}
+		if got := inv.EffectiveStatus(tt.asOf); got != tt.status {
+			t.Errorf("%s: EffectiveStatus = %s, want %s", tt.name, got, tt.status)
+		}
+	}
+}
+
+func TestInvoice_NewOverdueEvents(t *testing.T) {
+	first := time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+	inv, _ := domain.NewInvoice("INV-001", "CUST-001", tryMoney(3000), first.AddDate(0, 0, -10), first)
+	schedule, _ := domain.NewEvenInstalments(inv.TotalAmount, 3, first)
+	if err := inv.SetInstalments(schedule); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := inv.AllocatePayment(tryMoney(1200)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 20 Feb: the first instalment is paid, the second (15 Feb) is late.
+	asOf := time.Date(2026, 2, 20, 9, 0, 0, 0, time.UTC)
+	if got := inv.DaysOverdue(asOf); got != 5 {
+		t.Errorf("expected 5 days overdue, counted from the second instalment; got %d", got)
+	}
+
+	events := inv.NewOverdueEvents(asOf, nil)
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	e := events[0]
+	if e.ID != "OD-INV-001-2" || e.Instalment != 2 || e.Amount.Amount() != 800 || !e.DetectedAt.Equal(asOf) {
+		t.Errorf("unexpected event: %+v", e)
+	}
+
+	if again := inv.NewOverdueEvents(asOf, map[int]bool{2: true}); len(again) != 0 {
+		t.Errorf("an instalment with an event must not get another, got %d", len(again))
+	}
+
+	later := inv.NewOverdueEvents(time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC), map[int]bool{2: true})
+	if len(later) != 1 || later[0].Instalment != 3 {
+		t.Errorf("expected only the third instalment to become overdue, got %+v", later)
+	}
+}
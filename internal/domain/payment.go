@@ -12,6 +12,8 @@ type Payment struct {
 	AvailableAmount Money
 	Date            time.Time
 	Notes           string
+	ReversedAt      *time.Time
+	ReversalReason  string
 	CreatedAt       time.Time
 }
 
@@ -42,3 +44,49 @@ func (p *Payment) UseFunds(amount Money) error {
 	p.AvailableAmount = newAvailable
 	return nil
 }
+
+// RestoreFunds gives back an amount previously taken with UseFunds.
+func (p *Payment) RestoreFunds(amount Money) error {
+	if amount.currency != p.AvailableAmount.currency {
+		return ErrCurrencyMismatch
+	}
+
+	newAvailable, err := p.AvailableAmount.Add(amount)
+	if err != nil {
+		return err
+	}
+	if exceeds, _ := newAvailable.GreaterThan(p.Amount); exceeds {
+		return ErrInvalidAllocationReversal
+	}
+	p.AvailableAmount = newAvailable
+	return nil
+}
+
+func (p *Payment) IsReversed() bool {
+	return p.ReversedAt != nil
+}
+
+// Reverse cancels a payment that was booked by mistake. All of its
+// allocations must have been reversed first; the row itself is kept.
+func (p *Payment) Reverse(reason string, at time.Time) error {
+	if p.IsReversed() {
+		return ErrPaymentAlreadyReversed
+	}
+	if !p.AvailableAmount.Equals(p.Amount) {
+		return ErrPaymentHasAllocations
+	}
+
+	zero, _ := NewMoney(0, p.Amount.currency)
+	p.AvailableAmount = zero
+	p.ReversedAt = &at
+	p.ReversalReason = reason
+	return nil
+}
+
+func (p *Payment) owner() CustomerID { return p.CustomerID }
+
+func (p *Payment) available() Money { return p.AvailableAmount }
+
+func (p *Payment) attach(a *Allocation) { a.PaymentID = p.ID }
+
+func (p *Payment) owns(a *Allocation) bool { return a.PaymentID == p.ID }
@@ -0,0 +1,200 @@
+package domain
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// TermsBasis is what the due date of an invoice is counted from.
+type TermsBasis string
+
+const (
+	// TermsBasisNet is due NetDays after the issue date.
+	TermsBasisNet TermsBasis = "NET"
+	// TermsBasisEndOfMonth is due NetDays after the end of the issue month.
+	TermsBasisEndOfMonth TermsBasis = "EOM"
+	// TermsBasisDayOfNextMonth is due on DayOfMonth of the month after issue.
+	TermsBasisDayOfNextMonth TermsBasis = "NEXT_MONTH"
+)
+
+// PaymentTerms are the conditions an invoice is payable on. They decide its
+// due date, its instalments, if any, and its early-payment discount: "2/10
+// net 30" grants a 2% discount for payment within 10 days of the issue date
+// and expects the full amount within 30. DiscountPercent is in RateScale
+// units. The zero value is due on the issue date and grants no discount.
+type PaymentTerms struct {
+	Basis      TermsBasis
+	NetDays    int
+	DayOfMonth int
+	// Instalments splits the invoice into that many monthly instalments, the
+	// first due on the due date.
+	Instalments int
+	// BusinessDays moves due dates that fall on a weekend or a public holiday
+	// to the next business day.
+	BusinessDays    bool
+	DiscountPercent int64
+	DiscountDays    int
+}
+
+// NewPaymentTerms builds terms on basis. days is NetDays for the NET and EOM
+// bases and DayOfMonth for NEXT_MONTH; an empty basis is NET. The discount
+// must be below 100% and, on the NET basis, its window ends no later than the
+// net period. An instalment count below two means a single payment.
+func NewPaymentTerms(basis TermsBasis, days, instalments int, businessDays bool, discountPercent int64, discountDays int) (PaymentTerms, error) {
+	t := PaymentTerms{Basis: basis, BusinessDays: businessDays}
+	switch basis {
+	case "", TermsBasisNet, TermsBasisEndOfMonth:
+		if days < 0 {
+			return PaymentTerms{}, ErrInvalidPaymentTerms
+		}
+		t.NetDays = days
+	case TermsBasisDayOfNextMonth:
+		if days < 1 || days > 31 {
+			return PaymentTerms{}, ErrInvalidPaymentTerms
+		}
+		t.DayOfMonth = days
+	default:
+		return PaymentTerms{}, ErrInvalidPaymentTerms
+	}
+	if t.Basis == "" {
+		t.Basis = TermsBasisNet
+	}
+
+	if instalments < 0 || instalments > 120 {
+		return PaymentTerms{}, ErrInvalidPaymentTerms
+	}
+	if instalments >= 2 {
+		t.Instalments = instalments
+	}
+
+	if discountPercent < 0 || discountPercent >= 100*RateScale || discountDays < 0 {
+		return PaymentTerms{}, ErrInvalidPaymentTerms
+	}
+	if discountPercent > 0 {
+		if discountDays == 0 || (t.Basis == TermsBasisNet && t.NetDays > 0 && discountDays > t.NetDays) {
+			return PaymentTerms{}, ErrInvalidPaymentTerms
+		}
+		t.DiscountPercent, t.DiscountDays = discountPercent, discountDays
+	}
+	return t, nil
+}
+
+// ParseDiscountPercent reads a discount percentage such as "2" or "1.5" into
+// RateScale units; an empty percentage is no discount.
+func ParseDiscountPercent(s string) (int64, error) {
+	if strings.TrimSpace(s) == "" {
+		return 0, nil
+	}
+	p, err := ParseRate(s)
+	if err != nil {
+		return 0, ErrInvalidPaymentTerms
+	}
+	return p, nil
+}
+
+func (t PaymentTerms) IsZero() bool {
+	return t == PaymentTerms{}
+}
+
+// HasDiscount reports whether the terms reward early payment.
+func (t PaymentTerms) HasDiscount() bool {
+	return t.DiscountPercent > 0
+}
+
+// String renders the terms the way they are quoted, e.g. "2/10 net 30" or
+// "ay sonu + 30, 3 taksit".
+func (t PaymentTerms) String() string {
+	var s string
+	switch t.Basis {
+	case TermsBasisEndOfMonth:
+		s = fmt.Sprintf("ay sonu + %d", t.NetDays)
+	case TermsBasisDayOfNextMonth:
+		s = fmt.Sprintf("ertesi ayın %d. günü", t.DayOfMonth)
+	default:
+		s = fmt.Sprintf("net %d", t.NetDays)
+	}
+	if t.HasDiscount() {
+		s = fmt.Sprintf("%s/%d %s", FormatRate(t.DiscountPercent), t.DiscountDays, s)
+	}
+	if t.Instalments > 0 {
+		s += fmt.Sprintf(", %d taksit", t.Instalments)
+	}
+	if t.BusinessDays {
+		s += ", iş günü"
+	}
+	return s
+}
+
+// DueDate is when an invoice issued on issueDate falls due under the terms.
+// With instalments, it is the due date of the first one.
+func (t PaymentTerms) DueDate(issueDate time.Time) time.Time {
+	due := t.dueDate(issueDate)
+	if t.BusinessDays {
+		due = NextBusinessDay(due)
+	}
+	return due
+}
+
+func (t PaymentTerms) dueDate(issueDate time.Time) time.Time {
+	day := RateDay(issueDate)
+	y, m, _ := day.Date()
+	switch t.Basis {
+	case TermsBasisEndOfMonth:
+		endOfMonth := time.Date(y, m+1, 0, 0, 0, 0, 0, time.UTC)
+		return endOfMonth.AddDate(0, 0, t.NetDays)
+	case TermsBasisDayOfNextMonth:
+		return time.Date(y, m+1, min(t.DayOfMonth, daysIn(y, m+1)), 0, 0, 0, 0, time.UTC)
+	default:
+		return day.AddDate(0, 0, t.NetDays)
+	}
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// Schedule splits total into the terms' instalments for an invoice issued on
+// issueDate. It is nil when the terms ask for a single payment.
+func (t PaymentTerms) Schedule(total Money, issueDate time.Time) ([]Instalment, error) {
+	if t.Instalments == 0 {
+		return nil, nil
+	}
+	schedule, err := NewEvenInstalments(total, t.Instalments, t.dueDate(issueDate))
+	if err != nil {
+		return nil, err
+	}
+	if t.BusinessDays {
+		for i := range schedule {
+			schedule[i].DueDate = NextBusinessDay(schedule[i].DueDate)
+		}
+	}
+	return schedule, nil
+}
+
+// DiscountDeadline is the last day a payment for an invoice issued on
+// issueDate still earns the discount.
+func (t PaymentTerms) DiscountDeadline(issueDate time.Time) time.Time {
+	return RateDay(issueDate).AddDate(0, 0, t.DiscountDays)
+}
+
+// EarlyPaymentDiscount is what the invoice's terms take off the open amount
+// for a payment received on paidOn, rounded half up to the minor unit. It is
+// zero outside the discount window and for invoices that are not open.
+func (i *Invoice) EarlyPaymentDiscount(paidOn time.Time) Money {
+	zero := Money{currency: i.TotalAmount.currency}
+	if !i.Terms.HasDiscount() || (i.Status != InvoiceStatusOpen && i.Status != InvoiceStatusPartial) {
+		return zero
+	}
+	if RateDay(paidOn).After(i.Terms.DiscountDeadline(i.IssueDate)) {
+		return zero
+	}
+
+	num := new(big.Int).Mul(big.NewInt(i.RemainingAmount().amount), big.NewInt(i.Terms.DiscountPercent))
+	amount, err := divRound(num, big.NewInt(100*RateScale), RoundHalfUp)
+	if err != nil {
+		return zero
+	}
+	return Money{amount: amount, currency: i.TotalAmount.currency}
+}
@@ -0,0 +1,197 @@
+package domain_test
+
+import (
+	"carigo/internal/domain"
+	"testing"
+	"time"
+)
+
+func TestNewPaymentTerms(t *testing.T) {
+	tests := []struct {
+		name              string
+		basis             domain.TermsBasis
+		days, instalments int
+		percent           int64
+		discountDays      int
+		want              domain.PaymentTerms
+		err               error
+	}{
+		{"2/10 net 30", "", 30, 0, 2_000_000, 10, domain.PaymentTerms{Basis: domain.TermsBasisNet, NetDays: 30, DiscountPercent: 2_000_000, DiscountDays: 10}, nil},
+		{"end of month", domain.TermsBasisEndOfMonth, 30, 3, 0, 0, domain.PaymentTerms{Basis: domain.TermsBasisEndOfMonth, NetDays: 30, Instalments: 3}, nil},
+		{"day of next month", domain.TermsBasisDayOfNextMonth, 15, 0, 0, 0, domain.PaymentTerms{Basis: domain.TermsBasisDayOfNextMonth, DayOfMonth: 15}, nil},
+		{"discount days without a discount", domain.TermsBasisNet, 30, 1, 0, 10, domain.PaymentTerms{Basis: domain.TermsBasisNet, NetDays: 30}, nil},
+		{"discount without a window", domain.TermsBasisNet, 30, 0, 2_000_000, 0, domain.PaymentTerms{}, domain.ErrInvalidPaymentTerms},
+		{"window past the net period", domain.TermsBasisNet, 30, 0, 2_000_000, 40, domain.PaymentTerms{}, domain.ErrInvalidPaymentTerms},
+		{"100% discount", domain.TermsBasisNet, 30, 0, 100_000_000, 10, domain.PaymentTerms{}, domain.ErrInvalidPaymentTerms},
+		{"day 32", domain.TermsBasisDayOfNextMonth, 32, 0, 0, 0, domain.PaymentTerms{}, domain.ErrInvalidPaymentTerms},
+		{"unknown basis", "WEEKLY", 7, 0, 0, 0, domain.PaymentTerms{}, domain.ErrInvalidPaymentTerms},
+	}
+
+	for _, tt := range tests {
+		got, err := domain.NewPaymentTerms(tt.basis, tt.days, tt.instalments, false, tt.percent, tt.discountDays)
+		if err != tt.err || got != tt.want {
+			t.Errorf("%s: got %+v, %v; want %+v, %v", tt.name, got, err, tt.want, tt.err)
+		}
+	}
+
+	if _, err := domain.ParseDiscountPercent("abc"); err != domain.ErrInvalidPaymentTerms {
+		t.Errorf("expected ErrInvalidPaymentTerms, got %v", err)
+	}
+	if s := (domain.PaymentTerms{Basis: domain.TermsBasisNet, NetDays: 30, DiscountPercent: 2_000_000, DiscountDays: 10}).String(); s != "2/10 net 30" {
+		t.Errorf("expected \"2/10 net 30\", got %q", s)
+	}
+}
+
+func TestPaymentTerms_DueDate(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name  string
+		terms domain.PaymentTerms
+		issue time.Time
+		want  time.Time
+	}{
+		{"net 30", domain.PaymentTerms{Basis: domain.TermsBasisNet, NetDays: 30}, day(2026, 1, 15), day(2026, 2, 14)},
+		{"end of month + 30", domain.PaymentTerms{Basis: domain.TermsBasisEndOfMonth, NetDays: 30}, day(2026, 1, 15), day(2026, 3, 2)},
+		{"15th of next month", domain.PaymentTerms{Basis: domain.TermsBasisDayOfNextMonth, DayOfMonth: 15}, day(2026, 12, 20), day(2027, 1, 15)},
+		{"31st of a short month", domain.PaymentTerms{Basis: domain.TermsBasisDayOfNextMonth, DayOfMonth: 31}, day(2026, 1, 31), day(2026, 2, 28)},
+		{"weekend kept", domain.PaymentTerms{Basis: domain.TermsBasisNet, NetDays: 30}, day(2026, 1, 1), day(2026, 1, 31)},
+		{"weekend moved", domain.PaymentTerms{Basis: domain.TermsBasisNet, NetDays: 30, BusinessDays: true}, day(2026, 1, 1), day(2026, 2, 2)},
+		{"bayram moved", domain.PaymentTerms{Basis: domain.TermsBasisNet, NetDays: 30, BusinessDays: true}, day(2026, 4, 27), day(2026, 6, 1)},
+		{"public holiday moved", domain.PaymentTerms{Basis: domain.TermsBasisDayOfNextMonth, DayOfMonth: 29, BusinessDays: true}, day(2026, 9, 10), day(2026, 10, 30)},
+	}
+
+	for _, tt := range tests {
+		if got := tt.terms.DueDate(tt.issue); !got.Equal(tt.want) {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.want.Format("2006-01-02"), got.Format("2006-01-02"))
+		}
+	}
+}
+
+func TestPaymentTerms_Schedule(t *testing.T) {
+	terms := domain.PaymentTerms{Basis: domain.TermsBasisEndOfMonth, NetDays: 0, Instalments: 3, BusinessDays: true}
+
+	schedule, err := terms.Schedule(tryMoney(10000), time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// 31 Jan and 28 Feb 2026 are Saturdays; 31 Mar is a Tuesday.
+	want := []string{"2026-02-02", "2026-03-02", "2026-03-31"}
+	if len(schedule) != len(want) {
+		t.Fatalf("expected %d instalments, got %+v", len(want), schedule)
+	}
+	for i, inst := range schedule {
+		if got := inst.DueDate.Format("2006-01-02"); got != want[i] {
+			t.Errorf("instalment %d: expected %s, got %s", i+1, want[i], got)
+		}
+	}
+
+	if none, _ := (domain.PaymentTerms{NetDays: 30}).Schedule(tryMoney(10000), time.Now()); none != nil {
+		t.Errorf("expected no schedule, got %+v", none)
+	}
+}
+
+func TestIsBusinessDay(t *testing.T) {
+	tests := []struct {
+		day  time.Time
+		want bool
+	}{
+		{time.Date(2026, 10, 29, 0, 0, 0, 0, time.UTC), false}, // Cumhuriyet Bayramı
+		{time.Date(2026, 3, 20, 0, 0, 0, 0, time.UTC), false},  // Ramazan Bayramı
+		{time.Date(2026, 5, 30, 0, 0, 0, 0, time.UTC), false},  // Kurban Bayramı
+		{time.Date(2026, 5, 26, 0, 0, 0, 0, time.UTC), true},   // arife
+		{time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC), false}, // Sunday
+		{time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC), true},
+	}
+
+	for _, tt := range tests {
+		if got := domain.IsBusinessDay(tt.day); got != tt.want {
+			t.Errorf("IsBusinessDay(%s) = %v, want %v", tt.day.Format("2006-01-02"), got, tt.want)
+		}
+	}
+}
+
+func TestInvoice_EarlyPaymentDiscount(t *testing.T) {
+	issue := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	terms := domain.PaymentTerms{DiscountPercent: 2_000_000, DiscountDays: 10, NetDays: 30}
+
+	tests := []struct {
+		name   string
+		paid   int64
+		paidOn time.Time
+		want   int64
+	}{
+		{"within the window", 0, issue.AddDate(0, 0, 5), 200},
+		{"on the last day", 0, issue.AddDate(0, 0, 10).Add(10 * time.Hour), 200},
+		{"after the window", 0, issue.AddDate(0, 0, 11), 0},
+		{"on the remaining amount", 4000, issue.AddDate(0, 0, 5), 120},
+	}
+
+	for _, tt := range tests {
+		inv, _ := domain.NewInvoice("INV-001", "CUST-001", tryMoney(10000), issue, issue.AddDate(0, 0, 30))
+		inv.Terms = terms
+		if tt.paid > 0 {
+			if err := inv.AllocatePayment(tryMoney(tt.paid)); err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+		}
+		if got := inv.EarlyPaymentDiscount(tt.paidOn); got.Amount() != tt.want {
+			t.Errorf("%s: expected discount %d, got %d", tt.name, tt.want, got.Amount())
+		}
+	}
+}
+
+func TestNewPaymentAllocationPlan_EarlyPaymentDiscount(t *testing.T) {
+	inv := newTestInvoice(t, "INV-001", 10000, "TRY")
+	inv.Terms = domain.PaymentTerms{DiscountPercent: 2_000_000, DiscountDays: 10, NetDays: 30}
+
+	plan, err := domain.NewPaymentAllocationPlan("PLAN-1", "CUST-001", tryMoney(9800), time.Now(), []*domain.Invoice{inv}, fifo(t), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(plan.Lines) != 1 {
+		t.Fatalf("expected 1 line, got %+v", plan.Lines)
+	}
+	line := plan.Lines[0]
+	if line.Amount.Amount() != 9800 || line.Discount.Amount() != 200 || !line.RemainingAfter.IsZero() {
+		t.Errorf("expected 9800 settling the invoice with a 200 discount, got %+v", line)
+	}
+
+	// Short of the discounted amount, the payment is applied as is.
+	short, _ := domain.NewPaymentAllocationPlan("PLAN-2", "CUST-001", tryMoney(9000), time.Now(), []*domain.Invoice{inv}, fifo(t), nil)
+	if !short.Lines[0].Discount.IsZero() || short.Lines[0].RemainingAfter.Amount() != 1000 {
+		t.Errorf("expected no discount for a short payment, got %+v", short.Lines[0])
+	}
+
+	// Credit notes never earn the discount.
+	plain, _ := domain.NewAllocationPlan("PLAN-3", "CUST-001", tryMoney(9800), time.Now(), []*domain.Invoice{inv}, fifo(t), nil)
+	if !plain.Lines[0].Discount.IsZero() {
+		t.Errorf("expected no discount, got %+v", plain.Lines[0])
+	}
+}
+
+func TestNewDiscountAllocation(t *testing.T) {
+	inv := newTestInvoice(t, "INV-001", 10000, "TRY")
+	payment := domain.NewPayment("PAY-001", "CUST-001", tryMoney(9800), time.Now())
+
+	if _, err := domain.NewAllocation("AL-1", payment, inv, tryMoney(9800)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	discount, err := domain.NewDiscountAllocation("AL-1-ISK", payment, inv, tryMoney(200))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if inv.Status != domain.InvoiceStatusPaid || !payment.AvailableAmount.IsZero() {
+		t.Fatalf("expected a paid invoice and a used payment, got %s and %d", inv.Status, payment.AvailableAmount.Amount())
+	}
+	if !discount.IsDiscount() || !discount.IsActive() || discount.PaymentID != payment.ID || !discount.Amount.IsZero() {
+		t.Errorf("unexpected discount allocation: %+v", discount)
+	}
+
+	if _, err := discount.Reverse("AL-1-ISK-REV", payment, inv, "iptal", time.Now()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if inv.Status != domain.InvoiceStatusPartial || inv.RemainingAmount().Amount() != 200 || !payment.AvailableAmount.IsZero() {
+		t.Errorf("expected the discount to reopen 200, got %s, %d open", inv.Status, inv.RemainingAmount().Amount())
+	}
+}
@@ -0,0 +1,35 @@
+package domain_test
+
+import (
+	"carigo/internal/domain"
+	"testing"
+	"time"
+)
+
+func TestPayment_Reverse(t *testing.T) {
+	amount, _ := domain.NewMoney(1000, "TRY")
+	payment := domain.NewPayment("PAY-001", "CUST-001", amount, time.Now())
+
+	part, _ := domain.NewMoney(400, "TRY")
+	_ = payment.UseFunds(part)
+	if err := payment.Reverse("wrong customer", time.Now()); err != domain.ErrPaymentHasAllocations {
+		t.Errorf("expected ErrPaymentHasAllocations, got %v", err)
+	}
+
+	if err := payment.RestoreFunds(part); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := payment.RestoreFunds(part); err != domain.ErrInvalidAllocationReversal {
+		t.Errorf("expected ErrInvalidAllocationReversal when restoring above the amount, got %v", err)
+	}
+
+	if err := payment.Reverse("wrong customer", time.Now()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !payment.IsReversed() || !payment.AvailableAmount.IsZero() {
+		t.Errorf("expected reversed payment with nothing available")
+	}
+	if err := payment.Reverse("again", time.Now()); err != domain.ErrPaymentAlreadyReversed {
+		t.Errorf("expected ErrPaymentAlreadyReversed, got %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+type ReconciliationID string
+
+// ReconciliationStatus is the customer's answer to a balance confirmation.
+type ReconciliationStatus string
+
+const (
+	// ReconciliationStatusPending waits for the customer's answer.
+	ReconciliationStatusPending ReconciliationStatus = "PENDING"
+	// ReconciliationStatusAgreed is confirmed by the customer.
+	ReconciliationStatusAgreed ReconciliationStatus = "AGREED"
+	// ReconciliationStatusDisputed is rejected by the customer, who stated
+	// the balances their own books show.
+	ReconciliationStatusDisputed ReconciliationStatus = "DISPUTED"
+)
+
+// Reconciliation (cari mutabakat) asks a customer to confirm the balance of
+// their account at the end of AsOf. Balances are our books' balances per
+// currency at that time, as the statement shows them: positive when the
+// customer owes us. A currency without a balance is zero.
+type Reconciliation struct {
+	ID         ReconciliationID
+	CustomerID CustomerID
+	AsOf       time.Time
+	Balances   []Balance
+	Status     ReconciliationStatus
+	// Claimed are the balances the customer stated when disputing ours, in
+	// the same sign as Balances. Currencies they leave out are taken to
+	// agree with ours.
+	Claimed     []Balance
+	Note        string
+	CreatedAt   time.Time
+	RespondedAt *time.Time
+}
+
+// NewReconciliation snapshots balances of a customer's account as of the
+// calendar day of asOf, which cannot be after createdAt.
+func NewReconciliation(id ReconciliationID, customerID CustomerID, asOf time.Time, balances []Balance, createdAt time.Time) (*Reconciliation, error) {
+	asOf = RateDay(asOf)
+	if customerID == "" || asOf.IsZero() || asOf.After(RateDay(createdAt)) {
+		return nil, ErrInvalidReconciliation
+	}
+	balances, err := sortedBalances(balances)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Reconciliation{
+		ID:         id,
+		CustomerID: customerID,
+		AsOf:       asOf,
+		Balances:   balances,
+		Status:     ReconciliationStatusPending,
+		CreatedAt:  createdAt,
+	}, nil
+}
+
+// sortedBalances orders balances by currency and rejects a currency given
+// twice.
+func sortedBalances(balances []Balance) ([]Balance, error) {
+	sorted := make([]Balance, len(balances))
+	copy(sorted, balances)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].currency < sorted[j].currency
+	})
+	for i, b := range sorted {
+		if b.currency == "" || i > 0 && sorted[i-1].currency == b.currency {
+			return nil, ErrInvalidReconciliation
+		}
+	}
+	return sorted, nil
+}
+
+// Balance is our balance in currency, zero if the account has none.
+func (r *Reconciliation) Balance(currency string) Balance {
+	for _, b := range r.Balances {
+		if b.currency == currency {
+			return b
+		}
+	}
+	return Balance{currency: currency}
+}
+
+// Agree records that the customer confirmed our balances.
+func (r *Reconciliation) Agree(at time.Time) error {
+	if r.Status != ReconciliationStatusPending {
+		return ErrInvalidReconciliationState
+	}
+	r.Status = ReconciliationStatusAgreed
+	r.RespondedAt = &at
+	return nil
+}
+
+// Dispute records that the customer rejected our balances, with the
+// balances their books show and their explanation. At least one claimed
+// balance must differ from ours.
+func (r *Reconciliation) Dispute(claimed []Balance, note string, at time.Time) error {
+	if r.Status != ReconciliationStatusPending {
+		return ErrInvalidReconciliationState
+	}
+	claimed, err := sortedBalances(claimed)
+	if err != nil {
+		return err
+	}
+	differs := false
+	for _, c := range claimed {
+		if c.amount != r.Balance(c.currency).amount {
+			differs = true
+		}
+	}
+	if !differs {
+		return ErrNoReconciliationDifference
+	}
+
+	r.Status = ReconciliationStatusDisputed
+	r.Claimed = claimed
+	r.Note = note
+	r.RespondedAt = &at
+	return nil
+}
+
+// BalanceDifference is a currency in which the customer's books disagree
+// with ours. Difference is Ours less Theirs: positive when we show more debt
+// than the customer admits to.
+type BalanceDifference struct {
+	Ours       Balance
+	Theirs     Balance
+	Difference Balance
+}
+
+// Differences lists the currencies to investigate in a disputed
+// reconciliation, by currency code.
+func (r *Reconciliation) Differences() []BalanceDifference {
+	var diffs []BalanceDifference
+	for _, theirs := range r.Claimed {
+		ours := r.Balance(theirs.currency)
+		if ours.amount == theirs.amount {
+			continue
+		}
+		diffs = append(diffs, BalanceDifference{
+			Ours:       ours,
+			Theirs:     theirs,
+			Difference: Balance{amount: ours.amount - theirs.amount, currency: theirs.currency},
+		})
+	}
+	return diffs
+}
@@ -0,0 +1,101 @@
+package domain_test
+
+import (
+	"carigo/internal/domain"
+	"testing"
+	"time"
+)
+
+func balance(t *testing.T, amount int64, currency string) domain.Balance {
+	t.Helper()
+	b, err := domain.NewBalanceOf(amount, currency)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return b
+}
+
+func TestNewReconciliation(t *testing.T) {
+	now := time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC)
+	yearEnd := time.Date(2025, 12, 31, 18, 30, 0, 0, time.UTC)
+
+	r, err := domain.NewReconciliation("REC-1", "CUST-001", yearEnd, []domain.Balance{balance(t, 500, "USD"), balance(t, -1200, "TRY")}, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.Status != domain.ReconciliationStatusPending || !r.AsOf.Equal(time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected reconciliation: %+v", r)
+	}
+	if r.Balances[0].Currency() != "TRY" || r.Balance("TRY").Amount() != -1200 || r.Balance("EUR").Amount() != 0 {
+		t.Errorf("unexpected balances: %+v", r.Balances)
+	}
+
+	invalid := []struct {
+		name     string
+		customer domain.CustomerID
+		asOf     time.Time
+		balances []domain.Balance
+	}{
+		{"no customer", "", yearEnd, nil},
+		{"no date", "CUST-001", time.Time{}, nil},
+		{"future date", "CUST-001", now.AddDate(0, 0, 1), nil},
+		{"currency twice", "CUST-001", yearEnd, []domain.Balance{balance(t, 1, "TRY"), balance(t, 2, "TRY")}},
+		{"no currency", "CUST-001", yearEnd, []domain.Balance{{}}},
+	}
+	for _, tt := range invalid {
+		if _, err := domain.NewReconciliation("REC-1", tt.customer, tt.asOf, tt.balances, now); err != domain.ErrInvalidReconciliation {
+			t.Errorf("%s: expected ErrInvalidReconciliation, got %v", tt.name, err)
+		}
+	}
+}
+
+func TestReconciliation_Respond(t *testing.T) {
+	now := time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC)
+	newReconciliation := func() *domain.Reconciliation {
+		r, _ := domain.NewReconciliation("REC-1", "CUST-001", now, []domain.Balance{balance(t, 150000, "TRY"), balance(t, 500, "USD")}, now)
+		return r
+	}
+
+	agreed := newReconciliation()
+	if err := agreed.Agree(now); err != nil || agreed.Status != domain.ReconciliationStatusAgreed || agreed.RespondedAt == nil {
+		t.Fatalf("expected the reconciliation to be agreed, got %+v, %v", agreed, err)
+	}
+	if err := agreed.Dispute([]domain.Balance{balance(t, 1, "TRY")}, "", now); err != domain.ErrInvalidReconciliationState {
+		t.Errorf("expected an answered reconciliation not to be disputed, got %v", err)
+	}
+	if len(agreed.Differences()) != 0 {
+		t.Errorf("expected no differences, got %+v", agreed.Differences())
+	}
+
+	same := newReconciliation()
+	if err := same.Dispute([]domain.Balance{balance(t, 150000, "TRY")}, "", now); err != domain.ErrNoReconciliationDifference {
+		t.Errorf("expected ErrNoReconciliationDifference, got %v", err)
+	}
+
+	disputed := newReconciliation()
+	claimed := []domain.Balance{balance(t, 100, "EUR"), balance(t, 500, "USD"), balance(t, 120000, "TRY")}
+	if err := disputed.Dispute(claimed, "INV-9 bize ulaşmadı", now); err != nil || disputed.Status != domain.ReconciliationStatusDisputed {
+		t.Fatalf("expected the reconciliation to be disputed, got %s, %v", disputed.Status, err)
+	}
+	if err := disputed.Agree(now); err != domain.ErrInvalidReconciliationState {
+		t.Errorf("expected a disputed reconciliation not to be agreed, got %v", err)
+	}
+
+	diffs := disputed.Differences()
+	expected := []struct {
+		currency                 string
+		ours, theirs, difference int64
+	}{
+		{"EUR", 0, 100, -100},
+		{"TRY", 150000, 120000, 30000},
+	}
+	if len(diffs) != len(expected) {
+		t.Fatalf("expected %d differences, got %+v", len(expected), diffs)
+	}
+	for i, e := range expected {
+		d := diffs[i]
+		if d.Difference.Currency() != e.currency || d.Ours.Amount() != e.ours || d.Theirs.Amount() != e.theirs || d.Difference.Amount() != e.difference {
+			t.Errorf("expected %+v, got %+v", e, d)
+		}
+	}
+}
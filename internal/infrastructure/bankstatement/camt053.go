@@ -0,0 +1,227 @@
+package bankstatement
+
+import (
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The CAMT.053 elements below are matched by local name, so any version of
+// the camt.053.001 schema is read.
+type camtDocument struct {
+	Statements []camtStatement `xml:"BkToCstmrStmt>Stmt"`
+}
+
+type camtStatement struct {
+	IBAN    string      `xml:"Acct>Id>IBAN"`
+	Other   string      `xml:"Acct>Id>Othr>Id"`
+	Entries []camtEntry `xml:"Ntry"`
+}
+
+type camtEntry struct {
+	Amount      camtAmount    `xml:"Amt"`
+	CreditDebit string        `xml:"CdtDbtInd"`
+	Reversal    bool          `xml:"RvslInd"`
+	Status      camtStatus    `xml:"Sts"`
+	BookingDate camtDate      `xml:"BookgDt"`
+	ValueDate   camtDate      `xml:"ValDt"`
+	Reference   string        `xml:"AcctSvcrRef"`
+	Info        string        `xml:"AddtlNtryInf"`
+	Details     []camtDetails `xml:"NtryDtls>TxDtls"`
+}
+
+type camtAmount struct {
+	Value    string `xml:",chardata"`
+	Currency string `xml:"Ccy,attr"`
+}
+
+// camtStatus is a plain code up to version 6 and a Cd element after it.
+type camtStatus struct {
+	Value string `xml:",chardata"`
+	Code  string `xml:"Cd"`
+}
+
+type camtDate struct {
+	Date     string `xml:"Dt"`
+	DateTime string `xml:"DtTm"`
+}
+
+type camtDetails struct {
+	Amount      camtAmount `xml:"Amt"`
+	TxAmount    camtAmount `xml:"AmtDtls>TxAmt>Amt"`
+	Reference   string     `xml:"Refs>AcctSvcrRef"`
+	EndToEndID  string     `xml:"Refs>EndToEndId"`
+	Debtor      camtParty  `xml:"RltdPties>Dbtr"`
+	DebtorIBAN  string     `xml:"RltdPties>DbtrAcct>Id>IBAN"`
+	Remittance  []string   `xml:"RmtInf>Ustrd"`
+	Information string     `xml:"AddtlTxInf"`
+}
+
+// camtParty holds the debtor in both the flat layout and the Pty wrapper
+// introduced in version 8.
+type camtParty struct {
+	Name      string `xml:"Nm"`
+	PtyName   string `xml:"Pty>Nm"`
+	OrgID     string `xml:"Id>OrgId>Othr>Id"`
+	PrivateID string `xml:"Id>PrvtId>Othr>Id"`
+	PtyOrgID  string `xml:"Pty>Id>OrgId>Othr>Id"`
+	PtyPrvtID string `xml:"Pty>Id>PrvtId>Othr>Id"`
+}
+
+// ParseCAMT053 reads the booked credit entries of every statement in a
+// CAMT.053 document. A batch entry whose transactions carry their own amounts
+// yields a line per transaction. The document must be in UTF-8, as Parser
+// hands it over, whatever encoding its XML declaration names.
+func ParseCAMT053(r io.Reader) ([]ports.BankStatementEntry, error) {
+	var doc camtDocument
+	dec := xml.NewDecoder(r)
+	dec.CharsetReader = decodedCharset
+	if err := dec.Decode(&doc); err != nil {
+		return nil, invalid("camt.053: %v", err)
+	}
+	if len(doc.Statements) == 0 {
+		return nil, invalid("camt.053: no statement found")
+	}
+
+	var entries []ports.BankStatementEntry
+	for _, stmt := range doc.Statements {
+		account := compactIBAN(stmt.IBAN)
+		if account == "" {
+			account = strings.TrimSpace(stmt.Other)
+		}
+
+		for n, e := range stmt.Entries {
+			if e.CreditDebit != "CRDT" || e.Reversal {
+				continue
+			}
+			if status := strings.TrimSpace(e.Status.Value + e.Status.Code); status != "" && status != "BOOK" {
+				continue
+			}
+
+			booked, err := e.BookingDate.parse()
+			if err != nil {
+				return nil, invalid("camt.053: entry %d: %v", n+1, err)
+			}
+			valued, err := e.ValueDate.parse()
+			if err != nil {
+				return nil, invalid("camt.053: entry %d: %v", n+1, err)
+			}
+
+			split := len(e.Details) > 1
+			for _, d := range e.Details {
+				if d.amount().Value == "" {
+					split = false
+				}
+			}
+			if !split {
+				var d camtDetails
+				if len(e.Details) > 0 {
+					d = e.Details[0]
+				}
+				entry, err := camtEntryLine(account, e.Reference, booked, valued, e.Amount, e.Info, d)
+				if err != nil {
+					return nil, invalid("camt.053: entry %d: %v", n+1, err)
+				}
+				entries = append(entries, entry)
+				continue
+			}
+
+			for i, d := range e.Details {
+				ref := d.Reference
+				if ref == "" && e.Reference != "" {
+					ref = e.Reference + "/" + strconv.Itoa(i+1)
+				}
+				entry, err := camtEntryLine(account, ref, booked, valued, d.amount(), e.Info, d)
+				if err != nil {
+					return nil, invalid("camt.053: entry %d, transaction %d: %v", n+1, i+1, err)
+				}
+				entries = append(entries, entry)
+			}
+		}
+	}
+	return entries, nil
+}
+
+func camtEntryLine(account, ref string, booked, valued time.Time, amt camtAmount, info string, d camtDetails) (ports.BankStatementEntry, error) {
+	amount, err := domain.ParseMoney(amt.Value, amt.Currency)
+	if err != nil {
+		return ports.BankStatementEntry{}, fmt.Errorf("amount %q %s: %w", amt.Value, amt.Currency, err)
+	}
+	if booked.IsZero() {
+		booked = valued
+	}
+	if ref == "" {
+		ref = d.Reference
+	}
+	if ref == "" {
+		ref = d.EndToEndID
+	}
+
+	description := strings.Join(d.Remittance, " ")
+	if description == "" {
+		description = d.Information
+	}
+	if description == "" {
+		description = info
+	}
+
+	return ports.BankStatementEntry{
+		Account:     account,
+		Reference:   ref,
+		BookingDate: booked,
+		ValueDate:   valued,
+		Amount:      amount,
+		PayerName:   firstOf(d.Debtor.Name, d.Debtor.PtyName),
+		PayerIBAN:   compactIBAN(d.DebtorIBAN),
+		PayerTaxID:  firstOf(d.Debtor.OrgID, d.Debtor.PrivateID, d.Debtor.PtyOrgID, d.Debtor.PtyPrvtID),
+		Description: description,
+	}, nil
+}
+
+// amount is the transaction's own amount, if it has one.
+func (d camtDetails) amount() camtAmount {
+	if d.Amount.Value != "" {
+		return d.Amount
+	}
+	return d.TxAmount
+}
+
+func (d camtDate) parse() (time.Time, error) {
+	switch {
+	case d.Date != "":
+		return time.Parse("2006-01-02", strings.TrimSpace(d.Date))
+	case d.DateTime != "":
+		t, err := time.Parse(time.RFC3339, strings.TrimSpace(d.DateTime))
+		if err != nil {
+			// ISO 20022 date-times may come without a zone.
+			t, err = time.Parse("2006-01-02T15:04:05", strings.TrimSpace(d.DateTime))
+		}
+		return t, err
+	}
+	return time.Time{}, nil
+}
+
+func firstOf(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// decodedCharset accepts the Turkish encodings banks declare their exports
+// in. Parser has already turned the bytes into UTF-8, so the input is read as
+// it is.
+func decodedCharset(label string, input io.Reader) (io.Reader, error) {
+	switch strings.ToLower(label) {
+	case "utf8", "iso-8859-9", "iso8859-9", "latin5", "windows-1254", "cp1254":
+		return input, nil
+	}
+	return nil, fmt.Errorf("unsupported encoding %q", label)
+}
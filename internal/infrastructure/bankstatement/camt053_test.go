@@ -0,0 +1,238 @@
+package bankstatement_test
+
+import (
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"carigo/internal/infrastructure/bankstatement"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// camtV2 is a camt.053.001.02 statement: plain status codes and the debtor's
+// name and ID directly under Dbtr.
+const camtV2 = `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02">
+  <BkToCstmrStmt>
+    <Stmt>
+      <Acct><Id><IBAN>TR33 0006 1005 1978 6457 8413 26</IBAN></Id></Acct>
+      <Ntry>
+        <Amt Ccy="TRY">1250.00</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <Sts>BOOK</Sts>
+        <BookgDt><Dt>2026-03-02</Dt></BookgDt>
+        <ValDt><Dt>2026-03-03</Dt></ValDt>
+        <AcctSvcrRef>2026030200001</AcctSvcrRef>
+        <NtryDtls><TxDtls>
+          <Refs><EndToEndId>E2E-1</EndToEndId></Refs>
+          <RltdPties>
+            <Dbtr><Nm>ACME Ticaret A.Ş.</Nm><Id><OrgId><Othr><Id>1234567890</Id></Othr></OrgId></Id></Dbtr>
+            <DbtrAcct><Id><IBAN>TR12 0006 4000 0011 2345 6789 01</IBAN></Id></DbtrAcct>
+          </RltdPties>
+          <RmtInf><Ustrd>INV-001</Ustrd><Ustrd>ödemesi</Ustrd></RmtInf>
+        </TxDtls></NtryDtls>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="TRY">400.00</Amt>
+        <CdtDbtInd>DBIT</CdtDbtInd>
+        <Sts>BOOK</Sts>
+        <BookgDt><Dt>2026-03-02</Dt></BookgDt>
+        <AcctSvcrRef>2026030200002</AcctSvcrRef>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="TRY">90.00</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <RvslInd>true</RvslInd>
+        <Sts>BOOK</Sts>
+        <BookgDt><Dt>2026-03-02</Dt></BookgDt>
+        <AcctSvcrRef>2026030200003</AcctSvcrRef>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="TRY">75.00</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <Sts>PDNG</Sts>
+        <BookgDt><Dt>2026-03-02</Dt></BookgDt>
+        <AcctSvcrRef>2026030200004</AcctSvcrRef>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="TRY">100.00</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <Sts>BOOK</Sts>
+        <BookgDt><DtTm>2026-03-04T10:15:00</DtTm></BookgDt>
+        <AcctSvcrRef>NONREF</AcctSvcrRef>
+        <AddtlNtryInf>GELEN EFT</AddtlNtryInf>
+        <NtryDtls><TxDtls><Refs><EndToEndId>NOTPROVIDED</EndToEndId></Refs></TxDtls></NtryDtls>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="TRY">300.00</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <Sts>BOOK</Sts>
+        <BookgDt><Dt>2026-03-05</Dt></BookgDt>
+        <AcctSvcrRef>BATCH-7</AcctSvcrRef>
+        <NtryDtls>
+          <TxDtls>
+            <Amt Ccy="TRY">100.00</Amt>
+            <RltdPties><Dbtr><Nm>Beta Ltd</Nm></Dbtr></RltdPties>
+            <AddtlTxInf>INV-002</AddtlTxInf>
+          </TxDtls>
+          <TxDtls>
+            <AmtDtls><TxAmt><Amt Ccy="TRY">200.00</Amt></TxAmt></AmtDtls>
+            <Refs><AcctSvcrRef>TX-2</AcctSvcrRef></Refs>
+            <RltdPties><Dbtr><Nm>Gama AŞ</Nm></Dbtr></RltdPties>
+            <RmtInf><Ustrd>INV-003</Ustrd></RmtInf>
+          </TxDtls>
+        </NtryDtls>
+      </Ntry>
+    </Stmt>
+  </BkToCstmrStmt>
+</Document>`
+
+// camtV8 is a camt.053.001.08 statement: the status in a Cd element, the
+// debtor wrapped in Pty and the account given by another ID than an IBAN.
+const camtV8 = `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.08">
+  <BkToCstmrStmt>
+    <Stmt>
+      <Acct><Id><Othr><Id>6457841326</Id></Othr></Id></Acct>
+      <Ntry>
+        <Amt Ccy="EUR">500.00</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <Sts><Cd>BOOK</Cd></Sts>
+        <BookgDt><Dt>2026-04-01</Dt></BookgDt>
+        <ValDt><DtTm>2026-04-01T00:00:00+03:00</DtTm></ValDt>
+        <AcctSvcrRef>EUR-1</AcctSvcrRef>
+        <NtryDtls><TxDtls>
+          <RltdPties>
+            <Dbtr><Pty><Nm>Ayşe Yılmaz</Nm><Id><PrvtId><Othr><Id>12345678901</Id></Othr></PrvtId></Id></Pty></Dbtr>
+            <DbtrAcct><Id><IBAN>DE89370400440532013000</IBAN></Id></DbtrAcct>
+          </RltdPties>
+        </TxDtls></NtryDtls>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="EUR">80.00</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <Sts><Cd>PDNG</Cd></Sts>
+        <BookgDt><Dt>2026-04-01</Dt></BookgDt>
+        <AcctSvcrRef>EUR-2</AcctSvcrRef>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="EUR">60.00</Amt>
+        <CdtDbtInd>DBIT</CdtDbtInd>
+        <Sts><Cd>BOOK</Cd></Sts>
+        <BookgDt><Dt>2026-04-01</Dt></BookgDt>
+        <AcctSvcrRef>EUR-3</AcctSvcrRef>
+      </Ntry>
+    </Stmt>
+  </BkToCstmrStmt>
+</Document>`
+
+func TestParseCAMT053_Version2(t *testing.T) {
+	entries, err := bankstatement.ParseCAMT053(strings.NewReader(camtV2))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	account := "TR330006100519786457841326"
+	expectEntries(t, entries, []ports.BankStatementEntry{
+		{Account: account, Reference: "2026030200001", BookingDate: date(2026, 3, 2), ValueDate: date(2026, 3, 3),
+			Amount: money(125000, "TRY"), PayerName: "ACME Ticaret A.Ş.", PayerIBAN: "TR120006400000112345678901",
+			PayerTaxID: "1234567890", Description: "INV-001 ödemesi"},
+		// The placeholder reference is kept for the bank line to replace.
+		{Account: account, Reference: "NONREF", BookingDate: time.Date(2026, 3, 4, 10, 15, 0, 0, time.UTC),
+			Amount: money(10000, "TRY"), Description: "GELEN EFT"},
+		// A batch is split into its transactions.
+		{Account: account, Reference: "BATCH-7/1", BookingDate: date(2026, 3, 5), Amount: money(10000, "TRY"),
+			PayerName: "Beta Ltd", Description: "INV-002"},
+		{Account: account, Reference: "TX-2", BookingDate: date(2026, 3, 5), Amount: money(20000, "TRY"),
+			PayerName: "Gama AŞ", Description: "INV-003"},
+	})
+}
+
+func TestParseCAMT053_Version8(t *testing.T) {
+	entries, err := bankstatement.ParseCAMT053(strings.NewReader(camtV8))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	valued := time.Date(2026, 4, 1, 0, 0, 0, 0, time.FixedZone("", 3*60*60))
+	expectEntries(t, entries, []ports.BankStatementEntry{
+		{Account: "6457841326", Reference: "EUR-1", BookingDate: date(2026, 4, 1), ValueDate: valued,
+			Amount: money(50000, "EUR"), PayerName: "Ayşe Yılmaz", PayerIBAN: "DE89370400440532013000", PayerTaxID: "12345678901"},
+	})
+}
+
+func TestParseCAMT053_BatchWithoutTransactionAmounts(t *testing.T) {
+	// Transactions without their own amounts leave the entry whole, with
+	// the first transaction's details.
+	doc := `<Document><BkToCstmrStmt><Stmt><Acct><Id><IBAN>TR1</IBAN></Id></Acct>
+		<Ntry><Amt Ccy="TRY">300.00</Amt><CdtDbtInd>CRDT</CdtDbtInd><Sts>BOOK</Sts>
+		<BookgDt><Dt>2026-03-05</Dt></BookgDt><AcctSvcrRef>BATCH-8</AcctSvcrRef>
+		<NtryDtls><TxDtls><RltdPties><Dbtr><Nm>Beta Ltd</Nm></Dbtr></RltdPties></TxDtls>
+		<TxDtls><RltdPties><Dbtr><Nm>Gama AŞ</Nm></Dbtr></RltdPties></TxDtls></NtryDtls></Ntry>
+		</Stmt></BkToCstmrStmt></Document>`
+	entries, err := bankstatement.ParseCAMT053(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectEntries(t, entries, []ports.BankStatementEntry{
+		{Account: "TR1", Reference: "BATCH-8", BookingDate: date(2026, 3, 5), Amount: money(30000, "TRY"), PayerName: "Beta Ltd"},
+	})
+}
+
+func TestParseCAMT053_TurkishEncoding(t *testing.T) {
+	doc := `<?xml version="1.0" encoding="ISO-8859-9"?>
+<Document><BkToCstmrStmt><Stmt><Acct><Id><IBAN>TR1</IBAN></Id></Acct>
+	<Ntry><Amt Ccy="TRY">75.00</Amt><CdtDbtInd>CRDT</CdtDbtInd><Sts>BOOK</Sts>
+	<BookgDt><Dt>2026-03-06</Dt></BookgDt><AcctSvcrRef>ZR-1</AcctSvcrRef>
+	<NtryDtls><TxDtls><RltdPties><Dbtr><Nm>ŞAHİN AĞAÇ</Nm></Dbtr></RltdPties>
+	<RmtInf><Ustrd>INV-004 ödemesi</Ustrd></RmtInf></TxDtls></NtryDtls></Ntry>
+</Stmt></BkToCstmrStmt></Document>`
+	want := []ports.BankStatementEntry{
+		{Account: "TR1", Reference: "ZR-1", BookingDate: date(2026, 3, 6), Amount: money(7500, "TRY"),
+			PayerName: "ŞAHİN AĞAÇ", Description: "INV-004 ödemesi"},
+	}
+
+	// A Ziraat export saved in the ISO-8859-9 it declares.
+	latin5 := strings.NewReplacer("Ş", "\xde", "İ", "\xdd", "Ğ", "\xd0", "Ç", "\xc7", "ö", "\xf6").Replace(doc)
+	format, entries, err := bankstatement.NewParser(nil).Parse(context.Background(), "", "", strings.NewReader(latin5))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if format != domain.BankStatementCAMT053 {
+		t.Errorf("expected CAMT.053, got %s", format)
+	}
+	expectEntries(t, entries, want)
+
+	// Converted to UTF-8 with the declaration left as it was, as some
+	// banking portals serve it.
+	for _, label := range []string{"ISO-8859-9", "windows-1254"} {
+		utf8 := strings.Replace(doc, "ISO-8859-9", label, 1)
+		entries, err = bankstatement.ParseCAMT053(strings.NewReader(utf8))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", label, err)
+		}
+		expectEntries(t, entries, want)
+	}
+}
+
+func TestParseCAMT053_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"not xml":      "Tarih;Tutar",
+		"no statement": `<Document><BkToCstmrStmt></BkToCstmrStmt></Document>`,
+		"unsupported encoding": `<?xml version="1.0" encoding="KOI8-R"?>
+			<Document><BkToCstmrStmt><Stmt></Stmt></BkToCstmrStmt></Document>`,
+		"bad date": `<Document><BkToCstmrStmt><Stmt><Ntry><Amt Ccy="TRY">1.00</Amt><CdtDbtInd>CRDT</CdtDbtInd>
+			<BookgDt><Dt>02.03.2026</Dt></BookgDt></Ntry></Stmt></BkToCstmrStmt></Document>`,
+		"bad currency": `<Document><BkToCstmrStmt><Stmt><Ntry><Amt Ccy="XXX">1.00</Amt><CdtDbtInd>CRDT</CdtDbtInd>
+			<BookgDt><Dt>2026-03-02</Dt></BookgDt></Ntry></Stmt></BkToCstmrStmt></Document>`,
+	}
+	for name, doc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := bankstatement.ParseCAMT053(strings.NewReader(doc)); !errors.Is(err, domain.ErrInvalidBankStatement) {
+				t.Errorf("expected ErrInvalidBankStatement, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package bankstatement
+
+import (
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// CSVProfile describes the CSV export of one bank. Columns are found by
+// their header, compared without case, so rows above the header, such as
+// the account summary banks print there, are skipped, as are rows below the
+// transactions whose date does not parse.
+type CSVProfile struct {
+	// Delimiter separates the fields; the default is ";".
+	Delimiter string `json:"delimiter"`
+	// DateLayout is a Go time layout; the default is "02.01.2006". Dates
+	// followed by a time of day are read by their date.
+	DateLayout string `json:"date_layout"`
+	// Currency is used when there is no currency column; the default is TRY.
+	Currency string     `json:"currency"`
+	Columns  CSVColumns `json:"columns"`
+}
+
+// CSVColumns are the headers of the columns in a CSV export. Date and either
+// Amount, a signed amount, or Credit are required; the others may be empty.
+type CSVColumns struct {
+	Date        string `json:"date"`
+	ValueDate   string `json:"value_date"`
+	Amount      string `json:"amount"`
+	Credit      string `json:"credit"`
+	Currency    string `json:"currency"`
+	Description string `json:"description"`
+	Reference   string `json:"reference"`
+	PayerName   string `json:"payer_name"`
+	PayerIBAN   string `json:"payer_iban"`
+	PayerTaxID  string `json:"payer_tax_id"`
+	Account     string `json:"account"`
+}
+
+// DefaultCSVProfiles are the layouts known without configuration. "generic"
+// reads the column names most Turkish internet branches use.
+var DefaultCSVProfiles = map[string]CSVProfile{
+	"generic": {
+		Columns: CSVColumns{
+			Date:        "Tarih",
+			ValueDate:   "Valör",
+			Amount:      "Tutar",
+			Credit:      "Alacak",
+			Currency:    "Döviz",
+			Description: "Açıklama",
+			Reference:   "Dekont No",
+			PayerName:   "Gönderen",
+			PayerIBAN:   "Gönderen IBAN",
+			PayerTaxID:  "Gönderen VKN",
+			Account:     "IBAN",
+		},
+	},
+	"garanti": {
+		Columns: CSVColumns{
+			Date:        "Tarih",
+			Amount:      "Tutar",
+			Description: "Açıklama",
+			Reference:   "Dekont No",
+		},
+	},
+	"isbank": {
+		DateLayout: "02/01/2006",
+		Columns: CSVColumns{
+			Date:        "Tarih/Saat",
+			Amount:      "İşlem Tutarı",
+			Description: "Açıklama",
+			Reference:   "İşlem No",
+		},
+	},
+}
+
+// LoadCSVProfiles reads extra profiles from a JSON file that maps profile
+// names to CSVProfile objects.
+func LoadCSVProfiles(path string) (map[string]CSVProfile, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var profiles map[string]CSVProfile
+	if err := json.Unmarshal(raw, &profiles); err != nil {
+		return nil, err
+	}
+	for name, p := range profiles {
+		if p.Columns.Date == "" || (p.Columns.Amount == "" && p.Columns.Credit == "") {
+			return nil, errors.New("bank CSV profile " + name + " needs a date and an amount or credit column")
+		}
+	}
+	return profiles, nil
+}
+
+// ParseCSV reads the incoming transfers of a CSV export laid out as profile
+// says. Rows with a negative or empty amount are outgoing and left out.
+func ParseCSV(r io.Reader, profile CSVProfile) ([]ports.BankStatementEntry, error) {
+	delimiter := ';'
+	if profile.Delimiter != "" {
+		delimiter = []rune(profile.Delimiter)[0]
+	}
+	layout := profile.DateLayout
+	if layout == "" {
+		layout = "02.01.2006"
+	}
+	currency := profile.Currency
+	if currency == "" {
+		currency = "TRY"
+	}
+
+	cr := csv.NewReader(r)
+	cr.Comma = delimiter
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+
+	var (
+		entries []ports.BankStatementEntry
+		cols    map[string]int
+	)
+	for n := 1; ; n++ {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, invalid("csv: %v", err)
+		}
+
+		if cols == nil {
+			cols = csvHeader(row, profile.Columns)
+			continue
+		}
+		field := func(name string) string {
+			if i, ok := cols[name]; ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+
+		booked, err := parseCSVDate(field("date"), layout)
+		if err != nil {
+			continue
+		}
+		valued, _ := parseCSVDate(field("value_date"), layout)
+
+		rowCurrency := strings.ToUpper(field("currency"))
+		switch rowCurrency {
+		case "":
+			rowCurrency = currency
+		case "TL", "YTL":
+			rowCurrency = "TRY"
+		}
+
+		raw := field("credit")
+		if _, ok := cols["credit"]; !ok {
+			raw = field("amount")
+		}
+		raw = strings.TrimSpace(strings.TrimSuffix(strings.TrimSuffix(raw, "TL"), rowCurrency))
+		if raw == "" || strings.HasPrefix(raw, "-") {
+			continue
+		}
+		amount, err := domain.ParseMoney(strings.TrimPrefix(raw, "+"), rowCurrency)
+		if err != nil {
+			return nil, invalid("csv: row %d: amount %q %s: %v", n, raw, rowCurrency, err)
+		}
+		if amount.IsZero() {
+			continue
+		}
+
+		entries = append(entries, ports.BankStatementEntry{
+			Account:     compactIBAN(field("account")),
+			Reference:   field("reference"),
+			BookingDate: booked,
+			ValueDate:   valued,
+			Amount:      amount,
+			PayerName:   field("payer_name"),
+			PayerIBAN:   compactIBAN(field("payer_iban")),
+			PayerTaxID:  field("payer_tax_id"),
+			Description: field("description"),
+		})
+	}
+	if cols == nil {
+		return nil, invalid("csv: no header with the columns %q and %q", profile.Columns.Date, firstOf(profile.Columns.Credit, profile.Columns.Amount))
+	}
+	return entries, nil
+}
+
+// csvHeader maps the profile's columns to their index if row is the header,
+// which is the first row with the date column and an amount column. It is
+// nil for any other row.
+func csvHeader(row []string, columns CSVColumns) map[string]int {
+	names := map[string]string{
+		"date":         columns.Date,
+		"value_date":   columns.ValueDate,
+		"amount":       columns.Amount,
+		"credit":       columns.Credit,
+		"currency":     columns.Currency,
+		"description":  columns.Description,
+		"reference":    columns.Reference,
+		"payer_name":   columns.PayerName,
+		"payer_iban":   columns.PayerIBAN,
+		"payer_tax_id": columns.PayerTaxID,
+		"account":      columns.Account,
+	}
+
+	cols := map[string]int{}
+	for i, cell := range row {
+		for key, name := range names {
+			if name != "" && fold(cell) == fold(name) {
+				cols[key] = i
+			}
+		}
+	}
+	_, date := cols["date"]
+	_, amount := cols["amount"]
+	_, credit := cols["credit"]
+	if !date || (!amount && !credit) {
+		return nil
+	}
+	return cols
+}
+
+// fold lower-cases with the Turkish rules, so that "İşlem" and "işlem" match.
+func fold(s string) string {
+	return strings.ToLowerSpecial(unicode.TurkishCase, strings.TrimSpace(s))
+}
+
+func parseCSVDate(s, layout string) (time.Time, error) {
+	if len(s) > len(layout) {
+		s = s[:len(layout)]
+	}
+	return time.Parse(layout, s)
+}
@@ -0,0 +1,82 @@
+package bankstatement_test
+
+import (
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"carigo/internal/infrastructure/bankstatement"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseCSV_SignedAmount(t *testing.T) {
+	// An account summary above the header, a total below the transactions,
+	// and the headers in capitals.
+	file := "Hesap Özeti;;;;;;;\n" +
+		"IBAN;TR33 0006 1005 1978 6457 8413 26;;;;;;\n" +
+		"TARİH;VALÖR;TUTAR;DÖVİZ;AÇIKLAMA;DEKONT NO;GÖNDEREN;GÖNDEREN IBAN\n" +
+		"02.03.2026 14:35;03.03.2026;1.250,00;TL;INV-001 ödemesi;D-1;ACME A.Ş.;TR12 0006 4000 0011 2345 6789 01\n" +
+		"02.03.2026;;-400,00;TL;Kira;D-2;;\n" +
+		"03.03.2026;;+75,50 TL;;Havale;D-3;Ayşe Yılmaz;\n" +
+		"03.03.2026;;0,00;TL;Masraf iadesi;D-4;;\n" +
+		"04.03.2026;;200,00;USD;;D-5;Beta Ltd;\n" +
+		"Toplam;;1.125,50;;;;;\n"
+	entries, err := bankstatement.ParseCSV(strings.NewReader(file), bankstatement.DefaultCSVProfiles["generic"])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectEntries(t, entries, []ports.BankStatementEntry{
+		{Reference: "D-1", BookingDate: date(2026, 3, 2), ValueDate: date(2026, 3, 3), Amount: money(125000, "TRY"),
+			PayerName: "ACME A.Ş.", PayerIBAN: "TR120006400000112345678901", Description: "INV-001 ödemesi"},
+		{Reference: "D-3", BookingDate: date(2026, 3, 3), Amount: money(7550, "TRY"), PayerName: "Ayşe Yılmaz", Description: "Havale"},
+		{Reference: "D-5", BookingDate: date(2026, 3, 4), Amount: money(20000, "USD"), PayerName: "Beta Ltd"},
+	})
+}
+
+func TestParseCSV_CreditColumn(t *testing.T) {
+	// With both a credit and a signed amount column, the credit column
+	// decides; debits leave it empty.
+	file := "Tarih;Açıklama;Borç;Alacak;Tutar;Bakiye;IBAN\n" +
+		"05.03.2026;EFT INV-002;;300,00;300,00;1.300,00;TR1\n" +
+		"05.03.2026;Fatura ödemesi;150,00;;-150,00;1.150,00;TR1\n" +
+		"06.03.2026;Düzeltme;;;25,00;1.175,00;TR1\n"
+	entries, err := bankstatement.ParseCSV(strings.NewReader(file), bankstatement.DefaultCSVProfiles["generic"])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectEntries(t, entries, []ports.BankStatementEntry{
+		{Account: "TR1", BookingDate: date(2026, 3, 5), Amount: money(30000, "TRY"), Description: "EFT INV-002"},
+	})
+}
+
+func TestParseCSV_Windows1254(t *testing.T) {
+	// An İş Bankası export as the branch saves it: Windows-1254, the date
+	// with a time of day and "İşlem Tutarı" in capitals.
+	file := "Tarih/Saat;\xdd\xdeLEM TUTARI;A\xc7IKLAMA;\xdd\xdelem No\r\n" +
+		"02/03/2026 09:12:44;1.500,00;\xdeAH\xddN A\xd0A\xc7 \xd6DEME;IS-1\r\n" +
+		"02/03/2026 11:00:00;-20,00;EFT \xfccreti;IS-2\r\n"
+	_, entries, err := bankstatement.NewParser(nil).Parse(context.Background(), domain.BankStatementCSV, "isbank", strings.NewReader(file))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectEntries(t, entries, []ports.BankStatementEntry{
+		{Reference: "IS-1", BookingDate: date(2026, 3, 2), Amount: money(150000, "TRY"), Description: "ŞAHİN AĞAÇ ÖDEME"},
+	})
+}
+
+func TestParseCSV_Invalid(t *testing.T) {
+	generic := bankstatement.DefaultCSVProfiles["generic"]
+	tests := map[string]string{
+		"no header":    "Date;Amount\n02.03.2026;10,00\n",
+		"bad amount":   "Tarih;Tutar\n02.03.2026;10,0,0\n",
+		"bad currency": "Tarih;Tutar;Döviz\n02.03.2026;10,00;XXX\n",
+	}
+	for name, file := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := bankstatement.ParseCSV(strings.NewReader(file), generic); !errors.Is(err, domain.ErrInvalidBankStatement) {
+				t.Errorf("expected ErrInvalidBankStatement, got %v", err)
+			}
+		})
+	}
+}
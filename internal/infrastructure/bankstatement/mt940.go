@@ -0,0 +1,188 @@
+package bankstatement
+
+import (
+	"bufio"
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+type mt940Field struct {
+	tag, value string
+}
+
+// ParseMT940 reads the credit lines of every statement in an MT940 file.
+// The :86: information that follows a :61: line becomes its description;
+// when it is structured in ?NN subfields, the payer's name and IBAN are read
+// from it as well.
+func ParseMT940(r io.Reader) ([]ports.BankStatementEntry, error) {
+	fields, err := mt940Fields(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		entries  []ports.BankStatementEntry
+		account  string
+		currency string
+		found    bool
+	)
+	for i, f := range fields {
+		switch f.tag {
+		case "20":
+			found = true
+			account, currency = "", ""
+		case "25":
+			account = compactIBAN(f.value)
+		case "60F", "60M":
+			// D/C mark, YYMMDD date, then the currency of the statement.
+			if len(f.value) < 10 {
+				return nil, invalid("mt940: opening balance %q", f.value)
+			}
+			currency = strings.ToUpper(f.value[7:10])
+		case "61":
+			if currency == "" {
+				return nil, invalid("mt940: statement line before the opening balance")
+			}
+			line, credit, err := parseMT940Line(f.value, currency)
+			if err != nil {
+				return nil, err
+			}
+			if !credit {
+				continue
+			}
+			line.Account = account
+			if i+1 < len(fields) && fields[i+1].tag == "86" {
+				applyMT940Info(&line, fields[i+1].value)
+			}
+			entries = append(entries, line)
+		}
+	}
+	if !found {
+		return nil, invalid("mt940: no statement found")
+	}
+	return entries, nil
+}
+
+// mt940Fields splits the file into its tagged fields, joining continuation
+// lines to the field they belong to and skipping the SWIFT header blocks.
+func mt940Fields(r io.Reader) ([]mt940Field, error) {
+	var fields []mt940Field
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), "\r ")
+		if i := strings.Index(line, "{4:"); i >= 0 {
+			line = line[i+3:]
+		}
+		if line == "" || line == "-" || strings.HasPrefix(line, "-}") || strings.HasPrefix(line, "{") {
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			if end := strings.Index(line[1:], ":"); end > 0 {
+				fields = append(fields, mt940Field{tag: line[1 : end+1], value: line[end+2:]})
+				continue
+			}
+		}
+		if len(fields) > 0 {
+			fields[len(fields)-1].value += "\n" + line
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// mt940Line is a :61: statement line: value date YYMMDD, optional entry date
+// MMDD, D/C/RD/RC mark, optional funds code, amount with a decimal comma,
+// transaction type, customer reference and, after //, the bank's reference.
+var mt940Line = regexp.MustCompile(`^(\d{6})(\d{4})?(RC|RD|C|D)([A-Z])?([\d,]+)([A-Z][A-Z0-9]{3})([^/\n]*)(?://([^\n]*))?`)
+
+func parseMT940Line(value, currency string) (ports.BankStatementEntry, bool, error) {
+	m := mt940Line.FindStringSubmatch(value)
+	if m == nil {
+		return ports.BankStatementEntry{}, false, invalid("mt940: statement line %q", firstLine(value))
+	}
+	// Debits and reversals are not incoming transfers.
+	if m[3] != "C" {
+		return ports.BankStatementEntry{}, false, nil
+	}
+
+	valued, err := time.Parse("060102", m[1])
+	if err != nil {
+		return ports.BankStatementEntry{}, false, invalid("mt940: value date %q", m[1])
+	}
+	booked := valued
+	if m[2] != "" {
+		entry, err := time.Parse("0102", m[2])
+		if err != nil {
+			return ports.BankStatementEntry{}, false, invalid("mt940: entry date %q", m[2])
+		}
+		booked = time.Date(valued.Year(), entry.Month(), entry.Day(), 0, 0, 0, 0, time.UTC)
+		// An entry booked in late December for a January value date, or the
+		// other way round, belongs to the neighbouring year.
+		if diff := booked.Sub(valued); diff > 180*24*time.Hour {
+			booked = booked.AddDate(-1, 0, 0)
+		} else if diff < -180*24*time.Hour {
+			booked = booked.AddDate(1, 0, 0)
+		}
+	}
+
+	amount, err := domain.ParseMoney(m[5], currency)
+	if err != nil {
+		return ports.BankStatementEntry{}, false, invalid("mt940: amount %q %s: %v", m[5], currency, err)
+	}
+
+	ref := strings.TrimSpace(m[8])
+	if ref == "" {
+		ref = strings.TrimSpace(m[7])
+	}
+	return ports.BankStatementEntry{
+		Reference:   ref,
+		BookingDate: booked,
+		ValueDate:   valued,
+		Amount:      amount,
+	}, true, nil
+}
+
+// mt940Subfield is a ?NN subfield of structured :86: information.
+var mt940Subfield = regexp.MustCompile(`\?(\d{2})([^?]*)`)
+
+// applyMT940Info fills the description, and the payer where the bank gives
+// it, from :86: information. Structured information keeps its purpose in
+// subfields 20-29 and 60-63, the payer's name in 32-33 and their IBAN in 31
+// or 38.
+func applyMT940Info(line *ports.BankStatementEntry, info string) {
+	subfields := mt940Subfield.FindAllStringSubmatch(strings.ReplaceAll(info, "\n", ""), -1)
+	if len(subfields) == 0 {
+		line.Description = strings.ReplaceAll(info, "\n", " ")
+		return
+	}
+
+	var purpose, name []string
+	for _, sf := range subfields {
+		value := sf[2]
+		switch code := sf[1]; {
+		case code >= "20" && code <= "29", code >= "60" && code <= "63":
+			purpose = append(purpose, value)
+		case code == "32", code == "33":
+			name = append(name, value)
+		case code == "31", code == "38":
+			if iban := compactIBAN(value); len(iban) > 4 && iban[0] >= 'A' && iban[0] <= 'Z' {
+				line.PayerIBAN = iban
+			}
+		}
+	}
+	line.Description = strings.TrimSpace(strings.Join(purpose, ""))
+	line.PayerName = strings.TrimSpace(strings.Join(name, ""))
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
@@ -0,0 +1,91 @@
+package bankstatement_test
+
+import (
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"carigo/internal/infrastructure/bankstatement"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// mt940Statement holds two statements around the turn of the year, with
+// lines booked in the other year than their value date, structured and free
+// :86: information, a debit, a reversal and a line without a bank reference.
+const mt940Statement = `{1:F01TGBATRISAXXX0000000000}{2:O9401200260105TGBATRISAXXX00000000002601051200N}{4:
+:20:STMT-2025-12
+:25:TR33 0006 1005 1978 6457 8413 26
+:28C:1/1
+:60F:C251229TRY1000,00
+:61:2601021231C1250,00NTRFINV-001//2025123100001
+:86:?00EFT?20INV-001 ?21ODEMESI?31123456?32ACME TICARET?33 A.S.?38TR12 0006 4000 0011 2345 6789 01
+:61:2512310102CR500,00NTRFNONREF
+:86:GELEN EFT
+ABC LTD
+:61:2512311231D400,00NTRFKIRA//2025123100003
+:86:?20KIRA ARALIK
+:61:2512311231RC90,00NTRFREV//2025123100004
+:62F:C251231TRY1350,00
+-}
+{4:
+:20:STMT-EUR
+:25:DE89370400440532013000
+:60M:C260101EUR0,00
+:61:260105C75,5NMSC//EUR-1
+:62F:C260105EUR75,50
+-}`
+
+func TestParseMT940(t *testing.T) {
+	entries, err := bankstatement.ParseMT940(strings.NewReader(mt940Statement))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	account := "TR330006100519786457841326"
+	expectEntries(t, entries, []ports.BankStatementEntry{
+		// Value date in January, booked on the last day of December.
+		{Account: account, Reference: "2025123100001", BookingDate: date(2025, 12, 31), ValueDate: date(2026, 1, 2),
+			Amount: money(125000, "TRY"), PayerName: "ACME TICARET A.S.", PayerIBAN: "TR120006400000112345678901",
+			Description: "INV-001 ODEMESI"},
+		// Value date in December, booked in January; the customer reference
+		// stands in for the missing bank reference, and the funds code is
+		// not part of the amount.
+		{Account: account, Reference: "NONREF", BookingDate: date(2026, 1, 2), ValueDate: date(2025, 12, 31),
+			Amount: money(50000, "TRY"), Description: "GELEN EFT ABC LTD"},
+		{Account: "DE89370400440532013000", Reference: "EUR-1", BookingDate: date(2026, 1, 5), ValueDate: date(2026, 1, 5),
+			Amount: money(7550, "EUR")},
+	})
+}
+
+func TestParseMT940_Windows1254(t *testing.T) {
+	// "ÖDEME İŞLEMİ" and "ŞAHİN AĞAÇ" as a Turkish branch exports them.
+	file := ":20:STMT\r\n:25:TR1\r\n:60F:C260301TRY0,00\r\n" +
+		":61:2603020302C100,00NTRFREF//BNK-1\r\n" +
+		":86:?20\xd6DEME \xdd\xdeLEM\xdd?32\xdeAH\xddN A\xd0A\xc7\r\n"
+	_, entries, err := bankstatement.NewParser(nil).Parse(context.Background(), "", "", strings.NewReader(file))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectEntries(t, entries, []ports.BankStatementEntry{
+		{Account: "TR1", Reference: "BNK-1", BookingDate: date(2026, 3, 2), ValueDate: date(2026, 3, 2),
+			Amount: money(10000, "TRY"), PayerName: "ŞAHİN AĞAÇ", Description: "ÖDEME İŞLEMİ"},
+	})
+}
+
+func TestParseMT940_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"no statement":          "Tarih;Tutar\n",
+		"line before balance":   ":20:STMT\n:25:TR1\n:61:260302C100,00NTRFREF\n",
+		"unreadable line":       ":20:STMT\n:25:TR1\n:60F:C260301TRY0,00\n:61:0302C100,00NTRFREF\n",
+		"short opening balance": ":20:STMT\n:25:TR1\n:60F:C2603\n",
+		"bad value date":        ":20:STMT\n:25:TR1\n:60F:C260301TRY0,00\n:61:261302C100,00NTRFREF\n",
+	}
+	for name, file := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := bankstatement.ParseMT940(strings.NewReader(file)); !errors.Is(err, domain.ErrInvalidBankStatement) {
+				t.Errorf("expected ErrInvalidBankStatement, got %v", err)
+			}
+		})
+	}
+}
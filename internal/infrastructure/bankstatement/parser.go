@@ -0,0 +1,121 @@
+// Package bankstatement reads the account statements banks export for their
+// corporate customers: ISO 20022 CAMT.053 XML, SWIFT MT940 and the CSV files
+// of Turkish internet branches. Only incoming transfers are returned; debits,
+// reversals and pending entries are left out.
+package bankstatement
+
+import (
+	"bytes"
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Parser dispatches a statement to the reader of its format.
+type Parser struct {
+	profiles map[string]CSVProfile
+}
+
+// NewParser knows the built-in CSV profiles and the given ones, which
+// replace built-in profiles of the same name.
+func NewParser(profiles map[string]CSVProfile) *Parser {
+	p := &Parser{profiles: map[string]CSVProfile{}}
+	for name, profile := range DefaultCSVProfiles {
+		p.profiles[name] = profile
+	}
+	for name, profile := range profiles {
+		p.profiles[strings.ToLower(name)] = profile
+	}
+	return p
+}
+
+func (p *Parser) Parse(ctx context.Context, format domain.BankStatementFormat, profile string, r io.Reader) (domain.BankStatementFormat, []ports.BankStatementEntry, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return "", nil, err
+	}
+	raw = decode(raw)
+	if format == "" {
+		format = detect(raw)
+	}
+
+	var entries []ports.BankStatementEntry
+	switch format {
+	case domain.BankStatementCAMT053:
+		entries, err = ParseCAMT053(bytes.NewReader(raw))
+	case domain.BankStatementMT940:
+		entries, err = ParseMT940(bytes.NewReader(raw))
+	case domain.BankStatementCSV:
+		if profile == "" {
+			profile = "generic"
+		}
+		csvProfile, ok := p.profiles[strings.ToLower(profile)]
+		if !ok {
+			return "", nil, fmt.Errorf("%w: unknown CSV profile %q", domain.ErrUnknownBankStatementFormat, profile)
+		}
+		entries, err = ParseCSV(bytes.NewReader(raw), csvProfile)
+	default:
+		return "", nil, domain.ErrUnknownBankStatementFormat
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	return format, entries, nil
+}
+
+// detect tells the formats apart by their first significant characters:
+// CAMT.053 is XML and MT940 opens with a header block or a :20: tag.
+func detect(raw []byte) domain.BankStatementFormat {
+	s := strings.TrimSpace(string(raw))
+	switch {
+	case strings.HasPrefix(s, "<"):
+		return domain.BankStatementCAMT053
+	case strings.HasPrefix(s, "{1:"), strings.HasPrefix(s, ":20:"), strings.Contains(s, "\n:20:"):
+		return domain.BankStatementMT940
+	}
+	return domain.BankStatementCSV
+}
+
+// invalid wraps a parse error of the statement's content.
+func invalid(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", domain.ErrInvalidBankStatement, fmt.Sprintf(format, args...))
+}
+
+// decode strips a UTF-8 byte order mark and converts Windows-1254 (Turkish)
+// exports, which most bank branches still produce, to UTF-8.
+func decode(raw []byte) []byte {
+	raw = bytes.TrimPrefix(raw, []byte("\xef\xbb\xbf"))
+	if utf8.Valid(raw) {
+		return raw
+	}
+
+	var b bytes.Buffer
+	b.Grow(len(raw) + len(raw)/8)
+	for _, c := range raw {
+		r, ok := windows1254[c]
+		if !ok {
+			r = rune(c)
+		}
+		b.WriteRune(r)
+	}
+	return b.Bytes()
+}
+
+// windows1254 maps the bytes where Windows-1254 differs from ISO-8859-1 and
+// that can appear in a statement.
+var windows1254 = map[byte]rune{
+	0x80: '€', 0x8A: 'Š', 0x8C: 'Œ', 0x9A: 'š', 0x9C: 'œ', 0x9F: 'Ÿ',
+	0xD0: 'Ğ', 0xDD: 'İ', 0xDE: 'Ş',
+	0xF0: 'ğ', 0xFD: 'ı', 0xFE: 'ş',
+}
+
+// compactIBAN removes the spaces IBANs are printed with.
+func compactIBAN(s string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(s), ""))
+}
+
+var _ ports.BankStatementParser = &Parser{}
@@ -0,0 +1,84 @@
+package bankstatement_test
+
+import (
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"carigo/internal/infrastructure/bankstatement"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func money(amount int64, currency string) domain.Money {
+	m, _ := domain.NewMoney(amount, currency)
+	return m
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// expectEntries compares the parsed entries with the expected ones, field by
+// field, so a failure names the entry and the field that differ.
+func expectEntries(t *testing.T, got, want []ports.BankStatementEntry) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("expected %d entries, got %d: %+v", len(want), len(got), got)
+	}
+	for i := range want {
+		g, w := got[i], want[i]
+		if g.Account != w.Account || g.Reference != w.Reference || g.PayerName != w.PayerName ||
+			g.PayerIBAN != w.PayerIBAN || g.PayerTaxID != w.PayerTaxID || g.Description != w.Description {
+			t.Errorf("entry %d: expected %+v, got %+v", i+1, w, g)
+		}
+		if !g.Amount.Equals(w.Amount) || g.Amount.Currency() != w.Amount.Currency() {
+			t.Errorf("entry %d: expected %s %s, got %s %s", i+1, w.Amount.Decimal(), w.Amount.Currency(), g.Amount.Decimal(), g.Amount.Currency())
+		}
+		if !g.BookingDate.Equal(w.BookingDate) || !g.ValueDate.Equal(w.ValueDate) {
+			t.Errorf("entry %d: expected booked %s, value %s; got %s, %s", i+1, w.BookingDate, w.ValueDate, g.BookingDate, g.ValueDate)
+		}
+	}
+}
+
+func TestParser_DetectsFormat(t *testing.T) {
+	p := bankstatement.NewParser(nil)
+	tests := []struct {
+		name string
+		file string
+		want domain.BankStatementFormat
+	}{
+		{"camt.053", "\xef\xbb\xbf" + camtV2, domain.BankStatementCAMT053},
+		{"mt940 with header blocks", mt940Statement, domain.BankStatementMT940},
+		{"mt940 without them", ":20:STMT\n:25:TR1\n:60F:C260101TRY0,00\n:61:260102C10,00NTRFREF\n", domain.BankStatementMT940},
+		{"csv", "Tarih;Tutar\n02.03.2026;10,00\n", domain.BankStatementCSV},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, entries, err := p.Parse(context.Background(), "", "", strings.NewReader(tt.file))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if format != tt.want || len(entries) == 0 {
+				t.Errorf("expected %s with entries, got %s with %d", tt.want, format, len(entries))
+			}
+		})
+	}
+}
+
+func TestParser_UnknownCSVProfile(t *testing.T) {
+	p := bankstatement.NewParser(map[string]bankstatement.CSVProfile{
+		"Akbank": {Columns: bankstatement.CSVColumns{Date: "İşlem Tarihi", Amount: "Tutar"}},
+	})
+	_, _, err := p.Parse(context.Background(), domain.BankStatementCSV, "ziraat", strings.NewReader("Tarih;Tutar\n"))
+	if !errors.Is(err, domain.ErrUnknownBankStatementFormat) {
+		t.Errorf("expected ErrUnknownBankStatementFormat, got %v", err)
+	}
+
+	// Profiles given by name are found regardless of case.
+	_, entries, err := p.Parse(context.Background(), domain.BankStatementCSV, "AKBANK", strings.NewReader("İŞLEM TARİHİ;TUTAR\n02.03.2026;10,00\n"))
+	if err != nil || len(entries) != 1 {
+		t.Errorf("expected the configured profile to read one entry, got %d, %v", len(entries), err)
+	}
+}
@@ -0,0 +1,110 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+)
+
+// A4 portrait, in points.
+const (
+	pageWidth  = 595.28
+	pageHeight = 841.89
+)
+
+// document is a minimal PDF writer: pages of text, lines and grey boxes in
+// the standard Helvetica fonts, which every PDF viewer has built in, so
+// nothing needs to be embedded. Coordinates are in points from the top left
+// corner of the page.
+type document struct {
+	title string
+	pages []*bytes.Buffer
+	page  *bytes.Buffer
+}
+
+func newDocument(title string) *document {
+	return &document{title: title}
+}
+
+func (d *document) addPage() {
+	d.page = &bytes.Buffer{}
+	d.pages = append(d.pages, d.page)
+}
+
+// text writes s with its left edge at x and its baseline at y.
+func (d *document) text(x, y float64, f font, size float64, s string) {
+	fmt.Fprintf(d.page, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", f.resource, size, x, pageHeight-y, escape(encode(s)))
+}
+
+// textRight writes s with its right edge at x.
+func (d *document) textRight(x, y float64, f font, size float64, s string) {
+	d.text(x-f.width(s, size), y, f, size, s)
+}
+
+func (d *document) line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(d.page, "%.2f w %.2f %.2f m %.2f %.2f l S\n", width, x1, pageHeight-y1, x2, pageHeight-y2)
+}
+
+// fillRect paints a box of the given grey level, 0 being black and 1 white.
+func (d *document) fillRect(x, y, w, h, gray float64) {
+	fmt.Fprintf(d.page, "%.2f g %.2f %.2f %.2f %.2f re f 0 g\n", gray, x, pageHeight-y-h, w, h)
+}
+
+// bytes lays out the file: the catalog, the page tree, the two fonts, then
+// each page with its compressed content stream, and the cross-reference
+// table pointing at all of them.
+func (d *document) bytes() ([]byte, error) {
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	const firstPage = 6
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d /MediaBox [0 0 %.2f %.2f] >>", strings.Join(kids, " "), len(d.pages), pageWidth, pageHeight))
+	object(regular.dictionary())
+	object(bold.dictionary())
+	object(fmt.Sprintf("<< /Title (%s) /Producer (CariGo) >>", escape(encode(d.title))))
+
+	for i, page := range d.pages {
+		var content bytes.Buffer
+		zw := zlib.NewWriter(&content)
+		if _, err := zw.Write(page.Bytes()); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>", firstPage+2*i+1))
+		object(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", content.Len(), content.Bytes()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return out.Bytes(), nil
+}
+
+// escape makes encoded text safe inside a PDF string literal.
+func escape(b []byte) string {
+	var s strings.Builder
+	for _, c := range b {
+		if c == '(' || c == ')' || c == '\\' {
+			s.WriteByte('\\')
+		}
+		s.WriteByte(c)
+	}
+	return s.String()
+}
@@ -0,0 +1,110 @@
+package pdf
+
+import "fmt"
+
+// font is one of the standard Helvetica faces. Text is written in
+// Windows-1254, the Turkish code page: WinAnsi with the six Turkish letters
+// Latin-1 lacks put in place of Ð, Ý, Þ, ð, ý and þ, which the encoding's
+// Differences array tells the viewer.
+type font struct {
+	resource string
+	name     string
+	// widths are the advance widths of the printable ASCII characters, in
+	// thousandths of the font size, from the Adobe font metrics.
+	widths [95]int
+	// dotlessI is the width of ı, which has no ASCII base letter.
+	dotlessI int
+}
+
+var regular = font{
+	resource: "F1",
+	name:     "Helvetica",
+	widths: [95]int{
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	},
+	dotlessI: 278,
+}
+
+var bold = font{
+	resource: "F2",
+	name:     "Helvetica-Bold",
+	widths: [95]int{
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	},
+	dotlessI: 278,
+}
+
+func (f font) dictionary() string {
+	return fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding << /Type /Encoding /BaseEncoding /WinAnsiEncoding /Differences [208 /Gbreve 221 /Idotaccent 222 /Scedilla 240 /gbreve 253 /dotlessi 254 /scedilla] >> >>", f.name)
+}
+
+// turkish maps the letters Windows-1254 places where Latin-1 has others.
+var turkish = map[rune]byte{
+	'Ğ': 0xD0, 'İ': 0xDD, 'Ş': 0xDE,
+	'ğ': 0xF0, 'ı': 0xFD, 'ş': 0xFE,
+}
+
+// accented maps accented letters to the letter they are as wide as.
+var accented = map[rune]byte{
+	'Ç': 'C', 'Ğ': 'G', 'İ': 'I', 'Ö': 'O', 'Ş': 'S', 'Ü': 'U', 'Â': 'A', 'Î': 'I', 'Û': 'U',
+	'ç': 'c', 'ğ': 'g', 'ö': 'o', 'ş': 's', 'ü': 'u', 'â': 'a', 'î': 'i', 'û': 'u',
+}
+
+// encode converts s to Windows-1254. Characters it does not have become "?".
+func encode(s string) []byte {
+	b := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch c, ok := turkish[r]; {
+		case ok:
+			b = append(b, c)
+		case r >= ' ' && r <= '~':
+			b = append(b, byte(r))
+		case r >= 0xA0 && r <= 0xFF && r != 0xD0 && r != 0xDD && r != 0xDE && r != 0xF0 && r != 0xFD && r != 0xFE:
+			b = append(b, byte(r))
+		default:
+			b = append(b, '?')
+		}
+	}
+	return b
+}
+
+// width is the length of s set in f at size points.
+func (f font) width(s string, size float64) float64 {
+	total := 0
+	for _, r := range s {
+		switch base, ok := accented[r]; {
+		case r == 'ı':
+			total += f.dotlessI
+		case ok:
+			total += f.widths[base-' ']
+		case r >= ' ' && r <= '~':
+			total += f.widths[r-' ']
+		default:
+			// Other Latin-1 letters are about as wide as an average one.
+			total += f.widths['n'-' ']
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// fit shortens s with an ellipsis until it is no wider than max.
+func (f font) fit(s string, size, max float64) string {
+	if f.width(s, size) <= max {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && f.width(string(runes)+"...", size) > max {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
@@ -0,0 +1,140 @@
+package pdf
+
+import (
+	"carigo/internal/application/dto"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+var lineColumns = []column{
+	{title: "Açıklama", width: 190},
+	{title: "Miktar", width: 50, right: true},
+	{title: "Birim Fiyat", width: 80, right: true},
+	{title: "İskonto", width: 70, right: true},
+	{title: "KDV %", width: 40, right: true},
+	{title: "Tutar", width: 85.28, right: true},
+}
+
+var instalmentColumns = []column{
+	{title: "Taksit", width: 60},
+	{title: "Vade Tarihi", width: 95},
+	{title: "Tutar", width: 120, right: true},
+	{title: "Ödenen", width: 120, right: true},
+	{title: "Kalan", width: 120.28, right: true},
+}
+
+// RenderInvoice lays out the invoice with its lines, totals and instalment
+// plan. A void invoice is marked as such.
+func (r *Renderer) RenderInvoice(doc *dto.InvoiceDocument) ([]byte, error) {
+	inv := doc.Invoice
+	cur := inv.Currency
+	s := r.newSheet(invoiceTitle(inv.Kind), "Fatura "+inv.ID)
+
+	s.doc.text(margin, s.y, bold, 9, "Sayın")
+	s.doc.text(margin, s.y+13, bold, 10, bold.fit(doc.Customer.Name, 10, contentWidth/2))
+	s.field(margin, s.y+26, "Vergi/TC No:", doc.Customer.TaxID)
+	s.field(margin, s.y+38, "E-posta:", doc.Customer.Email)
+
+	x := pageWidth/2 + 40
+	s.field(x, s.y, "Fatura No:", inv.ID)
+	s.field(x, s.y+12, "Fatura Tarihi:", day(inv.IssueDate))
+	s.field(x, s.y+24, "Vade Tarihi:", day(inv.DueDate))
+	s.field(x, s.y+36, "Durum:", invoiceStatus(inv))
+	s.y += 58
+
+	if inv.Status == "VOID" {
+		s.doc.text(margin, s.y+6, bold, 16, "İPTAL EDİLDİ")
+		s.y += 18
+		if inv.VoidReason != "" {
+			s.note(regular, "İptal nedeni: "+inv.VoidReason)
+		}
+		s.y += 6
+	}
+
+	s.table(lineColumns)
+	for _, l := range inv.Lines {
+		description := l.Description
+		if l.Withholding != "" {
+			description += " (Tevkifat " + l.Withholding + ")"
+		}
+		s.row(regular, false, description, l.Quantity, money(l.UnitPrice, cur), numberOrBlank(l.Discount, cur),
+			fmt.Sprintf("%d", l.VATRate), money(l.NetAmount, cur))
+	}
+	if len(inv.Lines) == 0 {
+		s.row(regular, false, kindName(inv.Kind), "1", money(inv.Subtotal, cur), "", "", money(inv.Subtotal, cur))
+	}
+	s.endTable()
+
+	totals := [][2]string{
+		{"Ara Toplam", money(inv.Subtotal, cur)},
+		{"KDV", money(inv.VATAmount, cur)},
+	}
+	if !isZero(inv.Withholding) {
+		totals = append(totals, [2]string{"Tevkif Edilen KDV", "-" + money(inv.Withholding, cur)})
+	}
+	totals = append(totals, [2]string{"Ödenecek Tutar", money(inv.TotalAmount, cur)})
+	if inv.Status != "VOID" {
+		totals = append(totals,
+			[2]string{"Ödenen", money(inv.PaidAmount, cur)},
+			[2]string{"Kalan", money(doc.Remaining, cur)},
+		)
+	}
+	s.ensure(float64(len(totals)) * rowHeight)
+	for _, t := range totals {
+		f := regular
+		if t[0] == "Ödenecek Tutar" || t[0] == "Kalan" {
+			f = bold
+		}
+		s.doc.textRight(pageWidth-margin-100, s.y+10, f, fontSize, t[0]+":")
+		s.doc.textRight(pageWidth-margin-3, s.y+10, f, fontSize, t[1])
+		s.y += rowHeight
+	}
+	s.y += 18
+
+	if len(inv.Instalments) > 0 {
+		s.heading("Ödeme Planı")
+		s.table(instalmentColumns)
+		for _, i := range inv.Instalments {
+			due := day(i.DueDate)
+			if i.Overdue {
+				due += " (gecikmiş)"
+			}
+			s.row(regular, false, fmt.Sprintf("%d/%d", i.Number, len(inv.Instalments)), due,
+				money(i.Amount, cur), money(i.Paid, cur), money(i.Remaining, cur))
+		}
+		s.endTable()
+	}
+	return s.bytes()
+}
+
+func invoiceTitle(kind string) string {
+	return strings.ToUpperSpecial(unicode.TurkishCase, kindName(kind))
+}
+
+func kindName(kind string) string {
+	switch kind {
+	case "EXCHANGE_DIFFERENCE":
+		return "Kur Farkı Faturası"
+	case "LATE_INTEREST":
+		return "Vade Farkı Faturası"
+	}
+	return "Satış Faturası"
+}
+
+// invoiceStatus names the status the way the invoice list does.
+func invoiceStatus(inv dto.InvoiceDTO) string {
+	switch {
+	case inv.EffectiveStatus == "OVERDUE":
+		return fmt.Sprintf("Gecikmiş (%d gün)", inv.DaysOverdue)
+	case inv.Status == "OPEN":
+		return "Açık"
+	case inv.Status == "PAID":
+		return "Ödendi"
+	case inv.Status == "PARTIAL":
+		return "Kısmi"
+	case inv.Status == "VOID":
+		return "İptal"
+	}
+	return inv.Status
+}
@@ -0,0 +1,231 @@
+// Package pdf renders customer statements and invoices as PDF files. It
+// writes the files itself, in pure Go, using the fonts built into every PDF
+// viewer, so neither external programs nor font files are needed.
+package pdf
+
+import (
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Company is the issuer printed at the top of every page.
+type Company struct {
+	Name      string
+	Address   string
+	TaxOffice string
+	TaxID     string
+	Email     string
+}
+
+// Renderer lays out documents on A4 pages headed by the company.
+type Renderer struct {
+	company Company
+	clock   ports.Clock
+}
+
+func NewRenderer(company Company, clk ports.Clock) *Renderer {
+	return &Renderer{company: company, clock: clk}
+}
+
+var _ ports.DocumentRenderer = &Renderer{}
+
+const (
+	margin       = 40.0
+	contentWidth = pageWidth - 2*margin
+	// bottom is as far down as rows go; the page number sits below it.
+	bottom    = pageHeight - 60
+	rowHeight = 15.0
+	fontSize  = 7.5
+)
+
+// column is one column of a table. Amounts are aligned right.
+type column struct {
+	title string
+	width float64
+	right bool
+}
+
+// sheet writes a document top to bottom, starting a new page, with the
+// company header and the header row of the current table, whenever the next
+// block does not fit.
+type sheet struct {
+	doc     *document
+	company Company
+	title   string
+	date    time.Time
+	y       float64
+	columns []column
+}
+
+func (r *Renderer) newSheet(title, name string) *sheet {
+	s := &sheet{doc: newDocument(name), company: r.company, title: title, date: r.clock.Now()}
+	s.newPage()
+	return s
+}
+
+// newPage starts a page with the company on the left and the document title
+// and date on the right.
+func (s *sheet) newPage() {
+	s.doc.addPage()
+	y := margin + 14
+	titleWidth := bold.width(s.title, 14)
+	s.doc.text(margin, y, bold, 14, bold.fit(s.company.Name, 14, contentWidth-titleWidth-20))
+	s.doc.textRight(pageWidth-margin, y, bold, 14, s.title)
+
+	var lines []string
+	if s.company.Address != "" {
+		lines = append(lines, s.company.Address)
+	}
+	var tax []string
+	if s.company.TaxOffice != "" {
+		tax = append(tax, "Vergi Dairesi: "+s.company.TaxOffice)
+	}
+	if s.company.TaxID != "" {
+		tax = append(tax, "Vergi No: "+s.company.TaxID)
+	}
+	if len(tax) > 0 {
+		lines = append(lines, strings.Join(tax, "   "))
+	}
+	if s.company.Email != "" {
+		lines = append(lines, s.company.Email)
+	}
+	for i, l := range lines {
+		s.doc.text(margin, y+14+float64(i)*11, regular, fontSize, regular.fit(l, fontSize, contentWidth/2))
+	}
+	s.doc.textRight(pageWidth-margin, y+14, regular, fontSize, "Düzenleme Tarihi: "+s.date.Format("02.01.2006"))
+
+	y += 14 + float64(max(len(lines), 1))*11
+	s.doc.line(margin, y, pageWidth-margin, y, 1)
+	s.y = y + 20
+}
+
+// ensure starts a new page unless height more points fit on this one.
+func (s *sheet) ensure(height float64) {
+	if s.y+height > bottom {
+		s.newPage()
+		if s.columns != nil {
+			s.headerRow()
+		}
+	}
+}
+
+// heading writes a section title, keeping it on the page of the first rows
+// below it.
+func (s *sheet) heading(text string) {
+	s.ensure(3 * rowHeight)
+	s.doc.text(margin, s.y, bold, 10, text)
+	s.y += 8
+}
+
+// field writes a label and its value on one line at x.
+func (s *sheet) field(x, y float64, label, value string) {
+	s.doc.text(x, y, bold, fontSize, label)
+	s.doc.text(x+bold.width(label, fontSize)+4, y, regular, fontSize, value)
+}
+
+// note writes a line of plain text.
+func (s *sheet) note(f font, text string) {
+	s.ensure(rowHeight)
+	s.doc.text(margin, s.y+10, f, fontSize, f.fit(text, fontSize, contentWidth))
+	s.y += rowHeight
+}
+
+// table starts a table; its header row is repeated on every page it spans.
+func (s *sheet) table(columns []column) {
+	s.columns = nil
+	s.ensure(2 * rowHeight)
+	s.columns = columns
+	s.headerRow()
+}
+
+func (s *sheet) headerRow() {
+	s.doc.fillRect(margin, s.y, contentWidth, rowHeight, 0.88)
+	s.cells(bold, s.columns, titles(s.columns))
+	s.y += rowHeight
+}
+
+func titles(columns []column) []string {
+	t := make([]string, len(columns))
+	for i, c := range columns {
+		t[i] = c.title
+	}
+	return t
+}
+
+// row writes one table row; a shaded row stands out as a total.
+func (s *sheet) row(f font, shaded bool, values ...string) {
+	s.ensure(rowHeight)
+	if shaded {
+		s.doc.fillRect(margin, s.y, contentWidth, rowHeight, 0.95)
+	}
+	s.cells(f, s.columns, values)
+	s.y += rowHeight
+	s.doc.line(margin, s.y, pageWidth-margin, s.y, 0.3)
+}
+
+func (s *sheet) cells(f font, columns []column, values []string) {
+	x := margin
+	for i, c := range columns {
+		if i < len(values) {
+			v := f.fit(values[i], fontSize, c.width-6)
+			if c.right {
+				s.doc.textRight(x+c.width-3, s.y+10, f, fontSize, v)
+			} else {
+				s.doc.text(x+3, s.y+10, f, fontSize, v)
+			}
+		}
+		x += c.width
+	}
+}
+
+// endTable leaves some space below the table.
+func (s *sheet) endTable() {
+	s.columns = nil
+	s.y += 18
+}
+
+// bytes numbers the pages and returns the file.
+func (s *sheet) bytes() ([]byte, error) {
+	for i, p := range s.doc.pages {
+		s.doc.page = p
+		s.doc.textRight(pageWidth-margin, pageHeight-35, regular, 7, fmt.Sprintf("Sayfa %d/%d", i+1, len(s.doc.pages)))
+	}
+	return s.doc.bytes()
+}
+
+// money renders a decimal amount such as "-1234.56" in Turkish style,
+// "-1.234,56 TRY". Anything it cannot read is shown as is.
+func money(amount, currency string) string {
+	sign := ""
+	if strings.HasPrefix(amount, "-") {
+		sign, amount = "-", amount[1:]
+	}
+	m, err := domain.ParseMoney(amount, currency)
+	if err != nil {
+		return sign + amount + " " + currency
+	}
+	return sign + m.Format(domain.LocaleTR)
+}
+
+// number renders a decimal amount like money, without the currency code,
+// for tables of a single currency.
+func number(amount, currency string) string {
+	return strings.TrimSuffix(money(amount, currency), " "+currency)
+}
+
+// isZero reports whether a decimal amount is zero.
+func isZero(amount string) bool {
+	return strings.Trim(amount, "-0.") == ""
+}
+
+// day renders a "2006-01-02" date the Turkish way, as "02.01.2006".
+func day(date string) string {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return date
+	}
+	return t.Format("02.01.2006")
+}
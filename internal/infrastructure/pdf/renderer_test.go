@@ -0,0 +1,172 @@
+package pdf_test
+
+import (
+	"bytes"
+	"carigo/internal/application/dto"
+	"carigo/internal/infrastructure/pdf"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+var (
+	startXref = regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`)
+	xrefRow   = regexp.MustCompile(`^(\d{10}) 00000 n $`)
+	stream    = regexp.MustCompile(`<< /Length (\d+) /Filter /FlateDecode >>\nstream\n`)
+)
+
+// checkFile reads the file the way a viewer does: from startxref to the
+// cross-reference table, from there to every object, and through each
+// content stream by its length. It returns the decompressed pages.
+func checkFile(t *testing.T, file []byte) [][]byte {
+	t.Helper()
+	if !bytes.HasPrefix(file, []byte("%PDF-1.4\n")) {
+		t.Fatalf("expected a PDF 1.4 header, got %q", file[:min(len(file), 16)])
+	}
+
+	m := startXref.FindSubmatch(file)
+	if m == nil {
+		t.Fatal("expected the file to end with startxref and the end-of-file marker")
+	}
+	xref, _ := strconv.Atoi(string(m[1]))
+	table := bytes.Split(file[xref:], []byte("\n"))
+	var count int
+	if _, err := fmt.Sscanf(string(table[0])+" "+string(table[1]), "xref 0 %d", &count); err != nil {
+		t.Fatalf("expected a cross-reference table at %d, got %q", xref, file[xref:min(len(file), xref+20)])
+	}
+	for i := 1; i < count; i++ {
+		row := xrefRow.FindSubmatch(table[2+i])
+		if row == nil {
+			t.Fatalf("object %d: malformed cross-reference row %q", i, table[2+i])
+		}
+		offset, _ := strconv.Atoi(string(row[1]))
+		if want := fmt.Sprintf("%d 0 obj\n", i); !bytes.HasPrefix(file[offset:], []byte(want)) {
+			t.Errorf("object %d: offset %d points at %q", i, offset, file[offset:min(len(file), offset+12)])
+		}
+	}
+
+	var pages [][]byte
+	for _, loc := range stream.FindAllSubmatchIndex(file, -1) {
+		length, _ := strconv.Atoi(string(file[loc[2]:loc[3]]))
+		start := loc[1]
+		if !bytes.HasPrefix(file[start+length:], []byte("\nendstream")) {
+			t.Fatalf("stream at %d: /Length %d does not end at endstream", start, length)
+		}
+		zr, err := zlib.NewReader(bytes.NewReader(file[start : start+length]))
+		if err != nil {
+			t.Fatalf("stream at %d: %v", start, err)
+		}
+		content, err := io.ReadAll(zr)
+		if err != nil {
+			t.Fatalf("stream at %d: %v", start, err)
+		}
+		pages = append(pages, content)
+	}
+	return pages
+}
+
+func TestRenderStatement_MultiPage(t *testing.T) {
+	r := pdf.NewRenderer(pdf.Company{Name: "Örnek Yazılım A.Ş.", Address: "Çankaya, Ankara", TaxOffice: "Çankaya", TaxID: "1234567890"},
+		fixedClock(time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)))
+
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	try := dto.CurrencyStatement{Currency: "TRY", OpeningBalance: "1000.00", TotalDebt: "12000.00", TotalCredit: "0.00", FinalBalance: "13000.00"}
+	for i := 0; i < 120; i++ {
+		try.Transactions = append(try.Transactions, dto.StatementItem{
+			Date:        from.AddDate(0, 0, i),
+			Type:        "FATURA",
+			ReferenceID: fmt.Sprintf("INV-%03d", i+1),
+			Description: "Bakım (İşçilik)",
+			Debt:        "100.00",
+			Credit:      "0.00",
+			Balance:     fmt.Sprintf("%d.00", 1100+100*i),
+			Currency:    "TRY",
+		})
+	}
+	st := &dto.CustomerStatementDTO{
+		Customer:   dto.CustomerDTO{ID: "CUST-001", Name: "Şahin Ağaç Ürünleri", TaxID: "9876543210"},
+		From:       &from,
+		Currencies: []dto.CurrencyStatement{try},
+	}
+
+	file, err := r.RenderStatement(st)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pages := checkFile(t, file)
+	if len(pages) < 3 {
+		t.Fatalf("expected 120 rows to take at least three pages, got %d", len(pages))
+	}
+
+	// The fonts map the Turkish letters Windows-1254 has where
+	// WinAnsiEncoding has others, and the text is written in Windows-1254.
+	if !bytes.Contains(file, []byte("/Differences [208 /Gbreve 221 /Idotaccent 222 /Scedilla 240 /gbreve 253 /dotlessi 254 /scedilla]")) {
+		t.Error("expected the fonts to map the Turkish letters")
+	}
+	for i, page := range pages {
+		for _, want := range []string{
+			"(HESAP EKSTRES\xdd) Tj",              // HESAP EKSTRESİ
+			"(\xd6rnek Yaz\xfdl\xfdm A.\xde.) Tj", // Örnek Yazılım A.Ş.
+			fmt.Sprintf("(Sayfa %d/%d) Tj", i+1, len(pages)),
+		} {
+			if !bytes.Contains(page, []byte(want)) {
+				t.Errorf("page %d: expected %q", i+1, want)
+			}
+		}
+		// The header row is repeated on every page the table spans.
+		if bytes.Contains(page, []byte("(FATURA) Tj")) && !bytes.Contains(page, []byte("(Tarih) Tj")) {
+			t.Errorf("page %d: expected the header row of the table", i+1)
+		}
+	}
+	if !bytes.Contains(pages[0], []byte("(\xdeahin A\xf0a\xe7 \xdcr\xfcnleri) Tj")) {
+		t.Error("expected the customer's name in Windows-1254 on the first page")
+	}
+	if !bytes.Contains(pages[0], []byte("(DEV\xddR) Tj")) {
+		t.Error("expected the opening balance row on the first page")
+	}
+	// Parentheses in text are escaped.
+	if !bytes.Contains(pages[0], []byte("(Bak\xfdm \\(\xdd\xfe\xe7ilik\\)) Tj")) {
+		t.Error("expected the parentheses of the description to be escaped")
+	}
+	if all := bytes.Join(pages, nil); !bytes.Contains(all, []byte("(INV-120) Tj")) || !bytes.Contains(all, []byte(`(13.000,00 TRY \(B\)) Tj`)) {
+		t.Error("expected every row and the balance of the currency")
+	}
+}
+
+func TestRenderInvoice_Void(t *testing.T) {
+	r := pdf.NewRenderer(pdf.Company{Name: "Örnek Yazılım A.Ş."}, fixedClock(time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)))
+	doc := &dto.InvoiceDocument{
+		Invoice: dto.InvoiceDTO{
+			ID: "INV-001", Currency: "TRY", Status: "VOID", VoidReason: "Hatalı tutar",
+			IssueDate: "2026-10-01", DueDate: "2026-10-31",
+			Subtotal: "1000.00", VATAmount: "200.00", Withholding: "0.00", TotalAmount: "1200.00", PaidAmount: "0.00",
+		},
+		Customer:  dto.CustomerDTO{Name: "Şahin Ağaç Ürünleri"},
+		Remaining: "0.00",
+	}
+
+	file, err := r.RenderInvoice(doc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pages := checkFile(t, file)
+	if len(pages) != 1 {
+		t.Fatalf("expected one page, got %d", len(pages))
+	}
+	for _, want := range []string{"(SATI\xde FATURASI) Tj", "(\xddPTAL ED\xddLD\xdd) Tj", "(1.200,00 TRY) Tj"} {
+		if !bytes.Contains(pages[0], []byte(want)) {
+			t.Errorf("expected %q", want)
+		}
+	}
+	if bytes.Contains(pages[0], []byte("(Kalan:) Tj")) {
+		t.Error("expected no remaining amount on a void invoice")
+	}
+}
@@ -0,0 +1,107 @@
+package pdf
+
+import (
+	"carigo/internal/application/dto"
+	"strings"
+)
+
+var statementColumns = []column{
+	{title: "Tarih", width: 44},
+	{title: "Belge No", width: 104},
+	{title: "İşlem", width: 100},
+	{title: "Açıklama", width: 95.28},
+	{title: "Borç", width: 55, right: true},
+	{title: "Alacak", width: 55, right: true},
+	{title: "Bakiye", width: 62, right: true},
+}
+
+var totalColumns = []column{
+	{title: "Para Birimi", width: 75},
+	{title: "Devir", width: 110, right: true},
+	{title: "Toplam Borç", width: 110, right: true},
+	{title: "Toplam Alacak", width: 110, right: true},
+	{title: "Bakiye", width: 110.28, right: true},
+}
+
+// RenderStatement lays out the statement as one table per currency, each
+// with its running balance, followed by the totals of every currency. The
+// amounts in a currency's table are in that currency.
+func (r *Renderer) RenderStatement(st *dto.CustomerStatementDTO) ([]byte, error) {
+	s := r.newSheet("HESAP EKSTRESİ", "Hesap Ekstresi - "+st.Customer.Name)
+
+	period := "Tüm hareketler"
+	switch {
+	case st.From != nil && st.To != nil:
+		period = st.From.Format("02.01.2006") + " - " + st.To.Format("02.01.2006")
+	case st.From != nil:
+		period = st.From.Format("02.01.2006") + " tarihinden itibaren"
+	case st.To != nil:
+		period = st.To.Format("02.01.2006") + " tarihine kadar"
+	}
+	s.field(margin, s.y, "Müşteri:", st.Customer.Name)
+	s.field(pageWidth/2, s.y, "Dönem:", period)
+	s.field(margin, s.y+12, "Vergi/TC No:", st.Customer.TaxID)
+	s.field(pageWidth/2, s.y+12, "Müşteri No:", st.Customer.ID)
+	s.field(margin, s.y+24, "E-posta:", st.Customer.Email)
+	s.y += 48
+
+	if len(st.Currencies) == 0 {
+		s.note(regular, "Bu dönemde hesap hareketi yoktur.")
+	}
+	for _, c := range st.Currencies {
+		s.heading("Hesap Hareketleri (" + c.Currency + ")")
+		s.table(statementColumns)
+		if st.From != nil {
+			s.row(regular, false, st.From.Format("02.01.2006"), "", "DEVİR", "Önceki dönem", "", "", number(c.OpeningBalance, c.Currency))
+		}
+		for _, t := range c.Transactions {
+			kind := t.Type
+			if t.Overdue {
+				kind += " (gecikmiş)"
+			}
+			s.row(regular, false, t.Date.Format("02.01.2006"), t.ReferenceID, kind, t.Description,
+				numberOrBlank(t.Debt, c.Currency), numberOrBlank(t.Credit, c.Currency), number(t.Balance, c.Currency))
+		}
+		s.row(bold, true, "", "", "TOPLAM", "", number(c.TotalDebt, c.Currency), number(c.TotalCredit, c.Currency), number(c.FinalBalance, c.Currency))
+		s.endTable()
+	}
+
+	if len(st.Currencies) > 0 {
+		s.heading("Para Birimi Bazında Toplamlar")
+		s.table(totalColumns)
+		for _, c := range st.Currencies {
+			s.row(regular, false, c.Currency, money(c.OpeningBalance, c.Currency), money(c.TotalDebt, c.Currency),
+				money(c.TotalCredit, c.Currency), money(c.FinalBalance, c.Currency)+side(c.FinalBalance))
+		}
+		s.endTable()
+		s.note(regular, "Pozitif bakiye müşterinin borcunu (B), negatif bakiye alacağını (A) gösterir.")
+	}
+
+	if len(st.OnAccountCredits) > 0 {
+		credits := make([]string, len(st.OnAccountCredits))
+		for i, c := range st.OnAccountCredits {
+			credits[i] = money(c.Amount, c.Currency)
+		}
+		s.note(regular, "Faturalara dağıtılmamış avans/alacak: "+strings.Join(credits, ", "))
+	}
+	return s.bytes()
+}
+
+func numberOrBlank(amount, currency string) string {
+	if isZero(amount) {
+		return ""
+	}
+	return number(amount, currency)
+}
+
+// side marks a balance as the customer's debt (B) or credit (A), to be
+// written after the amount.
+func side(balance string) string {
+	switch {
+	case isZero(balance):
+		return ""
+	case strings.HasPrefix(balance, "-"):
+		return " (A)"
+	}
+	return " (B)"
+}
@@ -0,0 +1,45 @@
+package memory
+
+import (
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"context"
+	"sync"
+)
+
+// AllocationPlanStore keeps allocation plans in process memory.
+// Plans are lost on restart, which is fine: they are only proposals.
+type AllocationPlanStore struct {
+	mu    sync.Mutex
+	plans map[domain.AllocationPlanID]*domain.AllocationPlan
+}
+
+func NewAllocationPlanStore() *AllocationPlanStore {
+	return &AllocationPlanStore{plans: make(map[domain.AllocationPlanID]*domain.AllocationPlan)}
+}
+
+func (s *AllocationPlanStore) Save(ctx context.Context, plan *domain.AllocationPlan) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.plans[plan.ID] = plan
+	return nil
+}
+
+func (s *AllocationPlanStore) FindByID(ctx context.Context, id domain.AllocationPlanID) (*domain.AllocationPlan, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	plan, ok := s.plans[id]
+	if !ok {
+		return nil, domain.ErrAllocationPlanNotFound
+	}
+	return plan, nil
+}
+
+func (s *AllocationPlanStore) Delete(ctx context.Context, id domain.AllocationPlanID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.plans, id)
+	return nil
+}
+
+var _ ports.AllocationPlanStore = &AllocationPlanStore{}
@@ -4,25 +4,54 @@ import (
 	"carigo/internal/application/ports"
 	"carigo/internal/domain"
 	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
 )
 
 type AllocationModel struct {
-	ID        string `gorm:"primaryKey"`
-	PaymentID string `gorm:"index"`
-	InvoiceID string `gorm:"index"`
-	Amount    int64
-	Currency  string
-	CreatedAt int64
+	ID               string `gorm:"primaryKey"`
+	PaymentID        string `gorm:"index"`
+	CreditNoteID     string `gorm:"index"`
+	InvoiceID        string `gorm:"index"`
+	Amount           int64
+	Currency         string
+	InvoiceAmount    int64 `gorm:"default:0"`
+	InvoiceCurrency  string
+	ExchangeRate     int64 `gorm:"default:0"`
+	ExchangeRateFrom string
+	ExchangeRateDate int64 `gorm:"default:0"`
+	Type             string
+	ReversalOf       string
+	ReversedBy       string
+	// FXInvoiceID is the kur farkı invoice that billed this allocation's gain.
+	FXInvoiceID string
+	Reason      string
+	CreatedAt   int64
 }
 
 func (r *GormRepository) SaveAllocation(ctx context.Context, a *domain.Allocation) error {
 	m := AllocationModel{
-		ID:        string(a.ID),
-		PaymentID: string(a.PaymentID),
-		InvoiceID: string(a.InvoiceID),
-		Amount:    a.Amount.Amount(),
-		Currency:  a.Amount.Currency(),
-		CreatedAt: a.CreatedAt.Unix(),
+		ID:              string(a.ID),
+		PaymentID:       string(a.PaymentID),
+		CreditNoteID:    string(a.CreditNoteID),
+		InvoiceID:       string(a.InvoiceID),
+		Amount:          a.Amount.Amount(),
+		Currency:        a.Amount.Currency(),
+		InvoiceAmount:   a.InvoiceAmount.Amount(),
+		InvoiceCurrency: a.InvoiceAmount.Currency(),
+		Type:            string(a.Type),
+		ReversalOf:      string(a.ReversalOf),
+		ReversedBy:      string(a.ReversedBy),
+		FXInvoiceID:     string(a.ExchangeDifferenceInvoiceID),
+		Reason:          a.Reason,
+		CreatedAt:       a.CreatedAt.Unix(),
+	}
+	if a.ExchangeRate != nil {
+		m.ExchangeRate = a.ExchangeRate.Rate
+		m.ExchangeRateFrom = a.ExchangeRate.From
+		m.ExchangeRateDate = a.ExchangeRate.Date.Unix()
 	}
 	return r.getDB(ctx).Save(&m).Error
 }
@@ -33,4 +62,108 @@ func (a *AllocationAdapter) Save(ctx context.Context, al *domain.Allocation) err
 	return a.repo.SaveAllocation(ctx, al)
 }
 
+func (a *AllocationAdapter) FindByID(ctx context.Context, id domain.AllocationID) (*domain.Allocation, error) {
+	var m AllocationModel
+	if err := a.repo.getDB(ctx).First(&m, "id = ?", string(id)).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrAllocationNotFound
+		}
+		return nil, err
+	}
+	return a.mapToDomain(m)
+}
+
+func (a *AllocationAdapter) FindByPayment(ctx context.Context, pid domain.PaymentID) ([]*domain.Allocation, error) {
+	return a.findWhere(ctx, "payment_id = ?", string(pid))
+}
+
+func (a *AllocationAdapter) FindByCreditNote(ctx context.Context, cnid domain.CreditNoteID) ([]*domain.Allocation, error) {
+	return a.findWhere(ctx, "credit_note_id = ?", string(cnid))
+}
+
+func (a *AllocationAdapter) FindByInvoice(ctx context.Context, iid domain.InvoiceID) ([]*domain.Allocation, error) {
+	return a.findWhere(ctx, "invoice_id = ?", string(iid))
+}
+
+func (a *AllocationAdapter) FindByExchangeDifferenceInvoice(ctx context.Context, iid domain.InvoiceID) ([]*domain.Allocation, error) {
+	return a.findWhere(ctx, "fx_invoice_id = ?", string(iid))
+}
+
+// FindActiveForeign treats rows without an invoice currency, written before
+// cross-currency allocations existed, as settled in the payment currency.
+func (a *AllocationAdapter) FindActiveForeign(ctx context.Context, localCurrency string) ([]*domain.Allocation, error) {
+	return a.findWhere(ctx,
+		"type IN ? AND reversed_by = '' AND (CASE WHEN invoice_currency = '' THEN currency ELSE invoice_currency END) <> ?",
+		[]string{string(domain.AllocationTypeApplication), ""}, localCurrency)
+}
+
+func (a *AllocationAdapter) findWhere(ctx context.Context, query string, args ...interface{}) ([]*domain.Allocation, error) {
+	var models []AllocationModel
+	err := a.repo.getDB(ctx).
+		Where(query, args...).
+		Order("created_at asc, id asc").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var allocations []*domain.Allocation
+	for _, m := range models {
+		al, err := a.mapToDomain(m)
+		if err != nil {
+			return nil, err
+		}
+		allocations = append(allocations, al)
+	}
+	return allocations, nil
+}
+
+func (a *AllocationAdapter) mapToDomain(m AllocationModel) (*domain.Allocation, error) {
+	amount, err := domain.NewMoney(m.Amount, m.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	allocType := domain.AllocationType(m.Type)
+	if allocType == "" {
+		allocType = domain.AllocationTypeApplication
+	}
+
+	invoiceAmount := amount
+	if m.InvoiceCurrency != "" {
+		invoiceAmount, err = domain.NewMoney(m.InvoiceAmount, m.InvoiceCurrency)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var rate *domain.ExchangeRate
+	if m.ExchangeRate != 0 {
+		to := m.InvoiceCurrency
+		if m.ExchangeRateFrom == m.InvoiceCurrency {
+			to = m.Currency
+		}
+		rate, err = domain.NewExchangeRate(m.ExchangeRateFrom, to, m.ExchangeRate, time.Unix(m.ExchangeRateDate, 0).UTC(), "")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &domain.Allocation{
+		ID:                          domain.AllocationID(m.ID),
+		PaymentID:                   domain.PaymentID(m.PaymentID),
+		CreditNoteID:                domain.CreditNoteID(m.CreditNoteID),
+		InvoiceID:                   domain.InvoiceID(m.InvoiceID),
+		Amount:                      amount,
+		InvoiceAmount:               invoiceAmount,
+		ExchangeRate:                rate,
+		Type:                        allocType,
+		ReversalOf:                  domain.AllocationID(m.ReversalOf),
+		ReversedBy:                  domain.AllocationID(m.ReversedBy),
+		ExchangeDifferenceInvoiceID: domain.InvoiceID(m.FXInvoiceID),
+		Reason:                      m.Reason,
+		CreatedAt:                   parseTime(m.CreatedAt),
+	}, nil
+}
+
 var _ ports.AllocationRepository = &AllocationAdapter{}
@@ -0,0 +1,116 @@
+package sqlite
+
+import (
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// BankLineModel is unique on the receiving account and the bank's reference,
+// which also makes the ID, so a line is never staged twice.
+type BankLineModel struct {
+	ID          string `gorm:"primaryKey"`
+	Account     string `gorm:"uniqueIndex:idx_bank_line_reference"`
+	Reference   string `gorm:"uniqueIndex:idx_bank_line_reference"`
+	BookingDate int64  `gorm:"index"`
+	ValueDate   int64
+	Amount      int64
+	Currency    string
+	PayerName   string
+	PayerIBAN   string `gorm:"index"`
+	PayerTaxID  string
+	Description string
+	Format      string
+	State       string `gorm:"index"`
+	CustomerID  string `gorm:"index"`
+	PaymentID   string
+	ImportedAt  int64
+	UpdatedAt   int64
+}
+
+type BankLineAdapter struct{ repo *GormRepository }
+
+func (a *BankLineAdapter) Save(ctx context.Context, l *domain.BankLine) error {
+	m := BankLineModel{
+		ID:          string(l.ID),
+		Account:     l.Account,
+		Reference:   l.Reference,
+		BookingDate: l.BookingDate.Unix(),
+		ValueDate:   l.ValueDate.Unix(),
+		Amount:      l.Amount.Amount(),
+		Currency:    l.Amount.Currency(),
+		PayerName:   l.PayerName,
+		PayerIBAN:   l.PayerIBAN,
+		PayerTaxID:  l.PayerTaxID,
+		Description: l.Description,
+		Format:      string(l.Format),
+		State:       string(l.State),
+		CustomerID:  string(l.CustomerID),
+		PaymentID:   string(l.PaymentID),
+		ImportedAt:  l.ImportedAt.Unix(),
+		UpdatedAt:   l.UpdatedAt.Unix(),
+	}
+	return a.repo.getDB(ctx).Save(&m).Error
+}
+
+func (a *BankLineAdapter) FindByID(ctx context.Context, id domain.BankLineID) (*domain.BankLine, error) {
+	var m BankLineModel
+	if err := a.repo.getDB(ctx).First(&m, "id = ?", string(id)).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrBankLineNotFound
+		}
+		return nil, err
+	}
+	return a.mapToDomain(m)
+}
+
+func (a *BankLineAdapter) FindByState(ctx context.Context, state domain.BankLineState) ([]*domain.BankLine, error) {
+	db := a.repo.getDB(ctx)
+	if state != "" {
+		db = db.Where("state = ?", string(state))
+	}
+	var models []BankLineModel
+	if err := db.Order("booking_date desc, id asc").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	lines := make([]*domain.BankLine, 0, len(models))
+	for _, m := range models {
+		l, err := a.mapToDomain(m)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, l)
+	}
+	return lines, nil
+}
+
+func (a *BankLineAdapter) mapToDomain(m BankLineModel) (*domain.BankLine, error) {
+	amount, err := domain.NewMoney(m.Amount, m.Currency)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.BankLine{
+		ID:          domain.BankLineID(m.ID),
+		Account:     m.Account,
+		Reference:   m.Reference,
+		BookingDate: parseTime(m.BookingDate),
+		ValueDate:   parseTime(m.ValueDate),
+		Amount:      amount,
+		PayerName:   m.PayerName,
+		PayerIBAN:   m.PayerIBAN,
+		PayerTaxID:  m.PayerTaxID,
+		Description: m.Description,
+		Format:      domain.BankStatementFormat(m.Format),
+		State:       domain.BankLineState(m.State),
+		CustomerID:  domain.CustomerID(m.CustomerID),
+		PaymentID:   domain.PaymentID(m.PaymentID),
+		ImportedAt:  parseTime(m.ImportedAt),
+		UpdatedAt:   parseTime(m.UpdatedAt),
+	}, nil
+}
+
+var _ ports.BankLineRepository = &BankLineAdapter{}
@@ -0,0 +1,43 @@
+package sqlite
+
+import (
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"context"
+)
+
+type BankMatchRuleModel struct {
+	IBAN       string `gorm:"primaryKey"`
+	CustomerID string `gorm:"index"`
+	CreatedAt  int64
+}
+
+type BankMatchRuleAdapter struct{ repo *GormRepository }
+
+func (a *BankMatchRuleAdapter) Save(ctx context.Context, r *domain.BankMatchRule) error {
+	m := BankMatchRuleModel{
+		IBAN:       r.IBAN,
+		CustomerID: string(r.CustomerID),
+		CreatedAt:  r.CreatedAt.Unix(),
+	}
+	return a.repo.getDB(ctx).Save(&m).Error
+}
+
+func (a *BankMatchRuleAdapter) FindAll(ctx context.Context) ([]*domain.BankMatchRule, error) {
+	var models []BankMatchRuleModel
+	if err := a.repo.getDB(ctx).Order("iban asc").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	rules := make([]*domain.BankMatchRule, 0, len(models))
+	for _, m := range models {
+		rules = append(rules, &domain.BankMatchRule{
+			IBAN:       m.IBAN,
+			CustomerID: domain.CustomerID(m.CustomerID),
+			CreatedAt:  parseTime(m.CreatedAt),
+		})
+	}
+	return rules, nil
+}
+
+var _ ports.BankMatchRuleRepository = &BankMatchRuleAdapter{}
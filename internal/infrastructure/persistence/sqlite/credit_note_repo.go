@@ -0,0 +1,107 @@
+package sqlite
+
+import (
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type CreditNoteModel struct {
+	ID              string `gorm:"primaryKey"`
+	CustomerID      string `gorm:"index"`
+	InvoiceID       string `gorm:"index"`
+	Amount          int64
+	Currency        string
+	AvailableAmount int64
+	Date            int64
+	Reason          string
+	CreatedAt       int64
+}
+
+func (r *GormRepository) SaveCreditNote(ctx context.Context, c *domain.CreditNote) error {
+	m := CreditNoteModel{
+		ID:              string(c.ID),
+		CustomerID:      string(c.CustomerID),
+		InvoiceID:       string(c.InvoiceID),
+		Amount:          c.Amount.Amount(),
+		Currency:        c.Amount.Currency(),
+		AvailableAmount: c.AvailableAmount.Amount(),
+		Date:            c.Date.Unix(),
+		Reason:          c.Reason,
+		CreatedAt:       c.CreatedAt.Unix(),
+	}
+	return r.getDB(ctx).Save(&m).Error
+}
+
+type CreditNoteAdapter struct{ repo *GormRepository }
+
+func (a *CreditNoteAdapter) Save(ctx context.Context, c *domain.CreditNote) error {
+	return a.repo.SaveCreditNote(ctx, c)
+}
+
+func (a *CreditNoteAdapter) FindByID(ctx context.Context, id domain.CreditNoteID) (*domain.CreditNote, error) {
+	var m CreditNoteModel
+	if err := a.repo.getDB(ctx).First(&m, "id = ?", string(id)).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrCreditNoteNotFound
+		}
+		return nil, err
+	}
+	return a.mapToDomain(m)
+}
+
+func (a *CreditNoteAdapter) FindByCustomer(ctx context.Context, cid domain.CustomerID) ([]*domain.CreditNote, error) {
+	return a.findWhere(ctx, "customer_id = ?", string(cid))
+}
+
+func (a *CreditNoteAdapter) FindUnallocatedByCustomer(ctx context.Context, cid domain.CustomerID) ([]*domain.CreditNote, error) {
+	return a.findWhere(ctx, "customer_id = ? AND available_amount > 0", string(cid))
+}
+
+func (a *CreditNoteAdapter) findWhere(ctx context.Context, query string, args ...interface{}) ([]*domain.CreditNote, error) {
+	var models []CreditNoteModel
+	err := a.repo.getDB(ctx).
+		Where(query, args...).
+		Order("date asc").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var notes []*domain.CreditNote
+	for _, m := range models {
+		note, err := a.mapToDomain(m)
+		if err != nil {
+			return nil, err
+		}
+		notes = append(notes, note)
+	}
+	return notes, nil
+}
+
+func (a *CreditNoteAdapter) mapToDomain(m CreditNoteModel) (*domain.CreditNote, error) {
+	amount, err := domain.NewMoney(m.Amount, m.Currency)
+	if err != nil {
+		return nil, err
+	}
+	available, err := domain.NewMoney(m.AvailableAmount, m.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.CreditNote{
+		ID:              domain.CreditNoteID(m.ID),
+		CustomerID:      domain.CustomerID(m.CustomerID),
+		InvoiceID:       domain.InvoiceID(m.InvoiceID),
+		Amount:          amount,
+		AvailableAmount: available,
+		Date:            parseTime(m.Date),
+		Reason:          m.Reason,
+		CreatedAt:       parseTime(m.CreatedAt),
+	}, nil
+}
+
+var _ ports.CreditNoteRepository = &CreditNoteAdapter{}
@@ -4,35 +4,116 @@ import (
 	"carigo/internal/application/ports"
 	"carigo/internal/domain"
 	"context"
+	"errors"
+
+	"gorm.io/gorm"
 )
 
 type CustomerModel struct {
-	ID        string `gorm:"primaryKey"`
-	Name      string
-	Email     string
-	TaxID     string
+	ID                 string `gorm:"primaryKey"`
+	Name               string
+	Email              string
+	TaxID              string
+	AllocationStrategy string
+	LateInterestRate   int64
+	LateInterestPeriod string
+	// Default payment terms.
+	PaymentTermsColumns
 	CreatedAt int64
 	UpdatedAt int64
 }
 
+// CustomerCreditLimitModel is a customer's credit limit in one currency.
+type CustomerCreditLimitModel struct {
+	CustomerID string `gorm:"primaryKey"`
+	Currency   string `gorm:"primaryKey"`
+	Amount     int64
+}
+
 func (r *GormRepository) SaveCustomer(ctx context.Context, c *domain.Customer) error {
 	m := CustomerModel{
-		ID:        string(c.ID),
-		Name:      c.Name,
-		Email:     c.Email,
-		TaxID:     c.TaxID,
-		CreatedAt: c.CreatedAt.Unix(),
-		UpdatedAt: c.UpdatedAt.Unix(),
+		ID:                  string(c.ID),
+		Name:                c.Name,
+		Email:               c.Email,
+		TaxID:               c.TaxID,
+		AllocationStrategy:  string(c.AllocationStrategy),
+		LateInterestRate:    c.LateInterest.Percent,
+		LateInterestPeriod:  string(c.LateInterest.Period),
+		PaymentTermsColumns: newPaymentTermsColumns(c.PaymentTerms),
+		CreatedAt:           c.CreatedAt.Unix(),
+		UpdatedAt:           c.UpdatedAt.Unix(),
+	}
+	if err := r.getDB(ctx).Save(&m).Error; err != nil {
+		return err
+	}
+
+	if err := r.getDB(ctx).Where("customer_id = ?", m.ID).Delete(&CustomerCreditLimitModel{}).Error; err != nil {
+		return err
+	}
+	for _, limit := range c.CreditLimits {
+		lm := CustomerCreditLimitModel{CustomerID: m.ID, Currency: limit.Currency(), Amount: limit.Amount()}
+		if err := r.getDB(ctx).Create(&lm).Error; err != nil {
+			return err
+		}
 	}
-	return r.getDB(ctx).Save(&m).Error
+	return nil
 }
 
 func (r *GormRepository) FindCustomerByID(ctx context.Context, id domain.CustomerID) (*domain.Customer, error) {
 	var m CustomerModel
 	if err := r.getDB(ctx).First(&m, "id = ?", string(id)).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrCustomerNotFound
+		}
+		return nil, err
+	}
+	c, err := mapCustomerToDomain(m)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.attachCreditLimits(ctx, map[string]*domain.Customer{m.ID: c}); err != nil {
 		return nil, err
 	}
-	return domain.NewCustomer(domain.CustomerID(m.ID), m.Name, m.Email, m.TaxID)
+	return c, nil
+}
+
+func (r *GormRepository) attachCreditLimits(ctx context.Context, byID map[string]*domain.Customer) error {
+	ids := make([]string, 0, len(byID))
+	for id := range byID {
+		ids = append(ids, id)
+	}
+	var models []CustomerCreditLimitModel
+	if err := r.getDB(ctx).Where("customer_id IN ?", ids).Find(&models).Error; err != nil {
+		return err
+	}
+
+	for _, m := range models {
+		limit, err := domain.NewMoney(m.Amount, m.Currency)
+		if err != nil {
+			return err
+		}
+		c := byID[m.CustomerID]
+		if c.CreditLimits == nil {
+			c.CreditLimits = map[string]domain.Money{}
+		}
+		c.CreditLimits[m.Currency] = limit
+	}
+	return nil
+}
+
+func mapCustomerToDomain(m CustomerModel) (*domain.Customer, error) {
+	c, err := domain.NewCustomer(domain.CustomerID(m.ID), m.Name, m.Email, m.TaxID)
+	if err != nil {
+		return nil, err
+	}
+	if m.AllocationStrategy != "" {
+		c.AllocationStrategy = domain.AllocationStrategyName(m.AllocationStrategy)
+	}
+	c.LateInterest = domain.LateInterestRate{Percent: m.LateInterestRate, Period: domain.InterestPeriod(m.LateInterestPeriod)}
+	c.PaymentTerms = m.PaymentTermsColumns.toDomain()
+	c.CreatedAt = parseTime(m.CreatedAt)
+	c.UpdatedAt = parseTime(m.UpdatedAt)
+	return c, nil
 }
 
 type CustomerAdapter struct{ repo *GormRepository }
@@ -50,14 +131,19 @@ func (a *CustomerAdapter) FindAll(ctx context.Context) ([]*domain.Customer, erro
 		return nil, err
 	}
 	var customers []*domain.Customer
+	byID := make(map[string]*domain.Customer, len(models))
 	for _, m := range models {
-		c, err := domain.NewCustomer(domain.CustomerID(m.ID), m.Name, m.Email, m.TaxID)
+		c, err := mapCustomerToDomain(m)
 		if err != nil {
 			return nil, err
 		}
-		c.CreatedAt = parseTime(m.CreatedAt)
-		
 		customers = append(customers, c)
+		byID[m.ID] = c
+	}
+	if len(byID) > 0 {
+		if err := a.repo.attachCreditLimits(ctx, byID); err != nil {
+			return nil, err
+		}
 	}
 	return customers, nil
 }
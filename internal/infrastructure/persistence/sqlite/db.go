@@ -21,12 +21,22 @@ func NewGormRepository(dsn string) (*GormRepository, error) {
 	if err != nil {
 		return nil, err
 	}
-	
+
 	err = db.AutoMigrate(
 		&CustomerModel{},
+		&CustomerCreditLimitModel{},
 		&InvoiceModel{},
+		&InvoiceLineModel{},
+		&InvoiceInstalmentModel{},
 		&PaymentModel{},
 		&AllocationModel{},
+		&CreditNoteModel{},
+		&ExchangeRateModel{},
+		&OverdueEventModel{},
+		&BankLineModel{},
+		&BankMatchRuleModel{},
+		&ReconciliationModel{},
+		&ReconciliationBalanceModel{},
 	)
 	if err != nil {
 		return nil, err
@@ -35,8 +45,10 @@ func NewGormRepository(dsn string) (*GormRepository, error) {
 	return &GormRepository{db: db}, nil
 }
 
+// Do runs fn in a transaction. Called inside another transaction, it runs in
+// a savepoint of it, so use cases can be composed.
 func (r *GormRepository) Do(ctx context.Context, fn func(ctx context.Context) error) error {
-	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	return r.getDB(ctx).Transaction(func(tx *gorm.DB) error {
 		txCtx := context.WithValue(ctx, txKey{}, tx)
 		return fn(txCtx)
 	})
@@ -57,10 +69,40 @@ func parseTime(unix int64) time.Time {
 }
 
 var _ ports.TransactionManager = &GormRepository{}
-func NewRepositories(dsn string) (*GormRepository, *CustomerAdapter, *InvoiceAdapter, *PaymentAdapter, *AllocationAdapter, error) {
+
+// Repositories are the adapters of one database. Base runs transactions.
+type Repositories struct {
+	Base            *GormRepository
+	Customers       *CustomerAdapter
+	Invoices        *InvoiceAdapter
+	Payments        *PaymentAdapter
+	Allocations     *AllocationAdapter
+	CreditNotes     *CreditNoteAdapter
+	ExchangeRates   *ExchangeRateAdapter
+	OverdueEvents   *OverdueEventAdapter
+	BankLines       *BankLineAdapter
+	BankMatchRules  *BankMatchRuleAdapter
+	Reconciliations *ReconciliationAdapter
+	Statements      *StatementAdapter
+}
+
+func NewRepositories(dsn string) (*Repositories, error) {
 	base, err := NewGormRepository(dsn)
 	if err != nil {
-		return nil, nil, nil, nil, nil, err
+		return nil, err
 	}
-	return base, &CustomerAdapter{base}, &InvoiceAdapter{base}, &PaymentAdapter{base}, &AllocationAdapter{base}, nil
+	return &Repositories{
+		Base:            base,
+		Customers:       &CustomerAdapter{base},
+		Invoices:        &InvoiceAdapter{base},
+		Payments:        &PaymentAdapter{base},
+		Allocations:     &AllocationAdapter{base},
+		CreditNotes:     &CreditNoteAdapter{base},
+		ExchangeRates:   &ExchangeRateAdapter{base},
+		OverdueEvents:   &OverdueEventAdapter{base},
+		BankLines:       &BankLineAdapter{base},
+		BankMatchRules:  &BankMatchRuleAdapter{base},
+		Reconciliations: &ReconciliationAdapter{base},
+		Statements:      &StatementAdapter{base},
+	}, nil
 }
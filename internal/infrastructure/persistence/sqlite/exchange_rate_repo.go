@@ -0,0 +1,78 @@
+package sqlite
+
+import (
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ExchangeRateModel struct {
+	ID           string `gorm:"primaryKey"`
+	FromCurrency string `gorm:"index:idx_exchange_rate_pair"`
+	ToCurrency   string `gorm:"index:idx_exchange_rate_pair"`
+	Rate         int64
+	Date         int64 `gorm:"index"`
+	Source       string
+	CreatedAt    int64
+}
+
+type ExchangeRateAdapter struct{ repo *GormRepository }
+
+func (a *ExchangeRateAdapter) Save(ctx context.Context, r *domain.ExchangeRate) error {
+	m := ExchangeRateModel{
+		ID:           fmt.Sprintf("%s-%s-%s", r.From, r.To, r.Date.Format("20060102")),
+		FromCurrency: r.From,
+		ToCurrency:   r.To,
+		Rate:         r.Rate,
+		Date:         r.Date.Unix(),
+		Source:       r.Source,
+		CreatedAt:    time.Now().Unix(),
+	}
+	return a.repo.getDB(ctx).Save(&m).Error
+}
+
+func (a *ExchangeRateAdapter) Rate(ctx context.Context, from, to string, on time.Time) (*domain.ExchangeRate, error) {
+	var m ExchangeRateModel
+	day := domain.RateDay(on)
+	err := a.repo.getDB(ctx).
+		Where("((from_currency = ? AND to_currency = ?) OR (from_currency = ? AND to_currency = ?)) AND date <= ? AND date >= ?",
+			from, to, to, from, day.Unix(), day.AddDate(0, 0, -domain.RateMaxAgeDays).Unix()).
+		Order("date desc").
+		First(&m).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrExchangeRateNotFound
+		}
+		return nil, err
+	}
+	return a.mapToDomain(m)
+}
+
+func (a *ExchangeRateAdapter) FindAll(ctx context.Context) ([]*domain.ExchangeRate, error) {
+	var models []ExchangeRateModel
+	err := a.repo.getDB(ctx).Order("date desc, from_currency, to_currency").Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var rates []*domain.ExchangeRate
+	for _, m := range models {
+		r, err := a.mapToDomain(m)
+		if err != nil {
+			return nil, err
+		}
+		rates = append(rates, r)
+	}
+	return rates, nil
+}
+
+func (a *ExchangeRateAdapter) mapToDomain(m ExchangeRateModel) (*domain.ExchangeRate, error) {
+	return domain.NewExchangeRate(m.FromCurrency, m.ToCurrency, m.Rate, time.Unix(m.Date, 0).UTC(), m.Source)
+}
+
+var _ ports.ExchangeRateRepository = &ExchangeRateAdapter{}
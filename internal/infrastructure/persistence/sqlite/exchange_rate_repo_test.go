@@ -0,0 +1,51 @@
+package sqlite_test
+
+import (
+	"carigo/internal/domain"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRate_LooksBackOverGapsOnly(t *testing.T) {
+	ctx := context.Background()
+	repos := newTestRepositories(t)
+
+	// The last bulletin before a Kurban Bayramı closing that ran from the
+	// 25th of May to the 2nd of June.
+	friday := time.Date(2026, 5, 22, 0, 0, 0, 0, time.UTC)
+	rate, err := domain.NewExchangeRate("USD", "TRY", 41*domain.RateScale, friday, domain.ExchangeRateSourceTCMB)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := repos.ExchangeRates.Save(ctx, rate); err != nil {
+		t.Fatalf("failed to save the rate: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		from, to string
+		on       time.Time
+		want     error
+	}{
+		{"on the day", "USD", "TRY", friday, nil},
+		{"the other way round", "TRY", "USD", friday.Add(15 * time.Hour), nil},
+		{"over the weekend", "USD", "TRY", time.Date(2026, 5, 24, 12, 0, 0, 0, time.UTC), nil},
+		{"at the end of the holiday", "USD", "TRY", friday.AddDate(0, 0, domain.RateMaxAgeDays), nil},
+		{"after the gap a holiday leaves", "USD", "TRY", friday.AddDate(0, 0, domain.RateMaxAgeDays+1), domain.ErrExchangeRateNotFound},
+		{"months later", "USD", "TRY", friday.AddDate(0, 4, 0), domain.ErrExchangeRateNotFound},
+		{"before the first rate", "USD", "TRY", friday.AddDate(0, 0, -1), domain.ErrExchangeRateNotFound},
+		{"another pair", "EUR", "TRY", friday, domain.ErrExchangeRateNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repos.ExchangeRates.Rate(ctx, tt.from, tt.to, tt.on)
+			if err != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+			if err == nil && (!got.Date.Equal(friday) || got.Rate != 41*domain.RateScale) {
+				t.Errorf("expected the rate of %s, got %+v", friday.Format("2006-01-02"), got)
+			}
+		})
+	}
+}
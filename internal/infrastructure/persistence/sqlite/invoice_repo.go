@@ -5,35 +5,111 @@ import (
 	"carigo/internal/domain"
 	"context"
 	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
 )
 
 type InvoiceModel struct {
 	ID          string `gorm:"primaryKey"`
 	CustomerID  string `gorm:"index"`
+	Kind        string
 	TotalAmount int64
 	Currency    string
 	PaidAmount  int64
 	Status      string
 	IssueDate   int64
 	DueDate     int64
-	CreatedAt   int64
-	UpdatedAt   int64
+	VoidedAt    int64 `gorm:"default:0"`
+	VoidReason  string
+	// InterestInvoicedThrough is zero until late interest is billed.
+	InterestInvoicedThrough int64 `gorm:"default:0"`
+	PaymentTermsColumns
+	CreatedAt int64
+	UpdatedAt int64
+}
+
+// InvoiceLineModel is one line of an invoice. Derived amounts are not stored;
+// they are recomputed from the inputs when the line is loaded.
+type InvoiceLineModel struct {
+	InvoiceID   string `gorm:"primaryKey"`
+	Position    int    `gorm:"primaryKey;autoIncrement:false"`
+	Description string
+	Quantity    int64
+	UnitPrice   int64
+	Discount    int64
+	Currency    string
+	VATRate     int
+	Withholding int
+}
+
+// InvoiceInstalmentModel is one entry of an invoice's payment schedule. What
+// is paid per instalment is derived from the invoice's PaidAmount.
+type InvoiceInstalmentModel struct {
+	InvoiceID string `gorm:"primaryKey"`
+	Number    int    `gorm:"primaryKey;autoIncrement:false"`
+	DueDate   int64
+	Amount    int64
+	Currency  string
 }
 
 func (r *GormRepository) SaveInvoice(ctx context.Context, i *domain.Invoice) error {
 	m := InvoiceModel{
-		ID:          string(i.ID),
-		CustomerID:  string(i.CustomerID),
-		TotalAmount: i.TotalAmount.Amount(),
-		Currency:    i.TotalAmount.Currency(),
-		PaidAmount:  i.PaidAmount.Amount(),
-		Status:      string(i.Status),
-		IssueDate:   i.IssueDate.Unix(),
-		DueDate:     i.DueDate.Unix(),
-		CreatedAt:   i.CreatedAt.Unix(),
-		UpdatedAt:   i.UpdatedAt.Unix(),
+		ID:                  string(i.ID),
+		CustomerID:          string(i.CustomerID),
+		Kind:                string(i.Kind),
+		TotalAmount:         i.TotalAmount.Amount(),
+		Currency:            i.TotalAmount.Currency(),
+		PaidAmount:          i.PaidAmount.Amount(),
+		Status:              string(i.Status),
+		IssueDate:           i.IssueDate.Unix(),
+		DueDate:             i.DueDate.Unix(),
+		VoidReason:          i.VoidReason,
+		PaymentTermsColumns: newPaymentTermsColumns(i.Terms),
+		CreatedAt:           i.CreatedAt.Unix(),
+		UpdatedAt:           i.UpdatedAt.Unix(),
+	}
+	if i.VoidedAt != nil {
+		m.VoidedAt = i.VoidedAt.Unix()
+	}
+	if !i.InterestInvoicedThrough.IsZero() {
+		m.InterestInvoicedThrough = i.InterestInvoicedThrough.Unix()
 	}
-	return r.getDB(ctx).Save(&m).Error
+	if err := r.getDB(ctx).Save(&m).Error; err != nil {
+		return err
+	}
+
+	for pos, l := range i.Lines {
+		line := InvoiceLineModel{
+			InvoiceID:   string(i.ID),
+			Position:    pos + 1,
+			Description: l.Description,
+			Quantity:    l.Quantity,
+			UnitPrice:   l.UnitPrice.Amount(),
+			Discount:    l.Discount.Amount(),
+			Currency:    l.UnitPrice.Currency(),
+			VATRate:     int(l.VATRate),
+			Withholding: int(l.Withholding),
+		}
+		if err := r.getDB(ctx).Save(&line).Error; err != nil {
+			return err
+		}
+	}
+
+	for n, inst := range i.Instalments {
+		instalment := InvoiceInstalmentModel{
+			InvoiceID: string(i.ID),
+			Number:    n + 1,
+			DueDate:   inst.DueDate.Unix(),
+			Amount:    inst.Amount.Amount(),
+			Currency:  inst.Amount.Currency(),
+		}
+		if err := r.getDB(ctx).Save(&instalment).Error; err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 type InvoiceAdapter struct{ repo *GormRepository }
@@ -42,8 +118,22 @@ func (a *InvoiceAdapter) Save(ctx context.Context, i *domain.Invoice) error {
 	return a.repo.SaveInvoice(ctx, i)
 }
 func (a *InvoiceAdapter) FindByID(ctx context.Context, id domain.InvoiceID) (*domain.Invoice, error) {
-	return nil, errors.New("not implemented")
-} 
+	var m InvoiceModel
+	if err := a.repo.getDB(ctx).First(&m, "id = ?", string(id)).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrInvoiceNotFound
+		}
+		return nil, err
+	}
+	inv, err := a.mapToDomain(m)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.attachDetails(ctx, inv); err != nil {
+		return nil, err
+	}
+	return inv, nil
+}
 func (a *InvoiceAdapter) FindOpenByCustomer(ctx context.Context, cid domain.CustomerID) ([]*domain.Invoice, error) {
 	var models []InvoiceModel
 	err := a.repo.getDB(ctx).
@@ -62,6 +152,9 @@ func (a *InvoiceAdapter) FindOpenByCustomer(ctx context.Context, cid domain.Cust
 		}
 		invoices = append(invoices, inv)
 	}
+	if err := a.attachDetails(ctx, invoices...); err != nil {
+		return nil, err
+	}
 	return invoices, nil
 }
 
@@ -80,6 +173,9 @@ func (a *InvoiceAdapter) FindAll(ctx context.Context) ([]*domain.Invoice, error)
 		}
 		invoices = append(invoices, inv)
 	}
+	if err := a.attachDetails(ctx, invoices...); err != nil {
+		return nil, err
+	}
 	return invoices, nil
 }
 
@@ -101,9 +197,81 @@ func (a *InvoiceAdapter) FindByCustomer(ctx context.Context, cid domain.Customer
 		}
 		invoices = append(invoices, inv)
 	}
+	if err := a.attachDetails(ctx, invoices...); err != nil {
+		return nil, err
+	}
 	return invoices, nil
 }
 
+// attachDetails loads the lines and instalments of the given invoices, with
+// one query for each.
+func (a *InvoiceAdapter) attachDetails(ctx context.Context, invoices ...*domain.Invoice) error {
+	if len(invoices) == 0 {
+		return nil
+	}
+	byID := make(map[string]*domain.Invoice, len(invoices))
+	ids := make([]string, 0, len(invoices))
+	for _, inv := range invoices {
+		byID[string(inv.ID)] = inv
+		ids = append(ids, string(inv.ID))
+	}
+
+	if err := a.attachLines(ctx, byID, ids); err != nil {
+		return err
+	}
+	return a.attachInstalments(ctx, byID, ids)
+}
+
+func (a *InvoiceAdapter) attachLines(ctx context.Context, byID map[string]*domain.Invoice, ids []string) error {
+	var models []InvoiceLineModel
+	err := a.repo.getDB(ctx).
+		Where("invoice_id IN ?", ids).
+		Order("invoice_id asc, position asc").
+		Find(&models).Error
+	if err != nil {
+		return err
+	}
+
+	for _, m := range models {
+		unitPrice, err := domain.NewMoney(m.UnitPrice, m.Currency)
+		if err != nil {
+			return err
+		}
+		discount, err := domain.NewMoney(m.Discount, m.Currency)
+		if err != nil {
+			return err
+		}
+		line, err := domain.NewInvoiceLine(m.Description, m.Quantity, unitPrice, discount, domain.VATRate(m.VATRate), domain.WithholdingRatio(m.Withholding))
+		if err != nil {
+			return err
+		}
+		inv := byID[m.InvoiceID]
+		inv.Lines = append(inv.Lines, line)
+	}
+	return nil
+}
+
+func (a *InvoiceAdapter) attachInstalments(ctx context.Context, byID map[string]*domain.Invoice, ids []string) error {
+	var models []InvoiceInstalmentModel
+	err := a.repo.getDB(ctx).
+		Where("invoice_id IN ?", ids).
+		Order("invoice_id asc, number asc").
+		Find(&models).Error
+	if err != nil {
+		return err
+	}
+
+	for _, m := range models {
+		amount, err := domain.NewMoney(m.Amount, m.Currency)
+		if err != nil {
+			return err
+		}
+		inv := byID[m.InvoiceID]
+		inv.Instalments = append(inv.Instalments, domain.Instalment{DueDate: parseTime(m.DueDate), Amount: amount})
+	}
+	return nil
+}
+
 func (a *InvoiceAdapter) mapToDomain(m InvoiceModel) (*domain.Invoice, error) {
 	total, err := domain.NewMoney(m.TotalAmount, m.Currency)
 	if err != nil {
@@ -113,13 +281,25 @@ func (a *InvoiceAdapter) mapToDomain(m InvoiceModel) (*domain.Invoice, error) {
 	if err != nil {
 		return nil, err
 	}
-	
+
 	paid, _ := domain.NewMoney(m.PaidAmount, m.Currency)
 	inv.PaidAmount = paid
 	inv.Status = domain.InvoiceStatus(m.Status)
+	if m.Kind != "" {
+		inv.Kind = domain.InvoiceKind(m.Kind)
+	}
 	inv.CreatedAt = parseTime(m.CreatedAt)
 	inv.UpdatedAt = parseTime(m.UpdatedAt)
-	
+	if m.VoidedAt != 0 {
+		voidedAt := parseTime(m.VoidedAt)
+		inv.VoidedAt = &voidedAt
+		inv.VoidReason = m.VoidReason
+	}
+	inv.Terms = m.PaymentTermsColumns.toDomain()
+	if m.InterestInvoicedThrough != 0 {
+		inv.InterestInvoicedThrough = parseTime(m.InterestInvoicedThrough)
+	}
+
 	return inv, nil
 }
 
@@ -135,8 +315,150 @@ func (a *InvoiceAdapter) SumTotalAmount(ctx context.Context) (int64, error) {
 	var total int64
 	err := a.repo.getDB(ctx).Model(&InvoiceModel{}).
 		Select("ifnull(sum(total_amount), 0)").
+		Where("status <> ?", string(domain.InvoiceStatusVoid)).
 		Scan(&total).Error
 	return total, err
 }
 
+// openObligationsSQL lists what was still owed at the cutoff @asOf on the
+// invoices issued by then and not yet voided: one row per invoice, or one per
+// instalment for invoices with a schedule. What was paid by then is read from
+// the allocations booked by then, less their reversals, rather than from the
+// invoice's current paid amount. Allocations written before cross-currency
+// allocations existed have no invoice currency and count in full. An
+// instalment's open part is what is left after the paid amount has covered
+// the instalments before it, computed with a running sum over the schedule.
+const openObligationsSQL = `
+WITH paid AS (
+	SELECT invoice_id,
+		sum(CASE WHEN type = 'REVERSAL' THEN -1 ELSE 1 END *
+			CASE WHEN ifnull(invoice_currency, '') = '' THEN amount ELSE invoice_amount END) AS amount
+	FROM allocation_models
+	WHERE created_at <= @asOf
+	GROUP BY invoice_id
+), open_invoices AS (
+	SELECT i.id, i.customer_id, i.currency, i.total_amount, ifnull(p.amount, 0) AS paid_amount, i.due_date
+	FROM invoice_models i
+	LEFT JOIN paid p ON p.invoice_id = i.id
+	WHERE i.issue_date <= @asOf AND (i.voided_at = 0 OR i.voided_at > @asOf)
+		AND i.total_amount > ifnull(p.amount, 0)
+), obligations AS (
+	SELECT i.id AS invoice_id, i.customer_id, i.currency, i.due_date, i.total_amount - i.paid_amount AS remaining
+	FROM open_invoices i
+	WHERE NOT EXISTS (SELECT 1 FROM invoice_instalment_models s WHERE s.invoice_id = i.id)
+	UNION ALL
+	SELECT i.id, i.customer_id, i.currency, s.due_date,
+		max(0, min(s.amount, sum(s.amount) OVER (PARTITION BY s.invoice_id ORDER BY s.number) - i.paid_amount))
+	FROM invoice_instalment_models s
+	JOIN open_invoices i ON i.id = s.invoice_id
+)`
+
+// obligationArgs are the named parameters of openObligationsSQL and the
+// queries built on it.
+func obligationArgs(asOf time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"asOf":          asOf.Unix(),
+		"day":           utcDay(asOf),
+		"secondsPerDay": secondsPerDay,
+	}
+}
+
+const secondsPerDay = 24 * 60 * 60
+
+// utcDay numbers the UTC calendar day of t, matching due_date / secondsPerDay.
+func utcDay(t time.Time) int64 {
+	return domain.RateDay(t).Unix() / secondsPerDay
+}
+
+// SumOpenByAge works on UTC calendar days.
+func (a *InvoiceAdapter) SumOpenByAge(ctx context.Context, asOf time.Time, buckets domain.AgingBuckets) ([]ports.AgedBalance, error) {
+	bucket := "CASE WHEN days <= 0 THEN 0"
+	args := obligationArgs(asOf)
+	for i, edge := range buckets {
+		bucket += fmt.Sprintf(" WHEN days <= @edge%d THEN %d", i, i+1)
+		args[fmt.Sprintf("edge%d", i)] = edge
+	}
+	bucket += fmt.Sprintf(" ELSE %d END", len(buckets)+1)
+
+	query := openObligationsSQL + `, aged AS (
+	SELECT customer_id, currency, remaining, @day - due_date / @secondsPerDay AS days
+	FROM obligations
+	WHERE remaining > 0
+)
+SELECT customer_id, currency, ` + bucket + ` AS bucket, sum(remaining) AS total
+FROM aged
+GROUP BY customer_id, currency, bucket
+ORDER BY customer_id, currency, bucket`
+
+	var rows []struct {
+		CustomerID string
+		Currency   string
+		Bucket     int
+		Total      int64
+	}
+	if err := a.repo.getDB(ctx).Raw(query, args).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	balances := make([]ports.AgedBalance, 0, len(rows))
+	for _, row := range rows {
+		amount, err := domain.NewMoney(row.Total, row.Currency)
+		if err != nil {
+			return nil, err
+		}
+		balances = append(balances, ports.AgedBalance{
+			CustomerID: domain.CustomerID(row.CustomerID),
+			Bucket:     row.Bucket,
+			Amount:     amount,
+		})
+	}
+	return balances, nil
+}
+
+// overdueInvoiceIDsSQL selects the IDs of invoices with an open obligation
+// due before the UTC day of the cutoff.
+const overdueInvoiceIDsSQL = openObligationsSQL + `
+SELECT DISTINCT invoice_id FROM obligations WHERE remaining > 0 AND due_date / @secondsPerDay < @day`
+
+func (a *InvoiceAdapter) FindOverdue(ctx context.Context, asOf time.Time) ([]*domain.Invoice, error) {
+	var ids []string
+	err := a.repo.getDB(ctx).Raw(overdueInvoiceIDsSQL, obligationArgs(asOf)).Scan(&ids).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var models []InvoiceModel
+	err = a.repo.getDB(ctx).
+		Where("id IN ?", ids).
+		Order("due_date asc, id asc").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var invoices []*domain.Invoice
+	for _, m := range models {
+		inv, err := a.mapToDomain(m)
+		if err != nil {
+			return nil, err
+		}
+		invoices = append(invoices, inv)
+	}
+	if err := a.attachDetails(ctx, invoices...); err != nil {
+		return nil, err
+	}
+	return invoices, nil
+}
+
+func (a *InvoiceAdapter) CountOverdue(ctx context.Context, asOf time.Time) (int64, error) {
+	var count int64
+	err := a.repo.getDB(ctx).
+		Raw("SELECT count(*) FROM ("+overdueInvoiceIDsSQL+")", obligationArgs(asOf)).
+		Scan(&count).Error
+	return count, err
+}
+
 var _ ports.InvoiceRepository = &InvoiceAdapter{}
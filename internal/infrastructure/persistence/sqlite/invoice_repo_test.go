@@ -0,0 +1,119 @@
+package sqlite_test
+
+import (
+	"carigo/internal/domain"
+	"carigo/internal/infrastructure/persistence/sqlite"
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestRepositories(t *testing.T) *sqlite.Repositories {
+	t.Helper()
+	repos, err := sqlite.NewRepositories(filepath.Join(t.TempDir(), "carigo.db"))
+	if err != nil {
+		t.Fatalf("failed to open the database: %v", err)
+	}
+	return repos
+}
+
+func tryMoney(amount int64) domain.Money {
+	m, _ := domain.NewMoney(amount, "TRY")
+	return m
+}
+
+func day(month time.Month, d int) time.Time {
+	return time.Date(2026, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestSumOpenByAge_AsOfPastDate(t *testing.T) {
+	ctx := context.Background()
+	repos := newTestRepositories(t)
+	invoice := func(id domain.InvoiceID, amount int64) *domain.Invoice {
+		inv, err := domain.NewInvoice(id, "CUST-001", tryMoney(amount), day(9, 1), day(9, 30))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return inv
+	}
+
+	// Paid in full and in part after the first of October.
+	paidLater, partlyPaidLater := invoice("INV-A", 100000), invoice("INV-B", 50000)
+	payment := domain.NewPayment("PAY-1", "CUST-001", tryMoney(120000), day(10, 10))
+	full, _ := domain.NewAllocation("AL-1", payment, paidLater, tryMoney(100000))
+	part, _ := domain.NewAllocation("AL-2", payment, partlyPaidLater, tryMoney(20000))
+	full.CreatedAt, part.CreatedAt = day(10, 10), day(10, 10)
+
+	// Voided after the first of October.
+	voidedLater := invoice("INV-C", 30000)
+	if err := voidedLater.Void("hatalı"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	voidedAt := day(10, 5)
+	voidedLater.VoidedAt = &voidedAt
+
+	// Paid in part before the first of October, released again after it.
+	released := invoice("INV-D", 40000)
+	early := domain.NewPayment("PAY-2", "CUST-001", tryMoney(10000), day(9, 20))
+	applied, _ := domain.NewAllocation("AL-3", early, released, tryMoney(10000))
+	applied.CreatedAt = day(9, 20)
+	reversal, err := applied.Reverse("AL-3-R", early, released, "yanlış fatura", day(10, 12))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, inv := range []*domain.Invoice{paidLater, partlyPaidLater, voidedLater, released} {
+		if err := repos.Invoices.Save(ctx, inv); err != nil {
+			t.Fatalf("failed to save %s: %v", inv.ID, err)
+		}
+	}
+	for _, p := range []*domain.Payment{payment, early} {
+		if err := repos.Payments.Save(ctx, p); err != nil {
+			t.Fatalf("failed to save %s: %v", p.ID, err)
+		}
+	}
+	for _, a := range []*domain.Allocation{full, part, applied, reversal} {
+		if err := repos.Allocations.Save(ctx, a); err != nil {
+			t.Fatalf("failed to save %s: %v", a.ID, err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		asOf time.Time
+		want int64
+	}{
+		// 1000 + 500 + 300 + (400 - 100)
+		{"before the later payment, void and release", day(10, 1), 210000},
+		// 500 - 200, and INV-D open again in full
+		{"after them", day(10, 15), 70000},
+		{"before anything was issued", day(8, 31), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			balances, err := repos.Invoices.SumOpenByAge(ctx, tt.asOf, domain.DefaultAgingBuckets)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var total int64
+			for _, b := range balances {
+				if b.Bucket != 1 {
+					t.Errorf("expected everything in the 1-30 days bucket, got %d in bucket %d", b.Amount.Amount(), b.Bucket)
+				}
+				total += b.Amount.Amount()
+			}
+			if total != tt.want {
+				t.Errorf("expected %d open, got %d", tt.want, total)
+			}
+		})
+	}
+
+	overdue, err := repos.Invoices.FindOverdue(ctx, day(10, 1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(overdue) != 4 {
+		t.Errorf("expected all four invoices overdue on the first of October, got %d", len(overdue))
+	}
+}
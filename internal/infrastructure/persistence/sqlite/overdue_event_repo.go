@@ -0,0 +1,80 @@
+package sqlite
+
+import (
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"context"
+)
+
+type OverdueEventModel struct {
+	ID         string `gorm:"primaryKey"`
+	InvoiceID  string `gorm:"index"`
+	CustomerID string `gorm:"index"`
+	Instalment int
+	DueDate    int64
+	Amount     int64
+	Currency   string
+	DetectedAt int64 `gorm:"index"`
+}
+
+type OverdueEventAdapter struct{ repo *GormRepository }
+
+func (a *OverdueEventAdapter) Save(ctx context.Context, e *domain.OverdueEvent) error {
+	m := OverdueEventModel{
+		ID:         string(e.ID),
+		InvoiceID:  string(e.InvoiceID),
+		CustomerID: string(e.CustomerID),
+		Instalment: e.Instalment,
+		DueDate:    e.DueDate.Unix(),
+		Amount:     e.Amount.Amount(),
+		Currency:   e.Amount.Currency(),
+		DetectedAt: e.DetectedAt.Unix(),
+	}
+	return a.repo.getDB(ctx).Save(&m).Error
+}
+
+func (a *OverdueEventAdapter) FindByInvoice(ctx context.Context, invoiceID domain.InvoiceID) ([]*domain.OverdueEvent, error) {
+	var models []OverdueEventModel
+	err := a.repo.getDB(ctx).
+		Where("invoice_id = ?", string(invoiceID)).
+		Order("instalment asc").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	return a.mapAll(models)
+}
+
+func (a *OverdueEventAdapter) FindRecent(ctx context.Context, limit int) ([]*domain.OverdueEvent, error) {
+	var models []OverdueEventModel
+	err := a.repo.getDB(ctx).
+		Order("detected_at desc, id asc").
+		Limit(limit).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	return a.mapAll(models)
+}
+
+func (a *OverdueEventAdapter) mapAll(models []OverdueEventModel) ([]*domain.OverdueEvent, error) {
+	events := make([]*domain.OverdueEvent, 0, len(models))
+	for _, m := range models {
+		amount, err := domain.NewMoney(m.Amount, m.Currency)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, &domain.OverdueEvent{
+			ID:         domain.OverdueEventID(m.ID),
+			InvoiceID:  domain.InvoiceID(m.InvoiceID),
+			CustomerID: domain.CustomerID(m.CustomerID),
+			Instalment: m.Instalment,
+			DueDate:    parseTime(m.DueDate),
+			Amount:     amount,
+			DetectedAt: parseTime(m.DetectedAt),
+		})
+	}
+	return events, nil
+}
+
+var _ ports.OverdueEventRepository = &OverdueEventAdapter{}
@@ -5,6 +5,8 @@ import (
 	"carigo/internal/domain"
 	"context"
 	"errors"
+
+	"gorm.io/gorm"
 )
 
 type PaymentModel struct {
@@ -14,6 +16,8 @@ type PaymentModel struct {
 	Currency        string
 	AvailableAmount int64
 	Date            int64
+	ReversedAt      int64 `gorm:"default:0"`
+	ReversalReason  string
 	CreatedAt       int64
 }
 
@@ -25,8 +29,12 @@ func (r *GormRepository) SavePayment(ctx context.Context, p *domain.Payment) err
 		Currency:        p.Amount.Currency(),
 		AvailableAmount: p.AvailableAmount.Amount(),
 		Date:            p.Date.Unix(),
+		ReversalReason:  p.ReversalReason,
 		CreatedAt:       p.CreatedAt.Unix(),
 	}
+	if p.ReversedAt != nil {
+		m.ReversedAt = p.ReversedAt.Unix()
+	}
 	return r.getDB(ctx).Save(&m).Error
 }
 
@@ -36,7 +44,29 @@ func (a *PaymentAdapter) Save(ctx context.Context, p *domain.Payment) error {
 	return a.repo.SavePayment(ctx, p)
 }
 func (a *PaymentAdapter) FindByID(ctx context.Context, id domain.PaymentID) (*domain.Payment, error) {
-	return nil, errors.New("not implemented")
+	var m PaymentModel
+	if err := a.repo.getDB(ctx).First(&m, "id = ?", string(id)).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrPaymentNotFound
+		}
+		return nil, err
+	}
+	return a.mapToDomain(m), nil
+}
+
+func (a *PaymentAdapter) mapToDomain(m PaymentModel) *domain.Payment {
+	amount, _ := domain.NewMoney(m.Amount, m.Currency)
+	p := domain.NewPayment(domain.PaymentID(m.ID), domain.CustomerID(m.CustomerID), amount, parseTime(m.Date))
+
+	avail, _ := domain.NewMoney(m.AvailableAmount, m.Currency)
+	p.AvailableAmount = avail
+	p.CreatedAt = parseTime(m.CreatedAt)
+	if m.ReversedAt != 0 {
+		reversedAt := parseTime(m.ReversedAt)
+		p.ReversedAt = &reversedAt
+		p.ReversalReason = m.ReversalReason
+	}
+	return p
 }
 
 func (a *PaymentAdapter) FindAll(ctx context.Context) ([]*domain.Payment, error) {
@@ -48,14 +78,7 @@ func (a *PaymentAdapter) FindAll(ctx context.Context) ([]*domain.Payment, error)
 
 	var payments []*domain.Payment
 	for _, m := range models {
-		amount, _ := domain.NewMoney(m.Amount, m.Currency)
-		p := domain.NewPayment(domain.PaymentID(m.ID), domain.CustomerID(m.CustomerID), amount, parseTime(m.Date))
-		
-		avail, _ := domain.NewMoney(m.AvailableAmount, m.Currency)
-		p.AvailableAmount = avail
-		p.CreatedAt = parseTime(m.CreatedAt)
-		
-		payments = append(payments, p)
+		payments = append(payments, a.mapToDomain(m))
 	}
 	return payments, nil
 }
@@ -72,14 +95,24 @@ func (a *PaymentAdapter) FindByCustomer(ctx context.Context, cid domain.Customer
 
 	var payments []*domain.Payment
 	for _, m := range models {
-		amount, _ := domain.NewMoney(m.Amount, m.Currency)
-		p := domain.NewPayment(domain.PaymentID(m.ID), domain.CustomerID(m.CustomerID), amount, parseTime(m.Date))
-		
-		avail, _ := domain.NewMoney(m.AvailableAmount, m.Currency)
-		p.AvailableAmount = avail
-		p.CreatedAt = parseTime(m.CreatedAt)
-		
-		payments = append(payments, p)
+		payments = append(payments, a.mapToDomain(m))
+	}
+	return payments, nil
+}
+
+func (a *PaymentAdapter) FindUnallocatedByCustomer(ctx context.Context, cid domain.CustomerID) ([]*domain.Payment, error) {
+	var models []PaymentModel
+	err := a.repo.getDB(ctx).
+		Where("customer_id = ? AND available_amount > 0", string(cid)).
+		Order("date asc").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var payments []*domain.Payment
+	for _, m := range models {
+		payments = append(payments, a.mapToDomain(m))
 	}
 	return payments, nil
 }
@@ -88,8 +121,39 @@ func (a *PaymentAdapter) SumTotalCollected(ctx context.Context) (int64, error) {
 	var total int64
 	err := a.repo.getDB(ctx).Model(&PaymentModel{}).
 		Select("ifnull(sum(amount), 0)").
+		Where("reversed_at = 0").
 		Scan(&total).Error
 	return total, err
 }
 
+func (a *PaymentAdapter) SumAvailableByCustomer(ctx context.Context) ([]ports.CustomerCredit, error) {
+	var rows []struct {
+		CustomerID string
+		Currency   string
+		Total      int64
+	}
+	err := a.repo.getDB(ctx).Model(&PaymentModel{}).
+		Select("customer_id, currency, sum(available_amount) as total").
+		Where("available_amount > 0").
+		Group("customer_id, currency").
+		Order("customer_id, currency").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	credits := make([]ports.CustomerCredit, 0, len(rows))
+	for _, row := range rows {
+		amount, err := domain.NewMoney(row.Total, row.Currency)
+		if err != nil {
+			return nil, err
+		}
+		credits = append(credits, ports.CustomerCredit{
+			CustomerID: domain.CustomerID(row.CustomerID),
+			Amount:     amount,
+		})
+	}
+	return credits, nil
+}
+
 var _ ports.PaymentRepository = &PaymentAdapter{}
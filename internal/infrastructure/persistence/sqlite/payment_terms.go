@@ -0,0 +1,39 @@
+package sqlite
+
+import "carigo/internal/domain"
+
+// PaymentTermsColumns store domain.PaymentTerms on the customer and invoice
+// tables. DiscountPercent is in domain.RateScale units.
+type PaymentTermsColumns struct {
+	TermsBasis        string
+	NetDays           int
+	TermsDayOfMonth   int
+	TermsInstalments  int
+	TermsBusinessDays bool
+	DiscountPercent   int64
+	DiscountDays      int
+}
+
+func newPaymentTermsColumns(t domain.PaymentTerms) PaymentTermsColumns {
+	return PaymentTermsColumns{
+		TermsBasis:        string(t.Basis),
+		NetDays:           t.NetDays,
+		TermsDayOfMonth:   t.DayOfMonth,
+		TermsInstalments:  t.Instalments,
+		TermsBusinessDays: t.BusinessDays,
+		DiscountPercent:   t.DiscountPercent,
+		DiscountDays:      t.DiscountDays,
+	}
+}
+
+func (c PaymentTermsColumns) toDomain() domain.PaymentTerms {
+	return domain.PaymentTerms{
+		Basis:           domain.TermsBasis(c.TermsBasis),
+		NetDays:         c.NetDays,
+		DayOfMonth:      c.TermsDayOfMonth,
+		Instalments:     c.TermsInstalments,
+		BusinessDays:    c.TermsBusinessDays,
+		DiscountPercent: c.DiscountPercent,
+		DiscountDays:    c.DiscountDays,
+	}
+}
@@ -0,0 +1,146 @@
+package sqlite
+
+import (
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type ReconciliationModel struct {
+	ID          string `gorm:"primaryKey"`
+	CustomerID  string `gorm:"index"`
+	AsOf        int64
+	Status      string `gorm:"index"`
+	Note        string
+	CreatedAt   int64
+	RespondedAt int64 `gorm:"default:0"`
+}
+
+// Reconciliation balance sides: ours as snapshotted, or as the customer
+// claimed them.
+const (
+	reconciliationSideOurs    = "OURS"
+	reconciliationSideClaimed = "CLAIMED"
+)
+
+// ReconciliationBalanceModel is one side's balance of a reconciliation in one
+// currency.
+type ReconciliationBalanceModel struct {
+	ReconciliationID string `gorm:"primaryKey"`
+	Side             string `gorm:"primaryKey"`
+	Currency         string `gorm:"primaryKey"`
+	Amount           int64
+}
+
+type ReconciliationAdapter struct{ repo *GormRepository }
+
+func (a *ReconciliationAdapter) Save(ctx context.Context, r *domain.Reconciliation) error {
+	m := ReconciliationModel{
+		ID:         string(r.ID),
+		CustomerID: string(r.CustomerID),
+		AsOf:       r.AsOf.Unix(),
+		Status:     string(r.Status),
+		Note:       r.Note,
+		CreatedAt:  r.CreatedAt.Unix(),
+	}
+	if r.RespondedAt != nil {
+		m.RespondedAt = r.RespondedAt.Unix()
+	}
+	if err := a.repo.getDB(ctx).Save(&m).Error; err != nil {
+		return err
+	}
+
+	if err := a.repo.getDB(ctx).Where("reconciliation_id = ?", m.ID).Delete(&ReconciliationBalanceModel{}).Error; err != nil {
+		return err
+	}
+	sides := map[string][]domain.Balance{
+		reconciliationSideOurs:    r.Balances,
+		reconciliationSideClaimed: r.Claimed,
+	}
+	for side, balances := range sides {
+		for _, b := range balances {
+			bm := ReconciliationBalanceModel{ReconciliationID: m.ID, Side: side, Currency: b.Currency(), Amount: b.Amount()}
+			if err := a.repo.getDB(ctx).Create(&bm).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (a *ReconciliationAdapter) FindByID(ctx context.Context, id domain.ReconciliationID) (*domain.Reconciliation, error) {
+	var m ReconciliationModel
+	if err := a.repo.getDB(ctx).First(&m, "id = ?", string(id)).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrReconciliationNotFound
+		}
+		return nil, err
+	}
+	found, err := a.mapToDomain(ctx, []ReconciliationModel{m})
+	if err != nil {
+		return nil, err
+	}
+	return found[0], nil
+}
+
+func (a *ReconciliationAdapter) FindByStatus(ctx context.Context, status domain.ReconciliationStatus) ([]*domain.Reconciliation, error) {
+	db := a.repo.getDB(ctx)
+	if status != "" {
+		db = db.Where("status = ?", string(status))
+	}
+	var models []ReconciliationModel
+	if err := db.Order("created_at desc, id desc").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return a.mapToDomain(ctx, models)
+}
+
+// mapToDomain loads the balances of all models in one query.
+func (a *ReconciliationAdapter) mapToDomain(ctx context.Context, models []ReconciliationModel) ([]*domain.Reconciliation, error) {
+	if len(models) == 0 {
+		return nil, nil
+	}
+	ids := make([]string, len(models))
+	byID := make(map[string]*domain.Reconciliation, len(models))
+	reconciliations := make([]*domain.Reconciliation, len(models))
+	for i, m := range models {
+		r := &domain.Reconciliation{
+			ID:         domain.ReconciliationID(m.ID),
+			CustomerID: domain.CustomerID(m.CustomerID),
+			AsOf:       parseTime(m.AsOf).UTC(),
+			Status:     domain.ReconciliationStatus(m.Status),
+			Note:       m.Note,
+			CreatedAt:  parseTime(m.CreatedAt),
+		}
+		if m.RespondedAt != 0 {
+			respondedAt := parseTime(m.RespondedAt)
+			r.RespondedAt = &respondedAt
+		}
+		ids[i] = m.ID
+		byID[m.ID] = r
+		reconciliations[i] = r
+	}
+
+	var balances []ReconciliationBalanceModel
+	if err := a.repo.getDB(ctx).Where("reconciliation_id IN ?", ids).Order("currency asc").Find(&balances).Error; err != nil {
+		return nil, err
+	}
+	for _, bm := range balances {
+		b, err := domain.NewBalanceOf(bm.Amount, bm.Currency)
+		if err != nil {
+			return nil, err
+		}
+		r := byID[bm.ReconciliationID]
+		if bm.Side == reconciliationSideClaimed {
+			r.Claimed = append(r.Claimed, b)
+		} else {
+			r.Balances = append(r.Balances, b)
+		}
+	}
+	return reconciliations, nil
+}
+
+var _ ports.ReconciliationRepository = &ReconciliationAdapter{}
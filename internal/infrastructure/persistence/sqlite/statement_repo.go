@@ -0,0 +1,81 @@
+package sqlite
+
+import (
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"context"
+	"time"
+)
+
+// balancesBeforeQuery books the movements of a customer's account the way
+// the statement does: invoices are debits on their issue date and credits
+// when voided, payments are credits on their date and debits when reversed,
+// early-payment discounts are credits on the payment date and debits when
+// reversed, credit notes are credits, and cross-currency settlements are a
+// debit in the source currency and a credit in the invoice currency on the
+// day they were made, and the opposite when reversed.
+const balancesBeforeQuery = `
+SELECT currency, SUM(amount) AS amount FROM (
+	SELECT currency, total_amount AS amount FROM invoice_models
+		WHERE customer_id = @customer AND issue_date < @before
+	UNION ALL
+	SELECT currency, -total_amount FROM invoice_models
+		WHERE customer_id = @customer AND voided_at != 0 AND voided_at < @before
+	UNION ALL
+	SELECT currency, -amount FROM payment_models
+		WHERE customer_id = @customer AND date < @before
+	UNION ALL
+	SELECT currency, amount FROM payment_models
+		WHERE customer_id = @customer AND reversed_at != 0 AND reversed_at < @before
+	UNION ALL
+	SELECT a.invoice_currency, -a.invoice_amount FROM allocation_models a
+		JOIN payment_models p ON p.id = a.payment_id
+		WHERE a.type = @discount AND p.customer_id = @customer AND p.date < @before
+	UNION ALL
+	SELECT a.invoice_currency, a.invoice_amount FROM allocation_models a
+		JOIN payment_models p ON p.id = a.payment_id
+		JOIN allocation_models r ON r.reversal_of = a.id
+		WHERE a.type = @discount AND p.customer_id = @customer AND r.created_at < @before
+	UNION ALL
+	SELECT currency, -amount FROM credit_note_models
+		WHERE customer_id = @customer AND date < @before
+	UNION ALL
+	SELECT a.currency, CASE WHEN a.type = @reversal THEN -a.amount ELSE a.amount END FROM allocation_models a
+		JOIN invoice_models i ON i.id = a.invoice_id
+		WHERE a.exchange_rate != 0 AND a.type IN (@application, @reversal) AND i.customer_id = @customer AND a.created_at < @before
+	UNION ALL
+	SELECT a.invoice_currency, CASE WHEN a.type = @reversal THEN a.invoice_amount ELSE -a.invoice_amount END FROM allocation_models a
+		JOIN invoice_models i ON i.id = a.invoice_id
+		WHERE a.exchange_rate != 0 AND a.type IN (@application, @reversal) AND i.customer_id = @customer AND a.created_at < @before
+) GROUP BY currency ORDER BY currency`
+
+type StatementAdapter struct{ repo *GormRepository }
+
+func (a *StatementAdapter) BalancesBefore(ctx context.Context, customerID domain.CustomerID, before time.Time) ([]domain.Balance, error) {
+	var rows []struct {
+		Currency string
+		Amount   int64
+	}
+	args := map[string]interface{}{
+		"customer":    string(customerID),
+		"before":      before.Unix(),
+		"discount":    string(domain.AllocationTypeDiscount),
+		"application": string(domain.AllocationTypeApplication),
+		"reversal":    string(domain.AllocationTypeReversal),
+	}
+	if err := a.repo.getDB(ctx).Raw(balancesBeforeQuery, args).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	balances := make([]domain.Balance, 0, len(rows))
+	for _, row := range rows {
+		b, err := domain.NewBalanceOf(row.Amount, row.Currency)
+		if err != nil {
+			return nil, err
+		}
+		balances = append(balances, b)
+	}
+	return balances, nil
+}
+
+var _ ports.StatementRepository = &StatementAdapter{}
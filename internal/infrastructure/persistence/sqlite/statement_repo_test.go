@@ -0,0 +1,215 @@
+package sqlite_test
+
+import (
+	"carigo/internal/application/dto"
+	"carigo/internal/application/usecases"
+	"carigo/internal/domain"
+	"context"
+	"testing"
+	"time"
+)
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+// TestBalancesBefore_MatchesStatement books one of every kind of movement
+// and checks that, wherever the statement period starts, the opening balance
+// the query carries in plus the entries of the period come to the balance of
+// the statement with no period.
+func TestBalancesBefore_MatchesStatement(t *testing.T) {
+	ctx := context.Background()
+	repos := newTestRepositories(t)
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	customer, err := domain.NewCustomer("CUST-001", "Yılmaz Ticaret", "info@yilmaz.com.tr", "1234567890")
+	must(err)
+	must(repos.Customers.Save(ctx, customer))
+
+	usd, _ := domain.NewMoney(20000, "USD")
+	invoice, err := domain.NewInvoice("INV-1", customer.ID, tryMoney(100000), day(9, 1), day(9, 30))
+	must(err)
+	voided, err := domain.NewInvoice("INV-2", customer.ID, tryMoney(50000), day(9, 5), day(10, 5))
+	must(err)
+	must(voided.Void("hatalı"))
+	voidedAt := day(9, 20).Add(15 * time.Hour)
+	voided.VoidedAt = &voidedAt
+	foreign, err := domain.NewInvoice("INV-3", customer.ID, usd, day(10, 3), day(11, 3))
+	must(err)
+
+	// Paid in part with an early-payment discount that is released again.
+	payment := domain.NewPayment("PAY-1", customer.ID, tryMoney(30000), day(9, 10))
+	applied, err := domain.NewAllocation("AL-1", payment, invoice, tryMoney(30000))
+	must(err)
+	discount, err := domain.NewDiscountAllocation("AL-2", payment, invoice, tryMoney(2000))
+	must(err)
+	applied.CreatedAt, discount.CreatedAt = day(9, 10), day(9, 10)
+	released, err := discount.Reverse("AL-2-R", payment, invoice, "iskonto süresi geçmiş", day(10, 8).Add(9*time.Hour))
+	must(err)
+
+	// Booked by mistake and reversed on the first day of a period.
+	mistaken := domain.NewPayment("PAY-2", customer.ID, tryMoney(10000), day(9, 25))
+	must(mistaken.Reverse("yanlış müşteri", day(10, 1).Add(14*time.Hour)))
+
+	note, err := domain.NewCreditNote("CN-1", customer.ID, "", tryMoney(5000), day(10, 5), "iade")
+	must(err)
+	fifty, _ := domain.NewMoney(5000, "USD")
+	onAccount := domain.NewPayment("PAY-3", customer.ID, fifty, day(10, 20))
+
+	for _, inv := range []*domain.Invoice{invoice, voided, foreign} {
+		must(repos.Invoices.Save(ctx, inv))
+	}
+	for _, p := range []*domain.Payment{payment, mistaken, onAccount} {
+		must(repos.Payments.Save(ctx, p))
+	}
+	for _, a := range []*domain.Allocation{applied, discount, released} {
+		must(repos.Allocations.Save(ctx, a))
+	}
+	must(repos.CreditNotes.Save(ctx, note))
+
+	policy, err := domain.NewCreditPolicy("", 0)
+	must(err)
+	uc := usecases.NewGetCustomerStatementUseCase(repos.Customers, repos.Invoices, repos.Payments, repos.CreditNotes,
+		repos.Allocations, repos.Statements, policy, fixedClock(day(10, 31)))
+
+	full, err := uc.Execute(ctx, string(customer.ID), dto.StatementRequest{})
+	must(err)
+	want := map[string]string{}
+	for _, c := range full.Currencies {
+		want[c.Currency] = c.FinalBalance
+	}
+	// 1000 - 300 - 20 + 20 - 50 (+ 500 - 500, + 100 - 100), and 200 - 50
+	if want["TRY"] != "650.00" || want["USD"] != "150.00" {
+		t.Fatalf("unexpected final balances %v", want)
+	}
+
+	for _, from := range []time.Time{day(9, 1), day(9, 10), day(9, 20), day(9, 21), day(10, 1), day(10, 2), day(10, 8), day(10, 21), day(12, 1)} {
+		t.Run(from.Format("2006-01-02"), func(t *testing.T) {
+			st, err := uc.Execute(ctx, string(customer.ID), dto.StatementRequest{From: from})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got := map[string]string{}
+			for _, c := range st.Currencies {
+				got[c.Currency] = c.FinalBalance
+			}
+			for currency, balance := range want {
+				if got[currency] != balance {
+					t.Errorf("%s: opening balance and entries come to %q, want %q", currency, got[currency], balance)
+				}
+			}
+		})
+	}
+}
+
+// TestBalancesBefore_CrossCurrency settles TRY invoices with a EUR payment
+// and a EUR credit note, reverses one settlement, and checks that the
+// opening balance the query carries into each period is the balance the
+// statement reaches the day before.
+func TestBalancesBefore_CrossCurrency(t *testing.T) {
+	ctx := context.Background()
+	repos := newTestRepositories(t)
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	eur := func(amount int64) domain.Money {
+		m, _ := domain.NewMoney(amount, "EUR")
+		return m
+	}
+
+	customer, err := domain.NewCustomer("CUST-001", "Yılmaz Ticaret", "info@yilmaz.com.tr", "1234567890")
+	must(err)
+	must(repos.Customers.Save(ctx, customer))
+	rate, err := domain.NewExchangeRate("EUR", "TRY", 35*domain.RateScale, day(9, 10), domain.ExchangeRateSourceManual)
+	must(err)
+
+	// Settled in full by a EUR payment.
+	settled, err := domain.NewInvoice("INV-1", customer.ID, tryMoney(350000), day(9, 1), day(9, 30))
+	must(err)
+	payment := domain.NewPayment("PAY-1", customer.ID, eur(10000), day(9, 10))
+	full, err := domain.NewAllocationAtRate("AL-1", payment, settled, eur(10000), rate)
+	must(err)
+	full.CreatedAt = day(9, 12)
+
+	// Settled by a EUR payment, released, then settled in part by a EUR
+	// credit note.
+	reopened, err := domain.NewInvoice("INV-2", customer.ID, tryMoney(70000), day(9, 15), day(10, 15))
+	must(err)
+	second := domain.NewPayment("PAY-2", customer.ID, eur(5000), day(9, 20))
+	applied, err := domain.NewAllocationAtRate("AL-2", second, reopened, eur(5000), rate)
+	must(err)
+	applied.CreatedAt = day(9, 20)
+	released, err := applied.Reverse("AL-2-R", second, reopened, "yanlış fatura", day(10, 3).Add(11*time.Hour))
+	must(err)
+	note, err := domain.NewCreditNote("CN-1", customer.ID, "", eur(1000), day(10, 5), "iade")
+	must(err)
+	credited, err := domain.NewAllocationAtRate("AL-3", note, reopened, eur(1000), rate)
+	must(err)
+	credited.CreatedAt = day(10, 6)
+
+	for _, inv := range []*domain.Invoice{settled, reopened} {
+		must(repos.Invoices.Save(ctx, inv))
+	}
+	for _, p := range []*domain.Payment{payment, second} {
+		must(repos.Payments.Save(ctx, p))
+	}
+	must(repos.CreditNotes.Save(ctx, note))
+	for _, a := range []*domain.Allocation{full, applied, released, credited} {
+		must(repos.Allocations.Save(ctx, a))
+	}
+
+	policy, err := domain.NewCreditPolicy("", 0)
+	must(err)
+	uc := usecases.NewGetCustomerStatementUseCase(repos.Customers, repos.Invoices, repos.Payments, repos.CreditNotes,
+		repos.Allocations, repos.Statements, policy, fixedClock(day(10, 31)))
+	balances := func(currencies []dto.CurrencyStatement, opening bool) map[string]string {
+		m := map[string]string{}
+		for _, c := range currencies {
+			b := c.FinalBalance
+			if opening {
+				b = c.OpeningBalance
+			}
+			if b != "0.00" {
+				m[c.Currency] = b
+			}
+		}
+		return m
+	}
+
+	whole, err := uc.Execute(ctx, string(customer.ID), dto.StatementRequest{})
+	must(err)
+	// 3500 + 700 - 3500 - 700 + 700 - 350, and -100 - 50 + 100 + 20 - 20 - 10 + 10
+	if got := balances(whole.Currencies, false); got["TRY"] != "350.00" || got["EUR"] != "-50.00" || len(got) != 2 {
+		t.Fatalf("unexpected final balances %v", got)
+	}
+
+	for _, from := range []time.Time{day(9, 11), day(9, 12), day(9, 13), day(9, 21), day(10, 3), day(10, 4), day(10, 6), day(10, 7)} {
+		t.Run(from.Format("2006-01-02"), func(t *testing.T) {
+			before, err := uc.Execute(ctx, string(customer.ID), dto.StatementRequest{To: from.AddDate(0, 0, -1)})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			period, err := uc.Execute(ctx, string(customer.ID), dto.StatementRequest{From: from})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			want, got := balances(before.Currencies, false), balances(period.Currencies, true)
+			if len(got) != len(want) {
+				t.Errorf("expected opening balances %v, got %v", want, got)
+			}
+			for currency, balance := range want {
+				if got[currency] != balance {
+					t.Errorf("%s: expected an opening balance of %s, got %q", currency, balance, got[currency])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Job is a unit of background work run by Every.
+type Job func(ctx context.Context) error
+
+// Every runs job once right away and then at every interval, in its own
+// goroutine, until ctx is cancelled. Errors are logged and do not stop the
+// schedule; a run that overruns the interval delays the next one.
+func Every(ctx context.Context, name string, interval time.Duration, job Job) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := job(ctx); err != nil {
+				log.Printf("job %s failed: %v", name, err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
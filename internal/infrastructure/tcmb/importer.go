@@ -0,0 +1,127 @@
+// Package tcmb reads the daily exchange rate bulletin published by the
+// Central Bank of the Republic of Turkey (today.xml and its archived copies).
+package tcmb
+
+import (
+	"carigo/internal/application/ports"
+	"carigo/internal/domain"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+type bulletin struct {
+	XMLName    xml.Name   `xml:"Tarih_Date"`
+	Tarih      string     `xml:"Tarih,attr"`
+	Currencies []currency `xml:"Currency"`
+}
+
+type currency struct {
+	Code        string `xml:"CurrencyCode,attr"`
+	Unit        int64  `xml:"Unit"`
+	ForexBuying string `xml:"ForexBuying"`
+}
+
+// FileImporter loads a bulletin from a file on the local disk. Every currency
+// is imported against TRY at its forex buying rate (döviz alış kuru), divided
+// by the bulletin's unit so that, e.g., the JPY rate is per single yen.
+type FileImporter struct{}
+
+func NewFileImporter() *FileImporter {
+	return &FileImporter{}
+}
+
+func (i *FileImporter) Import(ctx context.Context, path string) ([]*domain.ExchangeRate, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Parse(f)
+}
+
+// Parse decodes a bulletin. Currencies without a forex buying rate, such as
+// the SDR line, are skipped.
+func Parse(r io.Reader) ([]*domain.ExchangeRate, error) {
+	dec := xml.NewDecoder(r)
+	dec.CharsetReader = charsetReader
+
+	var b bulletin
+	if err := dec.Decode(&b); err != nil {
+		return nil, fmt.Errorf("tcmb: %w", err)
+	}
+
+	date, err := time.Parse("02.01.2006", b.Tarih)
+	if err != nil {
+		return nil, fmt.Errorf("tcmb: invalid bulletin date %q: %w", b.Tarih, err)
+	}
+
+	var rates []*domain.ExchangeRate
+	for _, c := range b.Currencies {
+		if strings.TrimSpace(c.ForexBuying) == "" {
+			continue
+		}
+		// The bulletin also lists units such as XDR that are not billing currencies.
+		if _, err := domain.LookupCurrency(c.Code); err != nil {
+			continue
+		}
+		perUnit, err := domain.ParseRate(c.ForexBuying)
+		if err != nil {
+			return nil, fmt.Errorf("tcmb: %s: %w", c.Code, err)
+		}
+		unit := c.Unit
+		if unit <= 0 {
+			unit = 1
+		}
+
+		rate, err := domain.NewExchangeRate(c.Code, "TRY", (perUnit+unit/2)/unit, date, domain.ExchangeRateSourceTCMB)
+		if err != nil {
+			return nil, fmt.Errorf("tcmb: %s: %w", c.Code, err)
+		}
+		rates = append(rates, rate)
+	}
+	return rates, nil
+}
+
+// charsetReader accepts UTF-8 and the ISO-8859-9 (Latin-5) encoding used by
+// older bulletins.
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	switch strings.ToLower(charset) {
+	case "utf-8", "utf8":
+		return input, nil
+	case "iso-8859-9", "latin5", "windows-1254":
+		return latin5Reader(input)
+	}
+	return nil, fmt.Errorf("tcmb: unsupported charset %q", charset)
+}
+
+// latin5 maps the bytes where ISO-8859-9 differs from ISO-8859-1.
+var latin5 = map[byte]rune{
+	0xD0: 'Ğ', 0xDD: 'İ', 0xDE: 'Ş',
+	0xF0: 'ğ', 0xFD: 'ı', 0xFE: 'ş',
+}
+
+func latin5Reader(input io.Reader) (io.Reader, error) {
+	raw, err := io.ReadAll(input)
+	if err != nil {
+		return nil, err
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(raw))
+	for _, c := range raw {
+		r, ok := latin5[c]
+		if !ok {
+			r = rune(c)
+		}
+		sb.WriteRune(r)
+	}
+	return strings.NewReader(sb.String()), nil
+}
+
+var _ ports.ExchangeRateImporter = &FileImporter{}
@@ -0,0 +1,116 @@
+package tcmb_test
+
+import (
+	"carigo/internal/domain"
+	"carigo/internal/infrastructure/tcmb"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// bulletin is a cut-down today.xml: a rate per single unit, the yen quoted
+// per 100, a currency without a forex buying rate and the SDR line.
+const bulletin = `<?xml version="1.0" encoding="UTF-8"?>
+<Tarih_Date Tarih="16.10.2026" Date="10/16/2026" Bulten_No="2026/198">
+	<Currency CrossOrder="0" Kod="USD" CurrencyCode="USD">
+		<Unit>1</Unit>
+		<Isim>ABD DOLARI</Isim>
+		<CurrencyName>US DOLLAR</CurrencyName>
+		<ForexBuying>41.8412</ForexBuying>
+		<ForexSelling>41.9166</ForexSelling>
+	</Currency>
+	<Currency CrossOrder="11" Kod="JPY" CurrencyCode="JPY">
+		<Unit>100</Unit>
+		<Isim>JAPON YENİ</Isim>
+		<CurrencyName>JAPENESE YEN</CurrencyName>
+		<ForexBuying>27.65435</ForexBuying>
+		<ForexSelling>27.8375</ForexSelling>
+	</Currency>
+	<Currency CrossOrder="17" Kod="IRR" CurrencyCode="IRR">
+		<Unit>100</Unit>
+		<Isim>İRAN RİYALİ</Isim>
+		<CurrencyName>IRANIAN RIAL</CurrencyName>
+		<ForexBuying></ForexBuying>
+		<ForexSelling></ForexSelling>
+	</Currency>
+	<Currency CrossOrder="99" Kod="XDR" CurrencyCode="XDR">
+		<Unit>1</Unit>
+		<Isim>ÖZEL ÇEKME HAKKI (SDR)</Isim>
+		<CurrencyName>SPECIAL DRAWING RIGHT (SDR)</CurrencyName>
+		<ForexBuying>57.1234</ForexBuying>
+		<ForexSelling></ForexSelling>
+	</Currency>
+</Tarih_Date>`
+
+func TestParse(t *testing.T) {
+	rates, err := tcmb.Parse(strings.NewReader(bulletin))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	day := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	want := map[string]int64{
+		"USD": 41841200,
+		// 27.65435 per 100 yen, rounded half up to six places per yen.
+		"JPY": 276544,
+	}
+	if len(rates) != len(want) {
+		t.Fatalf("expected %d rates, got %d: %+v", len(want), len(rates), rates)
+	}
+	for _, r := range rates {
+		if r.To != "TRY" || !r.Date.Equal(day) || r.Source != domain.ExchangeRateSourceTCMB {
+			t.Errorf("unexpected rate %+v", r)
+		}
+		if r.Rate != want[r.From] {
+			t.Errorf("%s: expected %d, got %d", r.From, want[r.From], r.Rate)
+		}
+	}
+}
+
+func TestParse_Latin5(t *testing.T) {
+	// An archived bulletin in ISO-8859-9: "İNGİLİZ STERLİNİ".
+	file := "<?xml version=\"1.0\" encoding=\"ISO-8859-9\"?>\n" +
+		"<Tarih_Date Tarih=\"02.01.2026\"><Currency CurrencyCode=\"GBP\"><Unit>1</Unit>" +
+		"<Isim>\xddNG\xddL\xddZ STERL\xddN\xdd</Isim><ForexBuying>55.5</ForexBuying></Currency></Tarih_Date>"
+	rates, err := tcmb.Parse(strings.NewReader(file))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rates) != 1 || rates[0].From != "GBP" || rates[0].Rate != 55500000 {
+		t.Errorf("unexpected rates %+v", rates)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"unsupported charset": `<?xml version="1.0" encoding="KOI8-R"?><Tarih_Date Tarih="02.01.2026"></Tarih_Date>`,
+		"not a bulletin":      `<Document></Document>`,
+		"bad date":            `<Tarih_Date Tarih="2026-01-02"></Tarih_Date>`,
+		"bad rate": `<Tarih_Date Tarih="02.01.2026"><Currency CurrencyCode="USD"><Unit>1</Unit>` +
+			`<ForexBuying>41,8412</ForexBuying></Currency></Tarih_Date>`,
+	}
+	for name, file := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := tcmb.Parse(strings.NewReader(file)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestFileImporter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "today.xml")
+	if err := os.WriteFile(path, []byte(bulletin), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rates, err := tcmb.NewFileImporter().Import(context.Background(), path)
+	if err != nil || len(rates) != 2 {
+		t.Errorf("expected two rates, got %d, %v", len(rates), err)
+	}
+	if _, err := tcmb.NewFileImporter().Import(context.Background(), filepath.Join(t.TempDir(), "missing.xml")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
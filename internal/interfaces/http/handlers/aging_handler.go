@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"carigo/internal/application/dto"
+	"carigo/internal/application/usecases"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AgingHandler struct {
+	agingUC *usecases.GetAgingReportUseCase
+}
+
+func NewAgingHandler(uc *usecases.GetAgingReportUseCase) *AgingHandler {
+	return &AgingHandler{agingUC: uc}
+}
+
+// GetReport returns the aging report for the as_of day (YYYY-MM-DD) and the
+// bucket edges given as buckets=30,60,90.
+func (h *AgingHandler) GetReport(c *gin.Context) {
+	var req dto.AgingReportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.agingUC.Execute(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(statusFor(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *AgingHandler) ShowAging(c *gin.Context) {
+	var req dto.AgingReportRequest
+	errMsg := ""
+	if err := c.ShouldBindQuery(&req); err != nil {
+		errMsg = err.Error()
+		req = dto.AgingReportRequest{}
+	}
+
+	report, err := h.agingUC.Execute(c.Request.Context(), req)
+	if err != nil {
+		errMsg = err.Error()
+		report, err = h.agingUC.Execute(c.Request.Context(), dto.AgingReportRequest{AsOf: req.AsOf})
+		if err != nil {
+			report = &dto.AgingReport{}
+		}
+	}
+
+	c.HTML(http.StatusOK, "aging.html", gin.H{
+		"Title":      "Yaşlandırma",
+		"ActivePage": "aging",
+		"Report":     report,
+		"Buckets":    req.Buckets,
+		"Error":      errMsg,
+	})
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"carigo/internal/application/dto"
+	"carigo/internal/application/usecases"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AllocationHandler struct {
+	proposeUC *usecases.ProposeAllocationUseCase
+	confirmUC *usecases.ConfirmAllocationUseCase
+	unapplyUC *usecases.UnapplyAllocationUseCase
+}
+
+func NewAllocationHandler(propose *usecases.ProposeAllocationUseCase, confirm *usecases.ConfirmAllocationUseCase, unapply *usecases.UnapplyAllocationUseCase) *AllocationHandler {
+	return &AllocationHandler{
+		proposeUC: propose,
+		confirmUC: confirm,
+		unapplyUC: unapply,
+	}
+}
+
+func (h *AllocationHandler) ProposeAllocation(c *gin.Context) {
+	var req dto.RegisterPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.proposeUC.Execute(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(statusFor(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, res)
+}
+
+func (h *AllocationHandler) ConfirmAllocation(c *gin.Context) {
+	res, err := h.confirmUC.Execute(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(statusFor(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *AllocationHandler) UnapplyAllocation(c *gin.Context) {
+	var req dto.ReversalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.unapplyUC.Execute(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		c.JSON(statusFor(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"carigo/internal/application/dto"
+	"carigo/internal/application/usecases"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BankLineHandler struct {
+	importUC        *usecases.ImportBankStatementUseCase
+	listUC          *usecases.ListBankLinesUseCase
+	ignoreUC        *usecases.IgnoreBankLineUseCase
+	matchUC         *usecases.MatchBankLinesUseCase
+	postUC          *usecases.PostBankLineUseCase
+	reviewQueueUC   *usecases.GetBankReviewQueueUseCase
+	listCustomersUC *usecases.ListCustomersUseCase
+}
+
+func NewBankLineHandler(imp *usecases.ImportBankStatementUseCase, list *usecases.ListBankLinesUseCase, ignore *usecases.IgnoreBankLineUseCase, match *usecases.MatchBankLinesUseCase, post *usecases.PostBankLineUseCase, reviewQueue *usecases.GetBankReviewQueueUseCase, listCustomers *usecases.ListCustomersUseCase) *BankLineHandler {
+	return &BankLineHandler{
+		importUC:        imp,
+		listUC:          list,
+		ignoreUC:        ignore,
+		matchUC:         match,
+		postUC:          post,
+		reviewQueueUC:   reviewQueue,
+		listCustomersUC: listCustomers,
+	}
+}
+
+// ShowBankLines is the review queue of the bank lines the matching rules
+// could not post.
+func (h *BankLineHandler) ShowBankLines(c *gin.Context) {
+	queue, err := h.reviewQueueUC.Execute(c.Request.Context())
+	if err != nil {
+		queue = &dto.BankReviewQueue{}
+	}
+	customers, err := h.listCustomersUC.Execute(c.Request.Context())
+	if err != nil {
+		customers = []dto.CustomerDTO{}
+	}
+
+	c.HTML(http.StatusOK, "bank_lines.html", gin.H{
+		"Title":      "Banka Hareketleri",
+		"ActivePage": "bank",
+		"Queue":      queue,
+		"Customers":  customers,
+	})
+}
+
+// ImportBankStatement stages the incoming transfers of a statement uploaded
+// as the multipart field "file", then matches the open lines.
+func (h *BankLineHandler) ImportBankStatement(c *gin.Context) {
+	var req dto.ImportBankStatementRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+	req.File = file
+
+	res, err := h.importUC.Execute(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(statusFor(err), gin.H{"error": err.Error()})
+		return
+	}
+	// The lines are staged whether or not they can be matched now.
+	if matching, err := h.matchUC.Execute(c.Request.Context()); err == nil {
+		res.Matching = matching
+	}
+
+	c.JSON(http.StatusCreated, res)
+}
+
+func (h *BankLineHandler) MatchBankLines(c *gin.Context) {
+	res, err := h.matchUC.Execute(c.Request.Context())
+	if err != nil {
+		c.JSON(statusFor(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// PostBankLine registers a bank line as the payment of the customer chosen
+// by the clerk.
+func (h *BankLineHandler) PostBankLine(c *gin.Context) {
+	var req dto.PostBankLineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.postUC.Execute(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		c.JSON(statusFor(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, res)
+}
+
+func (h *BankLineHandler) GetReviewQueue(c *gin.Context) {
+	res, err := h.reviewQueueUC.Execute(c.Request.Context())
+	if err != nil {
+		c.JSON(statusFor(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// ListBankLines lists the staged bank lines, filtered by the "state" query
+// parameter if it is given.
+func (h *BankLineHandler) ListBankLines(c *gin.Context) {
+	res, err := h.listUC.Execute(c.Request.Context(), c.Query("state"))
+	if err != nil {
+		c.JSON(statusFor(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *BankLineHandler) IgnoreBankLine(c *gin.Context) {
+	res, err := h.ignoreUC.Execute(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(statusFor(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"carigo/internal/application/dto"
+	"carigo/internal/application/usecases"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CreditNoteHandler struct {
+	createCreditNoteUC *usecases.CreateCreditNoteUseCase
+}
+
+func NewCreditNoteHandler(create *usecases.CreateCreditNoteUseCase) *CreditNoteHandler {
+	return &CreditNoteHandler{
+		createCreditNoteUC: create,
+	}
+}
+
+func (h *CreditNoteHandler) CreateCreditNote(c *gin.Context) {
+	var req dto.CreateCreditNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.createCreditNoteUC.Execute(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(statusFor(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, res)
+}
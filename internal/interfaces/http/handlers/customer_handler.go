@@ -3,6 +3,8 @@ package handlers
 import (
 	"carigo/internal/application/dto"
 	"carigo/internal/application/usecases"
+	"carigo/internal/domain"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -12,13 +14,21 @@ type CustomerHandler struct {
 	createCustomerUC *usecases.CreateCustomerUseCase
 	listCustomersUC  *usecases.ListCustomersUseCase
 	getStatementUC   *usecases.GetCustomerStatementUseCase
+	exportUC         *usecases.ExportCustomerStatementUseCase
+	setInterestUC    *usecases.SetLateInterestRateUseCase
+	setTermsUC       *usecases.SetPaymentTermsUseCase
+	setLimitUC       *usecases.SetCreditLimitUseCase
 }
 
-func NewCustomerHandler(create *usecases.CreateCustomerUseCase, list *usecases.ListCustomersUseCase, statement *usecases.GetCustomerStatementUseCase) *CustomerHandler {
+func NewCustomerHandler(create *usecases.CreateCustomerUseCase, list *usecases.ListCustomersUseCase, statement *usecases.GetCustomerStatementUseCase, export *usecases.ExportCustomerStatementUseCase, setInterest *usecases.SetLateInterestRateUseCase, setTerms *usecases.SetPaymentTermsUseCase, setLimit *usecases.SetCreditLimitUseCase) *CustomerHandler {
 	return &CustomerHandler{
 		createCustomerUC: create,
 		listCustomersUC:  list,
 		getStatementUC:   statement,
+		exportUC:         export,
+		setInterestUC:    setInterest,
+		setTermsUC:       setTerms,
+		setLimitUC:       setLimit,
 	}
 }
 
@@ -44,13 +54,66 @@ func (h *CustomerHandler) CreateCustomer(c *gin.Context) {
 
 	res, err := h.createCustomerUC.Execute(c.Request.Context(), req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(statusFor(err), gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusCreated, res)
 }
 
+// SetLateInterestRate changes the late interest rate of a customer.
+func (h *CustomerHandler) SetLateInterestRate(c *gin.Context) {
+	var req dto.SetLateInterestRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.setInterestUC.Execute(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		c.JSON(statusFor(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// SetPaymentTerms changes the default payment terms of a customer.
+func (h *CustomerHandler) SetPaymentTerms(c *gin.Context) {
+	var req dto.PaymentTermsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.setTermsUC.Execute(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		c.JSON(statusFor(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// SetCreditLimit changes the credit limit of a customer in one currency.
+func (h *CustomerHandler) SetCreditLimit(c *gin.Context) {
+	var req dto.SetCreditLimitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.setLimitUC.Execute(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		c.JSON(statusFor(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// ShowCustomerStatement shows the statement for the period in the "from" and
+// "to" query parameters, or the whole history without them.
 func (h *CustomerHandler) ShowCustomerStatement(c *gin.Context) {
 	customerID := c.Param("id")
 	if customerID == "" {
@@ -58,7 +121,17 @@ func (h *CustomerHandler) ShowCustomerStatement(c *gin.Context) {
 		return
 	}
 
-	statement, err := h.getStatementUC.Execute(c.Request.Context(), customerID)
+	var req dto.StatementRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Redirect(http.StatusFound, "/customers/"+customerID)
+		return
+	}
+
+	statement, err := h.getStatementUC.Execute(c.Request.Context(), customerID, req)
+	if errors.Is(err, domain.ErrInvalidStatementPeriod) {
+		c.Redirect(http.StatusFound, "/customers/"+customerID)
+		return
+	}
 	if err != nil {
 		c.Redirect(http.StatusFound, "/customers")
 		return
@@ -70,3 +143,40 @@ func (h *CustomerHandler) ShowCustomerStatement(c *gin.Context) {
 		"Statement":  statement,
 	})
 }
+
+// ShowStatementPDF is the statement as a PDF file, limited like the page by
+// the "from" and "to" query parameters.
+func (h *CustomerHandler) ShowStatementPDF(c *gin.Context) {
+	var req dto.StatementRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	file, err := h.exportUC.Execute(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		c.JSON(statusFor(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", `inline; filename="ekstre-`+c.Param("id")+`.pdf"`)
+	c.Data(http.StatusOK, "application/pdf", file)
+}
+
+// GetCustomerStatement is the statement as JSON, limited like the page by
+// the "from" and "to" query parameters.
+func (h *CustomerHandler) GetCustomerStatement(c *gin.Context) {
+	var req dto.StatementRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.getStatementUC.Execute(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		c.JSON(statusFor(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
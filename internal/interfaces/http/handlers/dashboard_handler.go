@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"carigo/internal/application/usecases"
+	"carigo/internal/domain"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -25,15 +26,44 @@ func (h *DashboardHandler) ShowDashboard(c *gin.Context) {
 	formattedRevenue := float64(stats.TotalRevenue) / 100.0
 	formattedPending := float64(stats.PendingBalance) / 100.0
 
+	customerCredits := make([]map[string]interface{}, len(stats.CustomerCredits))
+	for i, cc := range stats.CustomerCredits {
+		customerCredits[i] = map[string]interface{}{
+			"CustomerID": string(cc.CustomerID),
+			"Amount":     cc.Amount.Format(domain.LocaleTR),
+		}
+	}
+
+	overdueAmounts := make([]string, len(stats.OverdueAmounts))
+	for i, m := range stats.OverdueAmounts {
+		overdueAmounts[i] = m.Format(domain.LocaleTR)
+	}
+
+	recentOverdue := make([]map[string]interface{}, len(stats.RecentOverdue))
+	for i, e := range stats.RecentOverdue {
+		recentOverdue[i] = map[string]interface{}{
+			"InvoiceID":  string(e.InvoiceID),
+			"CustomerID": string(e.CustomerID),
+			"Instalment": e.Instalment,
+			"DueDate":    e.DueDate.Format("02.01.2006"),
+			"Amount":     e.Amount.Format(domain.LocaleTR),
+			"DetectedAt": e.DetectedAt.Format("02.01.2006 15:04"),
+		}
+	}
+
 	c.HTML(http.StatusOK, "dashboard.html", gin.H{
 		"Title":      "Dashboard",
 		"ActivePage": "dashboard",
 		"Stats": map[string]interface{}{
-			"TotalCollected": formattedTotal,
-			"OpenInvoices":   stats.OpenInvoices,
-			"TotalRevenue":   formattedRevenue,
-			"TotalCustomers": stats.TotalCustomers,
-			"PendingBalance": formattedPending,
+			"TotalCollected":  formattedTotal,
+			"OpenInvoices":    stats.OpenInvoices,
+			"TotalRevenue":    formattedRevenue,
+			"TotalCustomers":  stats.TotalCustomers,
+			"PendingBalance":  formattedPending,
+			"OverdueInvoices": stats.OverdueInvoices,
 		},
+		"CustomerCredits": customerCredits,
+		"OverdueAmounts":  overdueAmounts,
+		"RecentOverdue":   recentOverdue,
 	})
 }
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"carigo/internal/domain"
+	"errors"
+	"net/http"
+	"os"
+)
+
+// statusFor maps domain errors to HTTP status codes. Anything the domain does
+// not know about is reported as an internal error.
+func statusFor(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrAllocationPlanNotFound),
+		errors.Is(err, domain.ErrCustomerNotFound),
+		errors.Is(err, domain.ErrInvoiceNotFound),
+		errors.Is(err, domain.ErrPaymentNotFound),
+		errors.Is(err, domain.ErrAllocationNotFound),
+		errors.Is(err, domain.ErrCreditNoteNotFound),
+		errors.Is(err, domain.ErrExchangeRateNotFound),
+		errors.Is(err, domain.ErrBankLineNotFound),
+		errors.Is(err, domain.ErrReconciliationNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrAllocationPlanStale),
+		errors.Is(err, domain.ErrInvoiceAlreadyPaid),
+		errors.Is(err, domain.ErrInvalidInvoiceState),
+		errors.Is(err, domain.ErrAllocationAlreadyReversed),
+		errors.Is(err, domain.ErrInvalidAllocationReversal),
+		errors.Is(err, domain.ErrPaymentAlreadyReversed),
+		errors.Is(err, domain.ErrPaymentHasAllocations),
+		errors.Is(err, domain.ErrInvoiceHasAllocations),
+		errors.Is(err, domain.ErrExchangeDifferenceInvoiced),
+		errors.Is(err, domain.ErrCreditLimitExceeded),
+		errors.Is(err, domain.ErrCustomerOverdue),
+		errors.Is(err, domain.ErrInvalidBankLineState),
+		errors.Is(err, domain.ErrInvalidReconciliationState):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrNegativeAmount),
+		errors.Is(err, domain.ErrInvalidAmount),
+		errors.Is(err, domain.ErrInvalidInvoiceLine),
+		errors.Is(err, domain.ErrInvalidVATRate),
+		errors.Is(err, domain.ErrInvalidWithholding),
+		errors.Is(err, domain.ErrInvalidInstalments),
+		errors.Is(err, domain.ErrInvalidAgingBuckets),
+		errors.Is(err, domain.ErrCurrencyMismatch),
+		errors.Is(err, domain.ErrInvalidCurrency),
+		errors.Is(err, domain.ErrOverPaymentNotAllowed),
+		errors.Is(err, domain.ErrInsufficientPaymentBalance),
+		errors.Is(err, domain.ErrUnknownAllocationStrategy),
+		errors.Is(err, domain.ErrCustomerMismatch),
+		errors.Is(err, domain.ErrInvalidExchangeRate),
+		errors.Is(err, domain.ErrNotForeignCurrency),
+		errors.Is(err, domain.ErrNoExchangeGain),
+		errors.Is(err, domain.ErrInvalidInterestRate),
+		errors.Is(err, domain.ErrInvalidPaymentTerms),
+		errors.Is(err, domain.ErrDueDateRequired),
+		errors.Is(err, domain.ErrInvalidCreditLimit),
+		errors.Is(err, domain.ErrNoLateInterest),
+		errors.Is(err, domain.ErrInvalidBankStatement),
+		errors.Is(err, domain.ErrUnknownBankStatementFormat),
+		errors.Is(err, domain.ErrInvalidBankMatchRule),
+		errors.Is(err, domain.ErrInvalidReconciliation),
+		errors.Is(err, domain.ErrNoReconciliationDifference),
+		errors.Is(err, domain.ErrInvalidStatementPeriod),
+		errors.Is(err, os.ErrNotExist):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"carigo/internal/application/dto"
+	"carigo/internal/application/usecases"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExchangeDifferenceHandler struct {
+	calculateUC *usecases.CalculateExchangeDifferencesUseCase
+}
+
+func NewExchangeDifferenceHandler(calculate *usecases.CalculateExchangeDifferencesUseCase) *ExchangeDifferenceHandler {
+	return &ExchangeDifferenceHandler{calculateUC: calculate}
+}
+
+// GetReport lists realized exchange differences, filtered by customer_id and
+// the from/to settlement days (YYYY-MM-DD). It never issues invoices.
+func (h *ExchangeDifferenceHandler) GetReport(c *gin.Context) {
+	var req dto.ExchangeDifferenceRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.IssueInvoices = false
+
+	res, err := h.calculateUC.Execute(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(statusFor(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// IssueInvoices bills the uninvoiced gains in the selection with kur farkı invoices.
+func (h *ExchangeDifferenceHandler) IssueInvoices(c *gin.Context) {
+	var req dto.ExchangeDifferenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.IssueInvoices = true
+
+	res, err := h.calculateUC.Execute(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(statusFor(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, res)
+}
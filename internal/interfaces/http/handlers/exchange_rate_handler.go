@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"carigo/internal/application/dto"
+	"carigo/internal/application/usecases"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExchangeRateHandler struct {
+	createRateUC  *usecases.CreateExchangeRateUseCase
+	listRatesUC   *usecases.ListExchangeRatesUseCase
+	importRatesUC *usecases.ImportExchangeRatesUseCase
+}
+
+func NewExchangeRateHandler(create *usecases.CreateExchangeRateUseCase, list *usecases.ListExchangeRatesUseCase, imp *usecases.ImportExchangeRatesUseCase) *ExchangeRateHandler {
+	return &ExchangeRateHandler{
+		createRateUC:  create,
+		listRatesUC:   list,
+		importRatesUC: imp,
+	}
+}
+
+func (h *ExchangeRateHandler) CreateExchangeRate(c *gin.Context) {
+	var req dto.CreateExchangeRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.createRateUC.Execute(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(statusFor(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, res)
+}
+
+func (h *ExchangeRateHandler) ListExchangeRates(c *gin.Context) {
+	res, err := h.listRatesUC.Execute(c.Request.Context())
+	if err != nil {
+		c.JSON(statusFor(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *ExchangeRateHandler) ImportExchangeRates(c *gin.Context) {
+	var req dto.ImportExchangeRatesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.importRatesUC.Execute(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(statusFor(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, res)
+}
@@ -10,24 +10,31 @@ import (
 
 type InvoiceHandler struct {
 	createInvoiceUC *usecases.CreateInvoiceUseCase
+	voidInvoiceUC   *usecases.VoidInvoiceUseCase
 	listInvoicesUC  *usecases.ListInvoicesUseCase
 	listCustomersUC *usecases.ListCustomersUseCase
+	exportInvoiceUC *usecases.ExportInvoiceUseCase
 }
 
-func NewInvoiceHandler(createUC *usecases.CreateInvoiceUseCase, listUC *usecases.ListInvoicesUseCase, listCustUC *usecases.ListCustomersUseCase) *InvoiceHandler {
+func NewInvoiceHandler(createUC *usecases.CreateInvoiceUseCase, voidUC *usecases.VoidInvoiceUseCase, listUC *usecases.ListInvoicesUseCase, listCustUC *usecases.ListCustomersUseCase, exportUC *usecases.ExportInvoiceUseCase) *InvoiceHandler {
 	return &InvoiceHandler{
 		createInvoiceUC: createUC,
+		voidInvoiceUC:   voidUC,
 		listInvoicesUC:  listUC,
 		listCustomersUC: listCustUC,
+		exportInvoiceUC: exportUC,
 	}
 }
 
 func (h *InvoiceHandler) ShowInvoices(c *gin.Context) {
-	invoices, err := h.listInvoicesUC.Execute(c.Request.Context())
+	var filter dto.InvoiceFilter
+	_ = c.ShouldBindQuery(&filter)
+
+	invoices, err := h.listInvoicesUC.Execute(c.Request.Context(), filter)
 	if err != nil {
 		invoices = []dto.InvoiceDTO{}
 	}
-	
+
 	customers, err := h.listCustomersUC.Execute(c.Request.Context())
 	if err != nil {
 		customers = []dto.CustomerDTO{}
@@ -38,9 +45,27 @@ func (h *InvoiceHandler) ShowInvoices(c *gin.Context) {
 		"ActivePage": "invoices",
 		"Invoices":   invoices,
 		"Customers":  customers,
+		"Filter":     filter,
 	})
 }
 
+// ListInvoices returns all invoices, or with overdue=true only the overdue ones.
+func (h *InvoiceHandler) ListInvoices(c *gin.Context) {
+	var filter dto.InvoiceFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	invoices, err := h.listInvoicesUC.Execute(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(statusFor(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, invoices)
+}
+
 func (h *InvoiceHandler) CreateInvoice(c *gin.Context) {
 	var req dto.CreateInvoiceRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -50,9 +75,37 @@ func (h *InvoiceHandler) CreateInvoice(c *gin.Context) {
 
 	res, err := h.createInvoiceUC.Execute(c.Request.Context(), req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(statusFor(err), gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusCreated, res)
 }
+
+func (h *InvoiceHandler) VoidInvoice(c *gin.Context) {
+	var req dto.ReversalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.voidInvoiceUC.Execute(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		c.JSON(statusFor(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// ShowInvoicePDF is a printable copy of the invoice as a PDF file.
+func (h *InvoiceHandler) ShowInvoicePDF(c *gin.Context) {
+	file, err := h.exportInvoiceUC.Execute(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(statusFor(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", `inline; filename="`+c.Param("id")+`.pdf"`)
+	c.Data(http.StatusOK, "application/pdf", file)
+}
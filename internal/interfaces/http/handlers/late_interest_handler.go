@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"carigo/internal/application/dto"
+	"carigo/internal/application/usecases"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LateInterestHandler struct {
+	calculateUC *usecases.CalculateLateInterestUseCase
+}
+
+func NewLateInterestHandler(calculate *usecases.CalculateLateInterestUseCase) *LateInterestHandler {
+	return &LateInterestHandler{calculateUC: calculate}
+}
+
+// GetReport lists the late interest accrued up to as_of (YYYY-MM-DD, default
+// today), filtered by customer_id. It never issues invoices.
+func (h *LateInterestHandler) GetReport(c *gin.Context) {
+	var req dto.LateInterestRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.IssueInvoices = false
+
+	res, err := h.calculateUC.Execute(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(statusFor(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// IssueInvoices bills the late interest in the selection with vade farkı invoices.
+func (h *LateInterestHandler) IssueInvoices(c *gin.Context) {
+	var req dto.LateInterestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.IssueInvoices = true
+
+	res, err := h.calculateUC.Execute(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(statusFor(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, res)
+}
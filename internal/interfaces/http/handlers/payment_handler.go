@@ -10,15 +10,28 @@ import (
 
 type PaymentHandler struct {
 	registerPaymentUC *usecases.RegisterPaymentUseCase
+	allocateUC        *usecases.AllocatePaymentManuallyUseCase
+	reverseUC         *usecases.ReversePaymentUseCase
 	listPaymentsUC    *usecases.ListPaymentsUseCase
 	listCustomersUC   *usecases.ListCustomersUseCase
+	listInvoicesUC    *usecases.ListInvoicesUseCase
 }
 
-func NewPaymentHandler(registerUC *usecases.RegisterPaymentUseCase, listUC *usecases.ListPaymentsUseCase, listCustUC *usecases.ListCustomersUseCase) *PaymentHandler {
+func NewPaymentHandler(
+	registerUC *usecases.RegisterPaymentUseCase,
+	allocateUC *usecases.AllocatePaymentManuallyUseCase,
+	reverseUC *usecases.ReversePaymentUseCase,
+	listUC *usecases.ListPaymentsUseCase,
+	listCustUC *usecases.ListCustomersUseCase,
+	listInvUC *usecases.ListInvoicesUseCase,
+) *PaymentHandler {
 	return &PaymentHandler{
 		registerPaymentUC: registerUC,
+		allocateUC:        allocateUC,
+		reverseUC:         reverseUC,
 		listPaymentsUC:    listUC,
 		listCustomersUC:   listCustUC,
+		listInvoicesUC:    listInvUC,
 	}
 }
 
@@ -33,11 +46,17 @@ func (h *PaymentHandler) ShowPayments(c *gin.Context) {
 		customers = []dto.CustomerDTO{}
 	}
 
+	invoices, err := h.listInvoicesUC.Execute(c.Request.Context(), dto.InvoiceFilter{})
+	if err != nil {
+		invoices = []dto.InvoiceDTO{}
+	}
+
 	c.HTML(http.StatusOK, "payments.html", gin.H{
 		"Title":      "Ödemeler",
 		"ActivePage": "payments",
 		"Payments":   payments,
 		"Customers":  customers,
+		"Invoices":   invoices,
 	})
 }
 
@@ -50,7 +69,39 @@ func (h *PaymentHandler) RegisterPayment(c *gin.Context) {
 
 	res, err := h.registerPaymentUC.Execute(c.Request.Context(), req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(statusFor(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *PaymentHandler) AllocatePayment(c *gin.Context) {
+	var req dto.ManualAllocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.allocateUC.Execute(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		c.JSON(statusFor(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *PaymentHandler) ReversePayment(c *gin.Context) {
+	var req dto.ReversalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.reverseUC.Execute(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		c.JSON(statusFor(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"carigo/internal/application/dto"
+	"carigo/internal/application/usecases"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReconciliationHandler struct {
+	createUC   *usecases.CreateReconciliationUseCase
+	respondUC  *usecases.RespondReconciliationUseCase
+	listUC     *usecases.ListReconciliationsUseCase
+	documentUC *usecases.GetReconciliationDocumentUseCase
+}
+
+func NewReconciliationHandler(create *usecases.CreateReconciliationUseCase, respond *usecases.RespondReconciliationUseCase, list *usecases.ListReconciliationsUseCase, document *usecases.GetReconciliationDocumentUseCase) *ReconciliationHandler {
+	return &ReconciliationHandler{
+		createUC:   create,
+		respondUC:  respond,
+		listUC:     list,
+		documentUC: document,
+	}
+}
+
+// ShowReconciliations lists the balance confirmations, with the differences
+// of the disputed ones to investigate.
+func (h *ReconciliationHandler) ShowReconciliations(c *gin.Context) {
+	reconciliations, err := h.listUC.Execute(c.Request.Context(), "")
+	if err != nil {
+		reconciliations = []dto.ReconciliationDTO{}
+	}
+
+	var disputed []dto.ReconciliationDTO
+	for _, r := range reconciliations {
+		if len(r.Differences) > 0 {
+			disputed = append(disputed, r)
+		}
+	}
+
+	c.HTML(http.StatusOK, "reconciliations.html", gin.H{
+		"Title":           "Mutabakatlar",
+		"ActivePage":      "reconciliations",
+		"Reconciliations": reconciliations,
+		"Disputed":        disputed,
+	})
+}
+
+// ShowReconciliationDocument is the printable confirmation letter, where the
+// customer's answer is also recorded.
+func (h *ReconciliationHandler) ShowReconciliationDocument(c *gin.Context) {
+	document, err := h.documentUC.Execute(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Redirect(http.StatusFound, "/reconciliations")
+		return
+	}
+
+	c.HTML(http.StatusOK, "reconciliation_document.html", gin.H{
+		"Title":      "Cari Hesap Mutabakatı",
+		"ActivePage": "reconciliations",
+		"Document":   document,
+	})
+}
+
+// CreateReconciliation snapshots the balances of the customer in the path.
+func (h *ReconciliationHandler) CreateReconciliation(c *gin.Context) {
+	var req dto.CreateReconciliationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.createUC.Execute(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		c.JSON(statusFor(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, res)
+}
+
+// ListReconciliations lists the reconciliations, filtered by the "status"
+// query parameter if it is given.
+func (h *ReconciliationHandler) ListReconciliations(c *gin.Context) {
+	res, err := h.listUC.Execute(c.Request.Context(), c.Query("status"))
+	if err != nil {
+		c.JSON(statusFor(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *ReconciliationHandler) GetReconciliationDocument(c *gin.Context) {
+	res, err := h.documentUC.Execute(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(statusFor(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *ReconciliationHandler) RespondReconciliation(c *gin.Context) {
+	var req dto.RespondReconciliationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.respondUC.Execute(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		c.JSON(statusFor(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"carigo/internal/domain"
+	"html/template"
+	"strings"
+)
+
+// TemplateFuncs are the helpers page templates use to show the exact decimal
+// amounts carried by DTOs.
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"money":    formatMoney,
+		"positive": isPositive,
+	}
+}
+
+// formatMoney renders a decimal amount such as "-1234.56" in Turkish style,
+// "-1.234,56 TRY". Anything it cannot read is shown as is.
+func formatMoney(amount, currency string) string {
+	sign := ""
+	if strings.HasPrefix(amount, "-") {
+		sign, amount = "-", amount[1:]
+	}
+	m, err := domain.ParseMoney(amount, currency)
+	if err != nil {
+		return sign + amount + " " + currency
+	}
+	return sign + m.Format(domain.LocaleTR)
+}
+
+// isPositive reports whether a decimal amount is greater than zero.
+func isPositive(amount string) bool {
+	return !strings.HasPrefix(amount, "-") && strings.Trim(amount, "0.") != ""
+}